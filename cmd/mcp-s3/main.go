@@ -34,7 +34,7 @@ func run() error {
 	}()
 
 	// Create the server with defaults from environment
-	mcpServer, toolkit, err := mcps3.NewWithDefaults()
+	mcpServer, toolkit, err := mcps3.NewWithDefaultsContext(ctx)
 	if err != nil {
 		return fmt.Errorf("creating server: %w", err)
 	}
@@ -2,10 +2,15 @@
 package server
 
 import (
+	"cmp"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -29,6 +34,36 @@ type Config struct {
 	// Multi-server configuration
 	MultiConfig *multiserver.MultiConfig
 
+	// ConfigFile is a YAML or JSON multi-connection file. When set, it
+	// replaces MultiConfig.
+	ConfigFile string
+
+	// WatchConfig reloads ConfigFile when it changes.
+	WatchConfig bool
+
+	// DiscoverProfiles adds a connection for each profile in the AWS shared
+	// config file whose name matches ProfileFilter (empty = all profiles).
+	// Connections configured explicitly take precedence.
+	DiscoverProfiles bool
+	ProfileFilter    []string
+
+	// Keyfile is an encrypted keyfile that "keyfile:NAME" credential
+	// references resolve against, decrypted with the base64 KeyfileKey.
+	Keyfile    string
+	KeyfileKey string
+
+	// ClientIdleTTL evicts multi-connection clients unused for this long.
+	// Zero keeps clients until their connection is removed.
+	ClientIdleTTL time.Duration
+
+	// MaxClients caps the number of cached multi-connection clients,
+	// evicting the least recently used. Zero means no cap.
+	MaxClients int
+
+	// SecretResolvers resolve additional secret reference forms in
+	// multi-connection credentials.
+	SecretResolvers []multiserver.SecretResolver
+
 	// Logger
 	Logger *slog.Logger
 }
@@ -39,6 +74,7 @@ func DefaultConfig() Config {
 		ClientConfig: nil, // Will use FromEnv()
 		ExtConfig:    extensions.DefaultConfig(),
 		MultiConfig:  nil,
+		WatchConfig:  true,
 		Logger:       slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})),
 	}
 }
@@ -57,33 +93,121 @@ func FromEnv() Config {
 	}
 	cfg.MultiConfig = multiCfg
 
+	cfg.ConfigFile = os.Getenv("S3_CONFIG_FILE")
+	if watch, err := strconv.ParseBool(os.Getenv("S3_CONFIG_WATCH")); err == nil {
+		cfg.WatchConfig = watch
+	}
+	if discover, err := strconv.ParseBool(os.Getenv("S3_DISCOVER_PROFILES")); err == nil {
+		cfg.DiscoverProfiles = discover
+	}
+	for _, pattern := range strings.Split(os.Getenv("S3_PROFILE_FILTER"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			cfg.ProfileFilter = append(cfg.ProfileFilter, pattern)
+		}
+	}
+	cfg.Keyfile = os.Getenv("S3_KEYFILE")
+	cfg.KeyfileKey = os.Getenv("S3_KEYFILE_KEY")
+	if ttl, err := time.ParseDuration(os.Getenv("S3_CLIENT_IDLE_TTL")); err == nil && ttl > 0 {
+		cfg.ClientIdleTTL = ttl
+	}
+	if n, err := strconv.Atoi(os.Getenv("S3_MAX_CLIENTS")); err == nil && n > 0 {
+		cfg.MaxClients = n
+	}
+
 	return cfg
 }
 
 // New creates a new MCP S3 server with the given configuration.
 func New(cfg Config) (*mcp.Server, *tools.Toolkit, error) {
+	return NewWithContext(context.Background(), cfg)
+}
+
+// NewWithContext creates a new MCP S3 server with the given configuration.
+// When cfg.ConfigFile is set and cfg.WatchConfig is true, the file is watched
+// until ctx is done and connection changes are applied without a restart.
+func NewWithContext(ctx context.Context, cfg Config) (*mcp.Server, *tools.Toolkit, error) {
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    "mcp-s3",
 		Version: Version,
 	}, nil)
 
+	if cfg.ConfigFile != "" {
+		multiCfg, err := multiserver.LoadFile(cfg.ConfigFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load config file: %w", err)
+		}
+		if err := multiCfg.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("invalid config file %s: %w", cfg.ConfigFile, err)
+		}
+		cfg.MultiConfig = multiCfg
+	}
+	if cfg.DiscoverProfiles {
+		if cfg.MultiConfig == nil {
+			cfg.MultiConfig = envMultiConfig(cfg)
+		}
+		multiCfg, err := profileDiscovery(cfg).Merge(cfg.MultiConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to discover AWS profiles: %w", err)
+		}
+		cfg.MultiConfig = multiCfg
+	}
+
 	s3Client, manager, err := createS3Client(cfg)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	opts := buildToolkitOptions(cfg, s3Client, manager)
+	// With a manager, every connection (including the default) is resolved
+	// through it, so reloaded settings take effect on the next call.
+	if manager != nil {
+		s3Client = nil
+	}
 	toolkit := tools.NewToolkit(s3Client, opts...)
 	toolkit.RegisterAll(mcpServer)
 
+	if manager != nil && cfg.ConfigFile != "" && cfg.WatchConfig {
+		startConfigWatcher(ctx, cfg, manager)
+	}
+	if manager != nil && cfg.ClientIdleTTL > 0 {
+		go manager.RunEviction(ctx)
+	}
+
 	return mcpServer, toolkit, nil
 }
 
+// startConfigWatcher reloads the manager from cfg.ConfigFile until ctx is
+// done.
+func startConfigWatcher(ctx context.Context, cfg Config, manager *multiserver.Manager) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	opts := []multiserver.WatcherOption{multiserver.WithWatchLogger(logger)}
+	if cfg.DiscoverProfiles {
+		opts = append(opts, multiserver.WithProfileDiscovery(profileDiscovery(cfg)))
+	}
+	watcher := multiserver.NewWatcher(manager, cfg.ConfigFile, opts...)
+	go func() {
+		if err := watcher.Run(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("config watcher stopped", "path", cfg.ConfigFile, "error", err)
+		}
+	}()
+}
+
 func createS3Client(cfg Config) (tools.S3Client, *multiserver.Manager, error) {
 	ctx := context.Background()
 
 	if cfg.MultiConfig != nil && len(cfg.MultiConfig.Connections) > 0 {
-		manager := multiserver.NewManager(cfg.MultiConfig)
+		resolvers, err := secretResolvers(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		manager := multiserver.NewManager(cfg.MultiConfig,
+			multiserver.WithSecretResolvers(resolvers...),
+			multiserver.WithIdleTTL(cfg.ClientIdleTTL),
+			multiserver.WithMaxClients(cfg.MaxClients),
+		)
 		s3Client, err := manager.GetDefaultClient(ctx)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create default S3 client: %w", err)
@@ -104,6 +228,46 @@ func createS3Client(cfg Config) (tools.S3Client, *multiserver.Manager, error) {
 	return s3Client, nil, nil
 }
 
+// envMultiConfig returns a multi-connection configuration holding only the
+// primary connection from cfg.ClientConfig, or from the environment when it
+// is nil. The connection is named "default" unless S3_CONNECTION_NAME is set.
+func envMultiConfig(cfg Config) *multiserver.MultiConfig {
+	clientCfg := cfg.ClientConfig
+	if clientCfg == nil {
+		envCfg := client.FromEnv()
+		clientCfg = &envCfg
+	}
+	conn := multiserver.FromClientConfig(clientCfg)
+	conn.Name = cmp.Or(conn.Name, "default")
+	return &multiserver.MultiConfig{
+		DefaultConnection: conn.Name,
+		Connections:       []multiserver.ConnectionConfig{conn},
+	}
+}
+
+// profileDiscovery returns the AWS profile discovery settings for cfg.
+func profileDiscovery(cfg Config) multiserver.ProfileDiscovery {
+	return multiserver.ProfileDiscovery{Patterns: cfg.ProfileFilter}
+}
+
+// secretResolvers returns the configured resolvers, adding a keyfile
+// resolver when cfg.Keyfile is set.
+func secretResolvers(cfg Config) ([]multiserver.SecretResolver, error) {
+	resolvers := append([]multiserver.SecretResolver(nil), cfg.SecretResolvers...)
+	if cfg.Keyfile == "" {
+		return resolvers, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(cfg.KeyfileKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keyfile key: %w", err)
+	}
+	keyfile, err := multiserver.NewKeyfileSecretResolver(cfg.Keyfile, key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keyfile key: %w", err)
+	}
+	return append(resolvers, keyfile), nil
+}
+
 func buildToolkitOptions(cfg Config, s3Client tools.S3Client, manager *multiserver.Manager) []tools.Option {
 	opts := []tools.Option{
 		tools.WithReadOnly(cfg.ExtConfig.ReadOnly),
@@ -113,7 +277,7 @@ func buildToolkitOptions(cfg Config, s3Client tools.S3Client, manager *multiserv
 	}
 
 	opts = appendConnectionOptions(opts, s3Client, manager)
-	opts = appendExtensionOptions(opts, cfg)
+	opts = appendExtensionOptions(opts, cfg, manager)
 	return opts
 }
 
@@ -123,6 +287,7 @@ func appendConnectionOptions(opts []tools.Option, s3Client tools.S3Client, manag
 			tools.WithClientProvider(manager.ClientProvider()),
 			tools.WithDefaultConnection(manager.DefaultConnectionName()),
 			tools.WithConnectionManager(manager),
+			tools.WithHealthChecker(manager),
 		)
 	} else if s3Client != nil && s3Client.ConnectionName() != "" {
 		opts = append(opts, tools.WithDefaultConnection(s3Client.ConnectionName()))
@@ -130,14 +295,18 @@ func appendConnectionOptions(opts []tools.Option, s3Client tools.S3Client, manag
 	return opts
 }
 
-func appendExtensionOptions(opts []tools.Option, cfg Config) []tools.Option {
+func appendExtensionOptions(opts []tools.Option, cfg Config, manager *multiserver.Manager) []tools.Option {
 	if cfg.ExtConfig.ReadOnly {
 		opts = append(opts, tools.WithInterceptor(extensions.NewReadOnlyInterceptor(true)))
 	}
+	opts = appendAdminOptions(opts, cfg, manager)
 	if cfg.ExtConfig.SizeLimit {
-		opts = append(opts, tools.WithInterceptor(
-			extensions.NewSizeLimitInterceptor(cfg.ExtConfig.MaxGetSize, cfg.ExtConfig.MaxPutSize),
-		))
+		sizeLimit := extensions.NewSizeLimitInterceptor(cfg.ExtConfig.MaxGetSize, cfg.ExtConfig.MaxPutSize)
+		// Connections with their own size limits override the server-wide ones.
+		if manager != nil {
+			sizeLimit.WithConnectionLimits(manager.SizeLimits)
+		}
+		opts = append(opts, tools.WithInterceptor(sizeLimit))
 	}
 	if cfg.ExtConfig.Logging && cfg.Logger != nil {
 		opts = append(opts, tools.WithMiddleware(extensions.NewLoggingMiddleware(cfg.Logger)))
@@ -151,10 +320,54 @@ func appendExtensionOptions(opts []tools.Option, cfg Config) []tools.Option {
 			extensions.NewPrefixACLInterceptor(cfg.ExtConfig.AllowedPrefixes, cfg.ExtConfig.DeniedPrefixes),
 		))
 	}
+	// Rate limiting runs before quotas so throttled calls are not charged.
+	if cfg.ExtConfig.RateLimit {
+		opts = append(opts, tools.WithInterceptor(extensions.NewRateLimitInterceptor(cfg.ExtConfig.RateLimits)))
+	}
+	if cfg.ExtConfig.Quota {
+		tracker := extensions.NewQuotaTracker(cfg.ExtConfig.Quotas)
+		opts = append(opts,
+			tools.WithInterceptor(tracker),
+			tools.WithMiddleware(tracker),
+			tools.WithQuotaReporter(tracker),
+		)
+	}
 	return opts
 }
 
+// appendAdminOptions enables the connection admin tools behind the admin
+// policy. They need a manager, so single-connection servers never get them,
+// and allowed principals, so they are never open to every caller.
+func appendAdminOptions(opts []tools.Option, cfg Config, manager *multiserver.Manager) []tools.Option {
+	if !cfg.ExtConfig.Admin {
+		return opts
+	}
+	if len(cfg.ExtConfig.AdminPrincipals) == 0 {
+		if cfg.Logger != nil {
+			cfg.Logger.Warn("connection admin tools need MCP_S3_ADMIN_PRINCIPALS; not enabled")
+		}
+		return opts
+	}
+	if manager == nil {
+		if cfg.Logger != nil {
+			cfg.Logger.Warn("connection admin tools need a multi-connection configuration; not enabled")
+		}
+		return opts
+	}
+	admin := multiserver.NewAdmin(manager, multiserver.WithPersistFile(cfg.ConfigFile))
+	return append(opts,
+		tools.WithConnectionAdmin(admin),
+		tools.WithInterceptor(extensions.NewAdminPolicyInterceptor(cfg.ExtConfig.AdminPrincipals)),
+	)
+}
+
 // NewWithDefaults creates a new MCP S3 server with default configuration from environment.
 func NewWithDefaults() (*mcp.Server, *tools.Toolkit, error) {
 	return New(FromEnv())
 }
+
+// NewWithDefaultsContext is NewWithDefaults with a context that bounds the
+// config file watcher.
+func NewWithDefaultsContext(ctx context.Context) (*mcp.Server, *tools.Toolkit, error) {
+	return NewWithContext(ctx, FromEnv())
+}
@@ -2,11 +2,20 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
 	"github.com/txn2/mcp-s3/pkg/client"
 	"github.com/txn2/mcp-s3/pkg/extensions"
+	"github.com/txn2/mcp-s3/pkg/multiserver"
 	"github.com/txn2/mcp-s3/pkg/tools"
 )
 
@@ -41,6 +50,72 @@ func TestFromEnv(t *testing.T) {
 	}
 }
 
+func TestFromEnv_ClientLifecycle(t *testing.T) {
+	t.Setenv("S3_CLIENT_IDLE_TTL", "15m")
+	t.Setenv("S3_MAX_CLIENTS", "20")
+
+	cfg := FromEnv()
+	if cfg.ClientIdleTTL != 15*time.Minute {
+		t.Errorf("ClientIdleTTL = %v, want 15m", cfg.ClientIdleTTL)
+	}
+	if cfg.MaxClients != 20 {
+		t.Errorf("MaxClients = %d, want 20", cfg.MaxClients)
+	}
+}
+
+func TestFromEnv_ProfileDiscovery(t *testing.T) {
+	t.Setenv("S3_DISCOVER_PROFILES", "true")
+	t.Setenv("S3_PROFILE_FILTER", "dev-*, prod")
+
+	cfg := FromEnv()
+	if !cfg.DiscoverProfiles {
+		t.Error("expected DiscoverProfiles to be set")
+	}
+	if want := []string{"dev-*", "prod"}; !reflect.DeepEqual(cfg.ProfileFilter, want) {
+		t.Errorf("ProfileFilter = %v, want %v", cfg.ProfileFilter, want)
+	}
+}
+
+func TestNew_DiscoverProfiles(t *testing.T) {
+	awsConfig := filepath.Join(t.TempDir(), "config")
+	data := "[default]\nregion = us-east-1\n\n[profile dev]\nregion = us-west-2\n\n[profile prod]\nregion = eu-west-1\n"
+	if err := os.WriteFile(awsConfig, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", awsConfig)
+	t.Setenv("AWS_PROFILE", "")
+
+	cfg := DefaultConfig()
+	cfg.DiscoverProfiles = true
+	cfg.ProfileFilter = []string{"default", "dev"}
+	_, toolkit, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := toolkit.ListConnections(); !reflect.DeepEqual(got, []string{"default", "dev"}) {
+		t.Errorf("ListConnections() = %v, want [default dev]", got)
+	}
+
+	// The environment's connection stays the default next to the profiles.
+	t.Setenv("S3_CONNECTION_NAME", "minio")
+	t.Setenv("S3_ENDPOINT", "http://localhost:9000")
+	cfg.ClientConfig = nil
+	_, toolkit, err = New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := toolkit.ListConnections(); !reflect.DeepEqual(got, []string{"minio", "default", "dev"}) {
+		t.Errorf("ListConnections() = %v, want [minio default dev]", got)
+	}
+	s3Client, err := toolkit.GetClient("")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if s3Client.Config().Endpoint != "http://localhost:9000" {
+		t.Errorf("default connection endpoint = %q", s3Client.Config().Endpoint)
+	}
+}
+
 func TestNew_WithConfig(t *testing.T) {
 	// Skip if we don't have a valid endpoint configured
 	clientCfg := &client.Config{
@@ -139,7 +214,7 @@ func TestAppendExtensionOptions_AllEnabled(t *testing.T) {
 	}
 
 	opts := []tools.Option{}
-	result := appendExtensionOptions(opts, cfg)
+	result := appendExtensionOptions(opts, cfg, nil)
 
 	// Should add 5 options: readonly, sizelimit, logging, audit, prefixacl
 	if len(result) != 5 {
@@ -159,7 +234,7 @@ func TestAppendExtensionOptions_NoneEnabled(t *testing.T) {
 	}
 
 	opts := []tools.Option{}
-	result := appendExtensionOptions(opts, cfg)
+	result := appendExtensionOptions(opts, cfg, nil)
 
 	// Should add 0 options
 	if len(result) != 0 {
@@ -167,6 +242,37 @@ func TestAppendExtensionOptions_NoneEnabled(t *testing.T) {
 	}
 }
 
+func TestAppendExtensionOptions_Quota(t *testing.T) {
+	cfg := Config{
+		ExtConfig: extensions.Config{
+			Quota:  true,
+			Quotas: extensions.QuotaConfig{Session: extensions.QuotaLimits{Requests: 10}},
+		},
+	}
+
+	result := appendExtensionOptions([]tools.Option{}, cfg, nil)
+
+	// Should add interceptor, middleware, and quota reporter options
+	if len(result) != 3 {
+		t.Errorf("expected 3 options, got %d", len(result))
+	}
+}
+
+func TestAppendExtensionOptions_RateLimit(t *testing.T) {
+	cfg := Config{
+		ExtConfig: extensions.Config{
+			RateLimit:  true,
+			RateLimits: extensions.RateLimitConfig{DefaultTool: extensions.RateLimit{Rate: 5}},
+		},
+	}
+
+	result := appendExtensionOptions([]tools.Option{}, cfg, nil)
+
+	if len(result) != 1 {
+		t.Errorf("expected 1 option, got %d", len(result))
+	}
+}
+
 func TestAppendExtensionOptions_LoggingWithNilLogger(t *testing.T) {
 	cfg := Config{
 		ExtConfig: extensions.Config{
@@ -176,7 +282,7 @@ func TestAppendExtensionOptions_LoggingWithNilLogger(t *testing.T) {
 	}
 
 	opts := []tools.Option{}
-	result := appendExtensionOptions(opts, cfg)
+	result := appendExtensionOptions(opts, cfg, nil)
 
 	// Should not add logging middleware when logger is nil
 	if len(result) != 0 {
@@ -200,6 +306,9 @@ func (m *mockS3Client) ListObjects(_ context.Context, _, _, _ string, _ int32, _
 func (m *mockS3Client) GetObject(_ context.Context, _, _ string) (*client.ObjectContent, error) {
 	return nil, nil
 }
+func (m *mockS3Client) GetObjectRange(_ context.Context, _, _ string, _, _ int64) (*client.ObjectContent, error) {
+	return nil, nil
+}
 func (m *mockS3Client) GetObjectMetadata(_ context.Context, _, _ string) (*client.ObjectMetadata, error) {
 	return nil, nil
 }
@@ -312,3 +421,141 @@ func TestVersion(t *testing.T) {
 		t.Error("Version should not be empty")
 	}
 }
+
+func TestNewWithContext_ConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "connections.yaml")
+	writeConfig := func(names ...string) {
+		t.Helper()
+		var b strings.Builder
+		b.WriteString("default_connection: primary\nconnections:\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "  - name: %s\n    endpoint: http://localhost:9999\n    access_key_id: test\n    secret_access_key: test\n", name)
+		}
+		if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig("primary")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := DefaultConfig()
+	cfg.ConfigFile = path
+	_, toolkit, err := NewWithContext(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewWithContext() error = %v", err)
+	}
+	if got := toolkit.ListConnections(); len(got) != 1 {
+		t.Fatalf("ListConnections() = %v, want [primary]", got)
+	}
+
+	writeConfig("primary", "scratch")
+	deadline := time.Now().Add(10 * time.Second)
+	for len(toolkit.ListConnections()) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("config file change was not picked up: %v", toolkit.ListConnections())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if _, err := toolkit.GetClient("scratch"); err != nil {
+		t.Errorf("GetClient(scratch) error = %v", err)
+	}
+}
+
+func TestNewWithContext_InvalidConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "connections.yaml")
+	if err := os.WriteFile(path, []byte("connections:\n  - region: us-east-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.ConfigFile = path
+	if _, _, err := New(cfg); err == nil {
+		t.Error("expected error for a connection without a name")
+	}
+}
+
+func TestSecretResolvers_Keyfile(t *testing.T) {
+	key := make([]byte, multiserver.KeyfileKeySize)
+	path := filepath.Join(t.TempDir(), "secrets.kf")
+	if err := multiserver.WriteKeyfile(path, key, map[string]string{"prod": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Keyfile = path
+	cfg.KeyfileKey = base64.StdEncoding.EncodeToString(key)
+	resolvers, err := secretResolvers(cfg)
+	if err != nil {
+		t.Fatalf("secretResolvers() error = %v", err)
+	}
+	if len(resolvers) != 1 {
+		t.Fatalf("expected keyfile resolver, got %d resolvers", len(resolvers))
+	}
+	value, handled, err := resolvers[0].Resolve(context.Background(), "keyfile:prod")
+	if err != nil || !handled || value != "secret" {
+		t.Errorf("Resolve() = %q, %v, %v", value, handled, err)
+	}
+
+	cfg.KeyfileKey = "not base64!"
+	if _, err := secretResolvers(cfg); err == nil {
+		t.Error("expected error for invalid key encoding")
+	}
+	cfg.KeyfileKey = base64.StdEncoding.EncodeToString([]byte("short"))
+	if _, err := secretResolvers(cfg); err == nil {
+		t.Error("expected error for short key")
+	}
+}
+
+func TestAppendAdminOptions(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ExtConfig.Admin = true
+	manager := multiserver.NewManager(&multiserver.MultiConfig{
+		Connections: []multiserver.ConnectionConfig{{Name: "primary"}},
+	})
+
+	if opts := appendAdminOptions(nil, cfg, manager); len(opts) != 0 {
+		t.Errorf("admin tools need principals, got %d options", len(opts))
+	}
+	cfg.ExtConfig.AdminPrincipals = []string{"alice"}
+	if opts := appendAdminOptions(nil, cfg, nil); len(opts) != 0 {
+		t.Errorf("admin tools need a manager, got %d options", len(opts))
+	}
+
+	toolkit := tools.NewToolkit(nil, appendAdminOptions(nil, cfg, manager)...)
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	toolkit.RegisterAll(mcpServer)
+
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+	ss, err := mcpServer.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ss.Close() }()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cs.Close() }()
+
+	listed, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registered := make(map[string]bool)
+	for _, tool := range listed.Tools {
+		registered[tool.Name] = true
+	}
+	for _, name := range tools.AdminTools() {
+		if !registered[string(name)] {
+			t.Errorf("expected %s to be registered", name)
+		}
+	}
+
+	cfg.ExtConfig.Admin = false
+	if opts := appendAdminOptions(nil, cfg, manager); len(opts) != 0 {
+		t.Errorf("admin disabled, got %d options", len(opts))
+	}
+}
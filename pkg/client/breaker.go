@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// DefaultCircuitBreakerCooldown is how long an open circuit rejects requests
+// before letting a probe through, when CircuitBreakerThreshold is set without
+// a cooldown.
+const DefaultCircuitBreakerCooldown = 30 * time.Second
+
+// ErrCircuitOpen indicates that a request was rejected without being sent
+// because the connection's circuit breaker is open. Callers can test for it
+// with errors.Is.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Circuit breaker states reported by CircuitState.State.
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// circuitBreakerMiddlewareID identifies the breaker in the SDK middleware stack.
+const circuitBreakerMiddlewareID = "CircuitBreaker"
+
+// CircuitState is a snapshot of a connection's circuit breaker.
+type CircuitState struct {
+	// State is CircuitClosed, CircuitOpen, or CircuitHalfOpen.
+	State string
+
+	// ConsecutiveFailures is the number of transport errors since the last success.
+	ConsecutiveFailures int
+
+	// OpenedAt is when the circuit last opened (zero if it never has).
+	OpenedAt time.Time
+
+	// RetryAt is when an open circuit will let a probe request through.
+	RetryAt time.Time
+}
+
+// circuitBreaker fails requests fast after a run of consecutive transport
+// errors. After the cooldown, a single probe request is let through: success
+// closes the circuit, another transport error reopens it.
+//
+// Only transport errors count as failures. An API error such as AccessDenied
+// proves the endpoint is reachable and resets the count.
+type circuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	// now returns the current time (overridable for testing).
+	now func() time.Time
+}
+
+// newCircuitBreaker returns a breaker for the connection, or nil when the
+// threshold is not positive.
+func newCircuitBreaker(name string, threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCircuitBreakerCooldown
+	}
+	return &circuitBreaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// stateLocked returns the current state. Caller must hold b.mu.
+func (b *circuitBreaker) stateLocked(now time.Time) string {
+	switch {
+	case b.failures < b.threshold:
+		return CircuitClosed
+	case b.probing || !now.Before(b.openedAt.Add(b.cooldown)):
+		return CircuitHalfOpen
+	default:
+		return CircuitOpen
+	}
+}
+
+// allow reports whether a request may be sent, and whether it is the probe
+// of a half-open circuit. Only one probe is allowed at a time.
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.stateLocked(now) {
+	case CircuitClosed:
+		return false, nil
+	case CircuitHalfOpen:
+		if !b.probing {
+			b.probing = true
+			return true, nil
+		}
+	}
+
+	retryAt := b.openedAt.Add(b.cooldown)
+	return false, fmt.Errorf("connection %s: %w after %d consecutive transport errors; retry after %s",
+		b.name, ErrCircuitOpen, b.failures, retryAt.Sub(now).Round(time.Second))
+}
+
+// record updates the breaker with the outcome of a request that allow let
+// through. probe is what allow returned for the request; a request admitted
+// before the circuit opened does not end the probe in flight.
+func (b *circuitBreaker) record(err error, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+
+	if !isTransportError(err) {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold && (probe || b.failures == b.threshold) {
+		b.openedAt = b.now()
+	}
+}
+
+// endProbe lets another probe through without recording an outcome.
+func (b *circuitBreaker) endProbe() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// state returns a snapshot of the breaker.
+func (b *circuitBreaker) state() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := CircuitState{
+		State:               b.stateLocked(b.now()),
+		ConsecutiveFailures: b.failures,
+		OpenedAt:            b.openedAt,
+	}
+	if s.State != CircuitClosed {
+		s.RetryAt = b.openedAt.Add(b.cooldown)
+	}
+	return s
+}
+
+// HandleInitialize implements middleware.InitializeMiddleware. Running at the
+// initialize step places the breaker outside the retry loop, so an open
+// circuit skips retries entirely and each operation counts once.
+func (b *circuitBreaker) HandleInitialize(
+	ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	probe, err := b.allow()
+	if err != nil {
+		return middleware.InitializeOutput{}, middleware.Metadata{}, err
+	}
+	out, md, err := next.HandleInitialize(ctx, in)
+	// A caller cancelling its own request says nothing about the endpoint.
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		if probe {
+			b.endProbe()
+		}
+		return out, md, err
+	}
+	b.record(err, probe)
+	return out, md, err
+}
+
+// ID implements middleware.InitializeMiddleware.
+func (b *circuitBreaker) ID() string {
+	return circuitBreakerMiddlewareID
+}
+
+// addToStack registers the breaker as the outermost initialize middleware.
+func (b *circuitBreaker) addToStack(stack *middleware.Stack) error {
+	return stack.Initialize.Add(b, middleware.Before)
+}
+
+// removeCircuitBreaker drops the breaker from a stack. Presigning shares the
+// data client's options but sends nothing, so it must not trip or be blocked
+// by the breaker.
+func removeCircuitBreaker(stack *middleware.Stack) error {
+	if _, ok := stack.Initialize.Get(circuitBreakerMiddlewareID); !ok {
+		return nil
+	}
+	_, err := stack.Initialize.Remove(circuitBreakerMiddlewareID)
+	return err
+}
+
+// isTransportError reports whether err means the endpoint could not be
+// reached, as opposed to the endpoint answering with an error.
+func isTransportError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return false
+	}
+	var sendErr *smithyhttp.RequestSendError
+	if errors.As(err, &sendErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsUnavailable reports whether err means the endpoint could not serve the
+// request: a transport error or timeout, a 5xx response, or an open circuit.
+// A request that failed this way may succeed against a replica.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() >= 500 {
+		return true
+	}
+	return isTransportError(err)
+}
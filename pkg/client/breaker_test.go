@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	b := newCircuitBreaker("dead", 2, time.Minute)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+
+	transportErr := &smithyhttp.RequestSendError{Err: errors.New("connection refused")}
+
+	for i := 0; i < 2; i++ {
+		if _, err := b.allow(); err != nil {
+			t.Fatalf("allow() #%d error = %v", i, err)
+		}
+		b.record(transportErr, false)
+	}
+	if got := b.state().State; got != CircuitOpen {
+		t.Fatalf("state = %s, want %s", got, CircuitOpen)
+	}
+
+	_, err := b.allow()
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() error = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown one probe is let through; a second caller is not.
+	now = now.Add(time.Minute)
+	if got := b.state().State; got != CircuitHalfOpen {
+		t.Errorf("state = %s, want %s", got, CircuitHalfOpen)
+	}
+	if probe, err := b.allow(); err != nil || !probe {
+		t.Fatalf("probe allow() = %v, %v", probe, err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second allow() during probe error = %v, want ErrCircuitOpen", err)
+	}
+
+	// A request admitted before the circuit opened finishing now does not
+	// end the probe.
+	b.record(transportErr, false)
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() after a straggler finished error = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed probe reopens the circuit for another cooldown.
+	b.record(transportErr, true)
+	state := b.state()
+	if state.State != CircuitOpen || !state.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("state after failed probe = %+v", state)
+	}
+
+	// A successful probe closes it.
+	now = now.Add(time.Minute)
+	if probe, err := b.allow(); err != nil || !probe {
+		t.Fatalf("probe allow() = %v, %v", probe, err)
+	}
+	b.record(nil, true)
+	state = b.state()
+	if state.State != CircuitClosed || state.ConsecutiveFailures != 0 {
+		t.Errorf("state after successful probe = %+v", state)
+	}
+}
+
+func TestCircuitBreaker_APIErrorResets(t *testing.T) {
+	b := newCircuitBreaker("live", 2, time.Minute)
+	b.record(&smithyhttp.RequestSendError{Err: errors.New("timeout")}, false)
+	b.record(&smithy.GenericAPIError{Code: "AccessDenied"}, false)
+	b.record(&smithyhttp.RequestSendError{Err: errors.New("timeout")}, false)
+
+	if got := b.state(); got.State != CircuitClosed || got.ConsecutiveFailures != 1 {
+		t.Errorf("state = %+v, want closed with 1 failure", got)
+	}
+}
+
+func TestNewCircuitBreaker_Disabled(t *testing.T) {
+	if b := newCircuitBreaker("x", 0, time.Minute); b != nil {
+		t.Error("expected nil breaker for zero threshold")
+	}
+	if b := newCircuitBreaker("x", 1, 0); b.cooldown != DefaultCircuitBreakerCooldown {
+		t.Errorf("cooldown = %v, want default", b.cooldown)
+	}
+}
+
+func TestIsTransportError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"send error", fmt.Errorf("wrapped: %w", &smithyhttp.RequestSendError{Err: errors.New("refused")}), true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"api error", &smithy.GenericAPIError{Code: "NoSuchKey"}, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransportError(tt.err); got != tt.want {
+				t.Errorf("isTransportError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUnavailable(t *testing.T) {
+	responseErr := func(status int) error {
+		return &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+			Err:      &smithy.GenericAPIError{Code: "Error"},
+		}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"send error", &smithyhttp.RequestSendError{Err: errors.New("refused")}, true},
+		{"deadline", fmt.Errorf("failed to get object: %w", context.DeadlineExceeded), true},
+		{"circuit open", fmt.Errorf("connection dead: %w", ErrCircuitOpen), true},
+		{"503", fmt.Errorf("failed to list objects: %w", responseErr(503)), true},
+		{"404", responseErr(404), false},
+		{"api error", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnavailable(tt.err); got != tt.want {
+				t.Errorf("IsUnavailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_CircuitBreakerFailsFast(t *testing.T) {
+	ctx := context.Background()
+	c, err := New(ctx, &Config{
+		Region:                  "us-east-1",
+		Endpoint:                "http://127.0.0.1:1",
+		AccessKeyID:             "test",
+		SecretAccessKey:         "test",
+		UsePathStyle:            true,
+		Timeout:                 2 * time.Second,
+		RetryMode:               RetryModeOff,
+		CircuitBreakerThreshold: 2,
+		CircuitBreakerCooldown:  time.Hour,
+		Name:                    "dead",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListBuckets(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("ListBuckets #%d error = %v, want transport error", i, err)
+		}
+	}
+
+	if _, err := c.ListBuckets(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("ListBuckets error = %v, want ErrCircuitOpen", err)
+	}
+	state, ok := c.CircuitState()
+	if !ok || state.State != CircuitOpen {
+		t.Errorf("CircuitState() = %+v, %v", state, ok)
+	}
+
+	// Presigning issues no request and is not blocked by the breaker.
+	if _, err := c.PresignGetURL(ctx, "bucket", "key", time.Minute); err != nil {
+		t.Errorf("PresignGetURL error = %v", err)
+	}
+}
+
+func TestNewRetryer(t *testing.T) {
+	if r := newRetryer(&Config{}); r != nil {
+		t.Error("expected SDK default retryer when nothing is tuned")
+	}
+	if got := newRetryer(&Config{RetryMode: RetryModeOff})().MaxAttempts(); got != 1 {
+		t.Errorf("off MaxAttempts = %d, want 1", got)
+	}
+	if got := newRetryer(&Config{MaxAttempts: 7})().MaxAttempts(); got != 7 {
+		t.Errorf("standard MaxAttempts = %d, want 7", got)
+	}
+	if got := newRetryer(&Config{RetryMode: RetryModeAdaptive, MaxAttempts: 4})().MaxAttempts(); got != 4 {
+		t.Errorf("adaptive MaxAttempts = %d, want 4", got)
+	}
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// Capabilities reports which optional S3 features a connection supports.
+type Capabilities struct {
+	Versioning        bool
+	Tagging           bool
+	ObjectLock        bool
+	ConditionalWrites bool
+	Presign           bool
+
+	// Bucket is the bucket the probe ran against. It is empty when no bucket
+	// was available, in which case only the provider preset was used.
+	Bucket string
+
+	// ProbedAt is when the probe ran.
+	ProbedAt time.Time
+}
+
+// Supports reports whether feature, one of the Feature constants, is
+// supported. Features the probe does not cover are assumed supported.
+func (c Capabilities) Supports(feature string) bool {
+	switch feature {
+	case FeatureVersioning:
+		return c.Versioning
+	case FeatureObjectTagging:
+		return c.Tagging
+	case FeatureObjectLock:
+		return c.ObjectLock
+	case FeatureConditionalWrites:
+		return c.ConditionalWrites
+	case FeaturePresign:
+		return c.Presign
+	default:
+		return true
+	}
+}
+
+// Unsupported returns the probed features that are not supported.
+func (c Capabilities) Unsupported() []string {
+	var unsupported []string
+	for _, feature := range []string{
+		FeatureVersioning, FeatureObjectTagging, FeatureObjectLock, FeatureConditionalWrites, FeaturePresign,
+	} {
+		if !c.Supports(feature) {
+			unsupported = append(unsupported, feature)
+		}
+	}
+	return unsupported
+}
+
+// Capabilities probes the connection's optional features on first use and
+// caches the result. Features the provider preset lists as unsupported are
+// not probed. Versioning, tagging, and object lock are probed with read-only
+// bucket calls against the first allowed bucket, or else the first listed
+// bucket; a feature is unsupported when the service answers NotImplemented.
+// Conditional writes cannot be probed without writing, and presigning makes no
+// request to probe, so both follow the preset. A probe that could not reach
+// the endpoint is not cached, so the next call probes again.
+func (c *Client) Capabilities(ctx context.Context) Capabilities {
+	c.capMu.Lock()
+	defer c.capMu.Unlock()
+	if c.caps != nil {
+		return *c.caps
+	}
+	caps, complete := c.probeCapabilities(ctx)
+	if complete {
+		c.caps = &caps
+	}
+	return caps
+}
+
+// CachedCapabilities returns the cached probe result without probing. The
+// second result is false when the connection has not been probed.
+func (c *Client) CachedCapabilities() (Capabilities, bool) {
+	c.capMu.Lock()
+	defer c.capMu.Unlock()
+	if c.caps == nil {
+		return Capabilities{}, false
+	}
+	return *c.caps, true
+}
+
+// probeCapabilities runs the capability probe. The second result is false
+// when a call failed because the endpoint was unavailable.
+func (c *Client) probeCapabilities(ctx context.Context) (Capabilities, bool) {
+	caps := Capabilities{
+		Versioning:        c.config.Supports(FeatureVersioning),
+		Tagging:           c.config.Supports(FeatureObjectTagging),
+		ObjectLock:        c.config.Supports(FeatureObjectLock),
+		ConditionalWrites: c.config.Supports(FeatureConditionalWrites),
+		Presign:           c.config.Supports(FeaturePresign),
+		ProbedAt:          time.Now(),
+	}
+
+	bucket, err := c.probeBucket(ctx)
+	if err != nil || bucket == "" {
+		return caps, !IsUnavailable(err)
+	}
+	caps.Bucket = bucket
+
+	complete := true
+	probe := func(supported *bool, call func(ctx context.Context) error) {
+		if !*supported {
+			return
+		}
+		ctx, cancel := c.contextWithTimeout(ctx)
+		defer cancel()
+		switch err := call(ctx); {
+		case isNotImplemented(err):
+			*supported = false
+		case IsUnavailable(err):
+			complete = false
+		}
+	}
+	probe(&caps.Versioning, func(ctx context.Context) error {
+		_, err := c.s3Client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: aws.String(bucket)})
+		return err
+	})
+	probe(&caps.Tagging, func(ctx context.Context) error {
+		_, err := c.s3Client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucket)})
+		return err
+	})
+	probe(&caps.ObjectLock, func(ctx context.Context) error {
+		_, err := c.s3Client.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{Bucket: aws.String(bucket)})
+		return err
+	})
+	return caps, complete
+}
+
+// probeBucket returns the bucket to probe: the first allowed bucket, or else
+// the first bucket the connection can list.
+func (c *Client) probeBucket(ctx context.Context) (string, error) {
+	if len(c.config.AllowedBuckets) > 0 {
+		return c.config.AllowedBuckets[0], nil
+	}
+	ctx, cancel := c.contextWithTimeout(ctx)
+	defer cancel()
+	output, err := c.s3Client.ListBuckets(ctx, &s3.ListBucketsInput{MaxBuckets: aws.Int32(1)})
+	if err != nil {
+		return "", err
+	}
+	if len(output.Buckets) == 0 {
+		return "", nil
+	}
+	return aws.ToString(output.Buckets[0].Name), nil
+}
+
+// isNotImplemented reports whether err means the service does not implement
+// the operation.
+func isNotImplemented(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotImplemented" {
+		return true
+	}
+	var respErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == 501
+}
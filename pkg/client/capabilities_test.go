@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestClient_Capabilities(t *testing.T) {
+	notImplemented := &smithy.GenericAPIError{Code: "NotImplemented", Message: "not implemented"}
+	calls := 0
+	api := &mockS3API{
+		listBucketsFunc: func(context.Context, *s3.ListBucketsInput, ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+			calls++
+			return &s3.ListBucketsOutput{Buckets: []types.Bucket{{Name: aws.String("data")}}}, nil
+		},
+		getBucketTaggingFunc: func(_ context.Context, params *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+			if aws.ToString(params.Bucket) != "data" {
+				t.Errorf("probed bucket %q, want data", aws.ToString(params.Bucket))
+			}
+			return nil, notImplemented
+		},
+		getObjectLockConfigurationFunc: func(context.Context, *s3.GetObjectLockConfigurationInput) (*s3.GetObjectLockConfigurationOutput, error) {
+			return nil, &smithy.GenericAPIError{Code: "ObjectLockConfigurationNotFoundError"}
+		},
+	}
+	c := newMockClient(api, nil)
+	c.creds = &credentialTracker{}
+
+	if _, ok := c.CachedCapabilities(); ok {
+		t.Fatal("capabilities should not be cached before the first probe")
+	}
+	caps := c.Capabilities(context.Background())
+	if caps.Bucket != "data" {
+		t.Errorf("Bucket = %q, want data", caps.Bucket)
+	}
+	if want := []string{FeatureObjectTagging}; !reflect.DeepEqual(caps.Unsupported(), want) {
+		t.Errorf("Unsupported() = %v, want %v", caps.Unsupported(), want)
+	}
+
+	c.Capabilities(context.Background())
+	if calls != 1 {
+		t.Errorf("ListBuckets called %d times, want 1 (cached)", calls)
+	}
+	if _, ok := c.CachedCapabilities(); !ok {
+		t.Error("capabilities should be cached after the probe")
+	}
+}
+
+func TestClient_CapabilitiesPreset(t *testing.T) {
+	probed := false
+	api := &mockS3API{
+		getBucketVersioningFunc: func(context.Context, *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+			probed = true
+			return &s3.GetBucketVersioningOutput{}, nil
+		},
+	}
+	c := newMockClient(api, nil)
+	c.config = &Config{Provider: ProviderR2, AllowedBuckets: []string{"media"}}
+
+	caps := c.Capabilities(context.Background())
+	if probed {
+		t.Error("features the preset marks unsupported should not be probed")
+	}
+	if caps.Versioning || caps.Tagging {
+		t.Errorf("r2 capabilities = %+v, want no versioning or tagging", caps)
+	}
+	if !caps.Presign {
+		t.Error("presigning should follow the preset")
+	}
+	if !caps.ObjectLock || caps.Bucket != "media" {
+		t.Errorf("capabilities = %+v, want object lock probed on media", caps)
+	}
+}
+
+func TestClient_CapabilitiesUnavailable(t *testing.T) {
+	api := &mockS3API{
+		listBucketsFunc: func(context.Context, *s3.ListBucketsInput, ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+			return nil, ErrCircuitOpen
+		},
+	}
+	c := newMockClient(api, nil)
+
+	c.Capabilities(context.Background())
+	if _, ok := c.CachedCapabilities(); ok {
+		t.Error("a probe that could not reach the endpoint should not be cached")
+	}
+
+	if isNotImplemented(errors.New("boom")) {
+		t.Error("plain errors are not NotImplemented")
+	}
+}
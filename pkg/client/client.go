@@ -5,12 +5,17 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/transfermanager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
@@ -23,6 +28,15 @@ type Client struct {
 	uploader       ObjectUploader
 	config         *Config
 	connectionName string
+	breaker        *circuitBreaker
+	creds          *credentialTracker
+
+	// transport carries the connection pool shared by the SDK clients. Close
+	// releases its idle connections.
+	transport *http.Transport
+
+	capMu sync.Mutex
+	caps  *Capabilities
 }
 
 // BucketInfo contains information about an S3 bucket.
@@ -53,13 +67,14 @@ type ObjectMetadata struct {
 
 // ObjectContent contains the content and metadata of an S3 object.
 type ObjectContent struct {
-	Key          string
-	Body         []byte
-	ContentType  string
-	Size         int64
-	LastModified time.Time
-	ETag         string
-	Metadata     map[string]string
+	Key             string
+	Body            []byte
+	ContentType     string
+	ContentEncoding string
+	Size            int64
+	LastModified    time.Time
+	ETag            string
+	Metadata        map[string]string
 }
 
 // ListObjectsOutput contains the result of listing objects.
@@ -155,14 +170,23 @@ func New(ctx context.Context, cfg *Config) (*Client, error) {
 		opts = append(opts, config.WithSharedConfigProfile(cfg.Profile))
 	}
 
-	// Set explicit credentials if provided
-	if cfg.HasCredentials() {
-		staticCreds := credentials.NewStaticCredentialsProvider(
-			cfg.AccessKeyID,
-			cfg.SecretAccessKey,
-			cfg.SessionToken,
-		)
-		opts = append(opts, config.WithCredentialsProvider(staticCreds))
+	// Set explicit credentials (static keys or credential_process) if provided
+	if provider := baseCredentialsProvider(cfg); provider != nil {
+		opts = append(opts, config.WithCredentialsProvider(provider))
+	}
+
+	// Set the retry strategy if tuned
+	if retryer := newRetryer(cfg); retryer != nil {
+		opts = append(opts, config.WithRetryer(retryer))
+	}
+
+	// Set TLS, proxy, and connection pool options if configured
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP transport: %w", err)
+	}
+	if httpClient != nil {
+		opts = append(opts, config.WithHTTPClient(httpClient))
 	}
 
 	// Load AWS config
@@ -171,17 +195,66 @@ func New(ctx context.Context, cfg *Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	// Replace the buildable HTTP client the SDK resolved with one built on
+	// the same transport, so this client owns a connection pool that Close
+	// can release; a buildable client hides its transport
+	var transport *http.Transport
+	if buildable, ok := awsCfg.HTTPClient.(*awshttp.BuildableClient); ok {
+		transport = buildable.GetTransport()
+		awsCfg.HTTPClient = &http.Client{
+			Transport: transport,
+			Timeout:   buildable.GetTimeout(),
+			// Hand redirects to the SDK, as the buildable client does
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+
+	// Layer web identity and assumed roles on top, then track the result so
+	// that credential expiry can be reported without forcing a refresh
+	applyRoleCredentials(&awsCfg, cfg)
+	var creds *credentialTracker
+	if awsCfg.Credentials != nil {
+		creds = &credentialTracker{provider: awsCfg.Credentials}
+		awsCfg.Credentials = creds
+	}
+
 	// Build S3 client options
 	var s3Opts []func(*s3.Options)
 
 	// Set custom endpoint if specified
 	if cfg.HasEndpoint() {
 		s3Opts = append(s3Opts, func(o *s3.Options) {
-			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			o.BaseEndpoint = aws.String(resolveEndpoint(cfg.Endpoint, cfg.DisableSSL))
 			o.UsePathStyle = cfg.UsePathStyle
 		})
 	}
 
+	// Use plain HTTP for resolved AWS endpoints when SSL is disabled
+	if cfg.DisableSSL {
+		s3Opts = append(s3Opts, func(o *s3.Options) {
+			o.EndpointOptions.DisableHTTPS = true
+		})
+	}
+
+	// Only send and check checksums where required for providers that reject
+	// the SDK's default checksum headers
+	if preset, ok := cfg.Preset(); ok && preset.ChecksumWhenRequired {
+		s3Opts = append(s3Opts, func(o *s3.Options) {
+			o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+			o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+		})
+	}
+
+	// Fail fast on a dead endpoint once the circuit breaker opens
+	breaker := newCircuitBreaker(cfg.Name, cfg.CircuitBreakerThreshold, cfg.CircuitBreakerCooldown)
+	if breaker != nil {
+		s3Opts = append(s3Opts, func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, breaker.addToStack)
+		})
+	}
+
 	// Create S3 client
 	s3Client := s3.NewFromConfig(awsCfg, s3Opts...)
 
@@ -197,7 +270,11 @@ func New(ctx context.Context, cfg *Config) (*Client, error) {
 			o.UsePathStyle = cfg.UsePathStyle
 		})
 	}
-	presignClient := s3.NewPresignClient(presignSource)
+	presignClient := s3.NewPresignClient(presignSource, func(o *s3.PresignOptions) {
+		o.ClientOptions = append(o.ClientOptions, func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, removeCircuitBreaker)
+		})
+	})
 
 	// Create the streaming/multipart uploader. It shares the same underlying
 	// S3 client so it honors the configured endpoint, credentials, and region.
@@ -209,17 +286,68 @@ func New(ctx context.Context, cfg *Config) (*Client, error) {
 		uploader:       uploader,
 		config:         cfg.Clone(),
 		connectionName: cfg.Name,
+		breaker:        breaker,
+		creds:          creds,
+		transport:      transport,
 	}, nil
 }
 
+// newRetryer returns the SDK retryer for the configured retry settings, or
+// nil to keep the SDK default.
+func newRetryer(cfg *Config) func() aws.Retryer {
+	if cfg.RetryMode == RetryModeOff {
+		return func() aws.Retryer { return aws.NopRetryer{} }
+	}
+	if cfg.RetryMode == "" && cfg.MaxAttempts == 0 && cfg.MaxBackoff == 0 {
+		return nil
+	}
+
+	standard := func(o *retry.StandardOptions) {
+		if cfg.MaxAttempts > 0 {
+			o.MaxAttempts = cfg.MaxAttempts
+		}
+		if cfg.MaxBackoff > 0 {
+			o.MaxBackoff = cfg.MaxBackoff
+		}
+	}
+
+	if cfg.RetryMode == RetryModeAdaptive {
+		return func() aws.Retryer {
+			return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+				o.StandardOptions = append(o.StandardOptions, standard)
+			})
+		}
+	}
+	return func() aws.Retryer { return retry.NewStandard(standard) }
+}
+
+// CredentialState returns the source and expiry of the credentials most
+// recently used by the client. The second result is false when the client
+// signs requests anonymously.
+func (c *Client) CredentialState() (CredentialState, bool) {
+	if c.creds == nil {
+		return CredentialState{}, false
+	}
+	return c.creds.snapshot(), true
+}
+
+// CircuitState returns a snapshot of the connection's circuit breaker.
+// The second result is false when no circuit breaker is configured.
+func (c *Client) CircuitState() (CircuitState, bool) {
+	if c.breaker == nil {
+		return CircuitState{}, false
+	}
+	return c.breaker.state(), true
+}
+
 // ConnectionName returns the configured connection name.
 func (c *Client) ConnectionName() string {
 	return c.connectionName
 }
 
-// Config returns a copy of the client configuration.
+// Config returns a copy of the client configuration with secrets redacted.
 func (c *Client) Config() *Config {
-	return c.config.Clone()
+	return c.config.Redacted()
 }
 
 // ListBuckets returns a list of all buckets accessible to the client.
@@ -325,19 +453,72 @@ func (c *Client) GetObject(ctx context.Context, bucket, key string) (*ObjectCont
 		return nil, fmt.Errorf("failed to read object body: %w", err)
 	}
 
+	return newObjectContent(key, body, aws.ToInt64(output.ContentLength), output), nil
+}
+
+// GetObjectRange retrieves length bytes of an object's content starting at
+// offset. A range that runs past the end of the object returns the bytes up
+// to the end. Size in the result is the size of the whole object.
+func (c *Client) GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (*ObjectContent, error) {
+	if offset < 0 || length <= 0 {
+		return nil, fmt.Errorf("invalid range: offset %d, length %d", offset, length)
+	}
+
+	ctx, cancel := c.contextWithTimeout(ctx)
+	defer cancel()
+
+	output, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object range: %w", err)
+	}
+	defer func() { _ = output.Body.Close() }()
+
+	body, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object body: %w", err)
+	}
+
+	size, ok := rangeObjectSize(aws.ToString(output.ContentRange))
+	if !ok {
+		size = aws.ToInt64(output.ContentLength)
+	}
+	return newObjectContent(key, body, size, output), nil
+}
+
+// newObjectContent builds the ObjectContent for a GetObject response.
+func newObjectContent(key string, body []byte, size int64, output *s3.GetObjectOutput) *ObjectContent {
 	result := &ObjectContent{
-		Key:         key,
-		Body:        body,
-		ContentType: aws.ToString(output.ContentType),
-		Size:        aws.ToInt64(output.ContentLength),
-		ETag:        aws.ToString(output.ETag),
-		Metadata:    output.Metadata,
+		Key:             key,
+		Body:            body,
+		ContentType:     aws.ToString(output.ContentType),
+		ContentEncoding: aws.ToString(output.ContentEncoding),
+		Size:            size,
+		ETag:            aws.ToString(output.ETag),
+		Metadata:        output.Metadata,
 	}
 	if output.LastModified != nil {
 		result.LastModified = *output.LastModified
 	}
+	return result
+}
 
-	return result, nil
+// rangeObjectSize returns the complete object size from a Content-Range
+// header such as "bytes 0-99/1234". It returns false when the header is
+// missing or the size is unknown.
+func rangeObjectSize(contentRange string) (int64, bool) {
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return 0, false
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return size, true
 }
 
 // GetObjectMetadata retrieves an object's metadata without downloading the content.
@@ -501,8 +682,11 @@ func (c *Client) contextWithTimeout(ctx context.Context) (context.Context, conte
 	return context.WithTimeout(ctx, c.config.Timeout) //#nosec G118 -- cancel func is returned to caller
 }
 
-// Close closes the S3 client and releases resources.
-// Currently a no-op as the AWS SDK manages its own connection pool.
+// Close closes the client's idle pooled connections. Requests in flight are
+// not interrupted, and the client opens new connections if used again.
 func (c *Client) Close() error {
+	if c.transport != nil {
+		c.transport.CloseIdleConnections()
+	}
 	return nil
 }
@@ -97,6 +97,9 @@ func TestClient_Config(t *testing.T) {
 	if returnedCfg.UsePathStyle != cfg.UsePathStyle {
 		t.Errorf("UsePathStyle mismatch: got %v, expected %v", returnedCfg.UsePathStyle, cfg.UsePathStyle)
 	}
+	if returnedCfg.SecretAccessKey != RedactedSecret {
+		t.Errorf("SecretAccessKey should be redacted, got %q", returnedCfg.SecretAccessKey)
+	}
 
 	// Verify returned config is independent
 	returnedCfg.Region = "us-west-2"
@@ -469,6 +472,61 @@ func TestClient_GetObject(t *testing.T) {
 	})
 }
 
+func TestClient_GetObjectRange(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var gotRange string
+		mock := &mockS3API{
+			getObjectFunc: func(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+				gotRange = aws.ToString(params.Range)
+				return &s3.GetObjectOutput{
+					Body:          io.NopCloser(strings.NewReader("World")),
+					ContentLength: aws.Int64(5),
+					ContentRange:  aws.String("bytes 7-11/13"),
+				}, nil
+			},
+		}
+		client := newMockClient(mock, nil)
+
+		result, err := client.GetObjectRange(context.Background(), "my-bucket", "file.txt", 7, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotRange != "bytes=7-11" {
+			t.Errorf("expected range bytes=7-11, got %s", gotRange)
+		}
+		if string(result.Body) != "World" {
+			t.Errorf("expected 'World', got %s", string(result.Body))
+		}
+		if result.Size != 13 {
+			t.Errorf("expected object size 13, got %d", result.Size)
+		}
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		client := newMockClient(&mockS3API{}, nil)
+
+		if _, err := client.GetObjectRange(context.Background(), "my-bucket", "file.txt", -1, 5); err == nil {
+			t.Error("expected error for negative offset")
+		}
+		if _, err := client.GetObjectRange(context.Background(), "my-bucket", "file.txt", 0, 0); err == nil {
+			t.Error("expected error for empty range")
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock := &mockS3API{
+			getObjectFunc: func(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+				return nil, errors.New("invalid range")
+			},
+		}
+		client := newMockClient(mock, nil)
+
+		if _, err := client.GetObjectRange(context.Background(), "my-bucket", "file.txt", 100, 5); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
 func TestClient_GetObjectMetadata(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		now := time.Now()
@@ -2,6 +2,7 @@
 package client
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -14,6 +15,13 @@ const (
 	DefaultTimeout = 30 * time.Second
 )
 
+// Retry modes accepted by Config.RetryMode.
+const (
+	RetryModeStandard = "standard"
+	RetryModeAdaptive = "adaptive"
+	RetryModeOff      = "off"
+)
+
 // Config holds the configuration for connecting to an S3-compatible storage service.
 type Config struct {
 	// Region is the AWS region for the S3 service.
@@ -45,6 +53,16 @@ type Config struct {
 	// Required for some S3-compatible services like SeaweedFS.
 	UsePathStyle bool
 
+	// Provider selects a preset for an S3-compatible service (aws, minio, r2,
+	// b2, wasabi, seaweedfs, or ceph) that fills in the endpoint, region, and
+	// addressing style and adjusts checksum behavior. Empty means plain S3
+	// settings.
+	Provider string
+
+	// AccountID is the provider account used to build the endpoint, such as
+	// the Cloudflare account ID for r2.
+	AccountID string
+
 	// Timeout is the timeout for S3 operations.
 	Timeout time.Duration
 
@@ -53,12 +71,97 @@ type Config struct {
 
 	// DisableSSL disables SSL/TLS for the connection (useful for local development).
 	DisableSSL bool
+
+	// RetryMode selects the SDK retry strategy: "standard" (default),
+	// "adaptive" (client-side rate limiting on throttling), or "off".
+	RetryMode string
+
+	// MaxAttempts is the maximum number of attempts per operation, including
+	// the first (0 = SDK default of 3).
+	MaxAttempts int
+
+	// MaxBackoff caps the delay between retry attempts (0 = SDK default of 20s).
+	MaxBackoff time.Duration
+
+	// CircuitBreakerThreshold is the number of consecutive transport errors
+	// after which requests fail fast without being sent (0 = disabled).
+	CircuitBreakerThreshold int
+
+	// CircuitBreakerCooldown is how long an open circuit rejects requests
+	// before letting a probe through (default: 30s).
+	CircuitBreakerCooldown time.Duration
+
+	// CABundle is the path to a PEM file of additional trusted CA certificates,
+	// for endpoints signed by a private CA.
+	CABundle string
+
+	// ClientCert is the path to a PEM client certificate for mutual TLS.
+	ClientCert string
+
+	// ClientKey is the path to the PEM private key for ClientCert.
+	ClientKey string
+
+	// InsecureSkipVerify disables TLS certificate verification. Only use this
+	// for local development.
+	InsecureSkipVerify bool
+
+	// ProxyURL routes requests through an HTTP proxy. If empty, the standard
+	// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY variables apply.
+	ProxyURL string
+
+	// MaxIdleConns caps idle connections across all hosts (0 = SDK default).
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost caps idle connections per host (0 = SDK default).
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost caps total connections per host (0 = unlimited).
+	MaxConnsPerHost int
+
+	// IdleConnTimeout closes idle connections after this duration (0 = SDK default).
+	IdleConnTimeout time.Duration
+
+	// CredentialProcess is an external command that prints credentials in the
+	// AWS credential_process JSON format. It cannot be combined with static keys.
+	CredentialProcess string
+
+	// WebIdentityTokenFile is the path to an OIDC token (such as a Kubernetes
+	// service account token) exchanged for WebIdentityRoleARN credentials.
+	WebIdentityTokenFile string
+
+	// WebIdentityRoleARN is the role assumed with WebIdentityTokenFile.
+	WebIdentityRoleARN string
+
+	// AssumeRole is a chain of roles to assume. The first role is assumed with
+	// the base credentials and each later role with the one before it.
+	AssumeRole []AssumeRoleConfig
+
+	// STSEndpoint is an optional custom STS endpoint for assuming roles.
+	STSEndpoint string
+
+	// ReadOnly marks the connection read-only. The client does not enforce it;
+	// the tools package blocks write tools on a read-only connection.
+	ReadOnly bool
+
+	// MaxGetSize overrides the server-wide object retrieval limit for this
+	// connection (0 = use the server-wide limit).
+	MaxGetSize int64
+
+	// MaxPutSize overrides the server-wide object upload limit for this
+	// connection (0 = use the server-wide limit).
+	MaxPutSize int64
+
+	// AllowedBuckets restricts tools to these buckets (empty = all buckets).
+	AllowedBuckets []string
+
+	// AllowedPrefixes restricts tools to keys under these prefixes (empty = all keys).
+	AllowedPrefixes []string
 }
 
 // FromEnv creates a Config populated from environment variables.
 //
 // Environment variables:
-//   - AWS_REGION: AWS region (default: us-east-1)
+//   - AWS_REGION: AWS region (default: us-east-1, or the provider's default)
 //   - AWS_ACCESS_KEY_ID: Access key ID
 //   - AWS_SECRET_ACCESS_KEY: Secret access key
 //   - AWS_SESSION_TOKEN: Session token (optional)
@@ -66,12 +169,35 @@ type Config struct {
 //   - S3_ENDPOINT: Custom endpoint URL (optional)
 //   - S3_PRESIGN_ENDPOINT: Public endpoint for presigned URLs (optional)
 //   - S3_USE_PATH_STYLE: Use path-style URLs (default: false)
+//   - S3_PROVIDER: Provider preset: aws, minio, r2, b2, wasabi, seaweedfs, or ceph (optional)
+//   - S3_ACCOUNT_ID: Provider account ID, used by r2 (optional)
 //   - S3_TIMEOUT: Operation timeout (default: 30s)
 //   - S3_CONNECTION_NAME: Connection name (optional)
 //   - S3_DISABLE_SSL: Disable SSL (default: false)
+//   - S3_RETRY_MODE: Retry mode: standard, adaptive, or off (default: standard)
+//   - S3_MAX_ATTEMPTS: Maximum attempts per operation (default: 3)
+//   - S3_MAX_BACKOFF: Maximum delay between retries (default: 20s)
+//   - S3_CIRCUIT_BREAKER_THRESHOLD: Consecutive transport errors before failing fast (default: 0, disabled)
+//   - S3_CIRCUIT_BREAKER_COOLDOWN: Time an open circuit waits before probing (default: 30s)
+//   - S3_CA_BUNDLE: Path to a PEM CA bundle (optional)
+//   - S3_CLIENT_CERT, S3_CLIENT_KEY: Paths to a PEM client certificate and key for mTLS (optional)
+//   - S3_INSECURE_SKIP_VERIFY: Skip TLS certificate verification (default: false)
+//   - S3_PROXY_URL: HTTP proxy URL (optional)
+//   - S3_MAX_IDLE_CONNS, S3_MAX_IDLE_CONNS_PER_HOST, S3_MAX_CONNS_PER_HOST: Connection pool sizes (optional)
+//   - S3_IDLE_CONN_TIMEOUT: Idle connection timeout (optional)
+//   - S3_CREDENTIAL_PROCESS: Command that prints credentials (optional)
+//   - S3_ROLE_ARN: Role to assume with the base credentials (optional)
+//   - S3_EXTERNAL_ID: External ID for S3_ROLE_ARN (optional)
+//   - S3_ROLE_SESSION_NAME: Session name for S3_ROLE_ARN (default: mcp-s3)
+//   - S3_ROLE_SESSION_TAGS: Session tags for S3_ROLE_ARN as key=value,... (optional)
+//   - S3_STS_ENDPOINT: Custom STS endpoint (optional)
+//
+// Web identity credentials from AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN
+// are picked up by the SDK default credential chain.
 func FromEnv() Config {
+	provider := getEnvSanitized("S3_PROVIDER")
 	cfg := Config{
-		Region:          getEnvOrDefault("AWS_REGION", DefaultRegion),
+		Region:          getEnvOrDefault("AWS_REGION", providerRegion(provider)),
 		Endpoint:        getEnvSanitized("S3_ENDPOINT"),
 		PresignEndpoint: getEnvSanitized("S3_PRESIGN_ENDPOINT"),
 		AccessKeyID:     getEnvSanitized("AWS_ACCESS_KEY_ID"),
@@ -79,9 +205,39 @@ func FromEnv() Config {
 		SessionToken:    getEnvSanitized("AWS_SESSION_TOKEN"),
 		Profile:         getEnvSanitized("AWS_PROFILE"),
 		UsePathStyle:    getEnvBool("S3_USE_PATH_STYLE", false),
+		Provider:        provider,
+		AccountID:       getEnvSanitized("S3_ACCOUNT_ID"),
 		Timeout:         getEnvDuration("S3_TIMEOUT", DefaultTimeout),
 		Name:            getEnvSanitized("S3_CONNECTION_NAME"),
 		DisableSSL:      getEnvBool("S3_DISABLE_SSL", false),
+
+		RetryMode:               getEnvSanitized("S3_RETRY_MODE"),
+		MaxAttempts:             getEnvInt("S3_MAX_ATTEMPTS", 0),
+		MaxBackoff:              getEnvDuration("S3_MAX_BACKOFF", 0),
+		CircuitBreakerThreshold: getEnvInt("S3_CIRCUIT_BREAKER_THRESHOLD", 0),
+		CircuitBreakerCooldown:  getEnvDuration("S3_CIRCUIT_BREAKER_COOLDOWN", 0),
+
+		CABundle:            getEnvSanitized("S3_CA_BUNDLE"),
+		ClientCert:          getEnvSanitized("S3_CLIENT_CERT"),
+		ClientKey:           getEnvSanitized("S3_CLIENT_KEY"),
+		InsecureSkipVerify:  getEnvBool("S3_INSECURE_SKIP_VERIFY", false),
+		ProxyURL:            getEnvSanitized("S3_PROXY_URL"),
+		MaxIdleConns:        getEnvInt("S3_MAX_IDLE_CONNS", 0),
+		MaxIdleConnsPerHost: getEnvInt("S3_MAX_IDLE_CONNS_PER_HOST", 0),
+		MaxConnsPerHost:     getEnvInt("S3_MAX_CONNS_PER_HOST", 0),
+		IdleConnTimeout:     getEnvDuration("S3_IDLE_CONN_TIMEOUT", 0),
+
+		CredentialProcess: getEnvSanitized("S3_CREDENTIAL_PROCESS"),
+		STSEndpoint:       getEnvSanitized("S3_STS_ENDPOINT"),
+	}
+
+	if roleARN := getEnvSanitized("S3_ROLE_ARN"); roleARN != "" {
+		cfg.AssumeRole = []AssumeRoleConfig{{
+			RoleARN:     roleARN,
+			ExternalID:  getEnvSanitized("S3_EXTERNAL_ID"),
+			SessionName: getEnvSanitized("S3_ROLE_SESSION_NAME"),
+			SessionTags: parseTags(getEnvSanitized("S3_ROLE_SESSION_TAGS")),
+		}}
 	}
 
 	return cfg
@@ -90,6 +246,10 @@ func FromEnv() Config {
 // Validate checks if the configuration is valid.
 // It returns an error if required fields are missing or invalid.
 func (c *Config) Validate() error {
+	if err := c.applyProvider(); err != nil {
+		return err
+	}
+
 	if c.Region == "" {
 		c.Region = DefaultRegion
 	}
@@ -98,7 +258,26 @@ func (c *Config) Validate() error {
 		c.Timeout = DefaultTimeout
 	}
 
-	return nil
+	switch c.RetryMode {
+	case "", RetryModeStandard, RetryModeAdaptive, RetryModeOff:
+	default:
+		return fmt.Errorf("unknown retry mode %q (want %s, %s, or %s)",
+			c.RetryMode, RetryModeStandard, RetryModeAdaptive, RetryModeOff)
+	}
+
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts must not be negative, got %d", c.MaxAttempts)
+	}
+
+	if c.MaxGetSize < 0 || c.MaxPutSize < 0 {
+		return fmt.Errorf("size limits must not be negative")
+	}
+
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return fmt.Errorf("client certificate and client key must be set together")
+	}
+
+	return c.validateCredentials()
 }
 
 // HasCredentials returns true if explicit credentials are configured.
@@ -111,6 +290,24 @@ func (c *Config) HasEndpoint() bool {
 	return c.Endpoint != ""
 }
 
+// RedactedSecret replaces secret values in configurations returned by
+// Redacted.
+const RedactedSecret = "[REDACTED]"
+
+// Redacted returns a copy of the configuration with the secret access key
+// and session token replaced by RedactedSecret, safe to log or return to
+// callers.
+func (c *Config) Redacted() *Config {
+	redacted := c.Clone()
+	if redacted.SecretAccessKey != "" {
+		redacted.SecretAccessKey = RedactedSecret
+	}
+	if redacted.SessionToken != "" {
+		redacted.SessionToken = RedactedSecret
+	}
+	return redacted
+}
+
 // Clone creates a deep copy of the configuration.
 func (c *Config) Clone() *Config {
 	return &Config{
@@ -122,12 +319,78 @@ func (c *Config) Clone() *Config {
 		SessionToken:    c.SessionToken,
 		Profile:         c.Profile,
 		UsePathStyle:    c.UsePathStyle,
+		Provider:        c.Provider,
+		AccountID:       c.AccountID,
 		Timeout:         c.Timeout,
 		Name:            c.Name,
 		DisableSSL:      c.DisableSSL,
+
+		RetryMode:               c.RetryMode,
+		MaxAttempts:             c.MaxAttempts,
+		MaxBackoff:              c.MaxBackoff,
+		CircuitBreakerThreshold: c.CircuitBreakerThreshold,
+		CircuitBreakerCooldown:  c.CircuitBreakerCooldown,
+
+		CABundle:            c.CABundle,
+		ClientCert:          c.ClientCert,
+		ClientKey:           c.ClientKey,
+		InsecureSkipVerify:  c.InsecureSkipVerify,
+		ProxyURL:            c.ProxyURL,
+		MaxIdleConns:        c.MaxIdleConns,
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.MaxConnsPerHost,
+		IdleConnTimeout:     c.IdleConnTimeout,
+
+		CredentialProcess:    c.CredentialProcess,
+		WebIdentityTokenFile: c.WebIdentityTokenFile,
+		WebIdentityRoleARN:   c.WebIdentityRoleARN,
+		AssumeRole:           cloneAssumeRoles(c.AssumeRole),
+		STSEndpoint:          c.STSEndpoint,
+
+		ReadOnly:        c.ReadOnly,
+		MaxGetSize:      c.MaxGetSize,
+		MaxPutSize:      c.MaxPutSize,
+		AllowedBuckets:  cloneStrings(c.AllowedBuckets),
+		AllowedPrefixes: cloneStrings(c.AllowedPrefixes),
 	}
 }
 
+// cloneStrings returns a copy of a string slice, preserving nil.
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
+
+// cloneAssumeRoles returns a deep copy of a role chain.
+func cloneAssumeRoles(roles []AssumeRoleConfig) []AssumeRoleConfig {
+	if roles == nil {
+		return nil
+	}
+	result := make([]AssumeRoleConfig, len(roles))
+	for i, role := range roles {
+		result[i] = role.clone()
+	}
+	return result
+}
+
+// parseTags parses a comma-separated list of key=value pairs. Entries
+// without "=" are skipped.
+func parseTags(s string) map[string]string {
+	if s == "" {
+		return nil
+	}
+	tags := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && key != "" {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
 // getEnvOrDefault returns the value of an environment variable or a default value.
 // It treats unresolved template variables (from mcpb) as empty strings.
 func getEnvOrDefault(key, defaultValue string) string {
@@ -153,6 +416,21 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return parsed
 }
 
+// getEnvInt returns the integer value of an environment variable or a default value.
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+
+	return parsed
+}
+
 // getEnvDuration returns the duration value of an environment variable or a default value.
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	value := os.Getenv(key)
@@ -2,6 +2,7 @@ package client
 
 import (
 	"os"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -203,6 +204,20 @@ func TestConfig_Validate(t *testing.T) {
 		assertString(t, "Region", "ap-southeast-1", cfg.Region)
 		assertDuration(t, 120*time.Second, cfg.Timeout)
 	})
+
+	t.Run("rejects unknown retry mode", func(t *testing.T) {
+		cfg := &Config{RetryMode: "aggressive"}
+		if err := cfg.Validate(); err == nil {
+			t.Error("expected error for unknown retry mode")
+		}
+	})
+
+	t.Run("rejects negative max attempts", func(t *testing.T) {
+		cfg := &Config{MaxAttempts: -1}
+		if err := cfg.Validate(); err == nil {
+			t.Error("expected error for negative max attempts")
+		}
+	})
 }
 
 func TestConfig_HasCredentials(t *testing.T) {
@@ -269,6 +284,64 @@ func TestConfig_Clone(t *testing.T) {
 	}
 }
 
+func TestConfig_Redacted(t *testing.T) {
+	original := &Config{
+		Region:          "us-west-2",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+		SessionToken:    "test-token",
+	}
+
+	redacted := original.Redacted()
+	if redacted.SecretAccessKey != RedactedSecret || redacted.SessionToken != RedactedSecret {
+		t.Errorf("secrets not redacted: %+v", redacted)
+	}
+	if redacted.AccessKeyID != "test-key" || redacted.Region != "us-west-2" {
+		t.Errorf("non-secret fields changed: %+v", redacted)
+	}
+	if original.SecretAccessKey != "test-secret" {
+		t.Error("Redacted should not modify the original")
+	}
+
+	if empty := (&Config{}).Redacted(); empty.SecretAccessKey != "" || empty.SessionToken != "" {
+		t.Errorf("empty secrets should stay empty: %+v", empty)
+	}
+}
+
+func TestConfig_CloneCopiesAllFields(t *testing.T) {
+	original := &Config{
+		Region:                  "eu-west-1",
+		Endpoint:                "https://ceph.internal",
+		PresignEndpoint:         "https://s3.example.com",
+		Provider:                ProviderCeph,
+		AccountID:               "account",
+		Timeout:                 time.Minute,
+		RetryMode:               RetryModeAdaptive,
+		MaxAttempts:             5,
+		MaxBackoff:              time.Second,
+		CircuitBreakerThreshold: 3,
+		CircuitBreakerCooldown:  time.Minute,
+		CABundle:                "/etc/ssl/ca.pem",
+		ClientCert:              "/etc/ssl/client.pem",
+		ClientKey:               "/etc/ssl/client-key.pem",
+		InsecureSkipVerify:      true,
+		ProxyURL:                "http://proxy:3128",
+		MaxIdleConns:            10,
+		MaxIdleConnsPerHost:     5,
+		MaxConnsPerHost:         20,
+		IdleConnTimeout:         time.Minute,
+		ReadOnly:                true,
+		MaxGetSize:              1 << 20,
+		MaxPutSize:              1 << 30,
+		AllowedBuckets:          []string{"reports"},
+		AllowedPrefixes:         []string{"public/"},
+	}
+
+	if clone := original.Clone(); !reflect.DeepEqual(original, clone) {
+		t.Errorf("Clone() = %+v, want %+v", clone, original)
+	}
+}
+
 // Test helpers
 
 func saveEnv(vars []string) map[string]string {
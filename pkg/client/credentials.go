@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+)
+
+// DefaultRoleSessionName is the session name used when assuming a role
+// without an explicit one.
+const DefaultRoleSessionName = "mcp-s3"
+
+// AssumeRoleConfig describes one STS AssumeRole step.
+type AssumeRoleConfig struct {
+	// RoleARN is the ARN of the role to assume.
+	RoleARN string
+
+	// ExternalID is the external ID required by the role's trust policy, if any.
+	ExternalID string
+
+	// SessionName identifies the role session (default: mcp-s3).
+	SessionName string
+
+	// SessionTags are passed as STS session tags.
+	SessionTags map[string]string
+
+	// TransitiveTagKeys lists session tag keys that carry over to later roles in a chain.
+	TransitiveTagKeys []string
+
+	// Duration is the lifetime of the role credentials (0 = STS default).
+	Duration time.Duration
+}
+
+// clone returns a deep copy of the role configuration.
+func (r AssumeRoleConfig) clone() AssumeRoleConfig {
+	if r.SessionTags != nil {
+		tags := make(map[string]string, len(r.SessionTags))
+		for k, v := range r.SessionTags {
+			tags[k] = v
+		}
+		r.SessionTags = tags
+	}
+	if r.TransitiveTagKeys != nil {
+		r.TransitiveTagKeys = append([]string(nil), r.TransitiveTagKeys...)
+	}
+	return r
+}
+
+// sessionName returns the configured session name or the default.
+func (r AssumeRoleConfig) sessionName() string {
+	if r.SessionName != "" {
+		return r.SessionName
+	}
+	return DefaultRoleSessionName
+}
+
+// CredentialState reports the credentials most recently used by a client.
+type CredentialState struct {
+	// Source names the provider that supplied the credentials, such as
+	// AssumeRoleProvider or StaticCredentials.
+	Source string
+
+	// CanExpire is true for temporary credentials.
+	CanExpire bool
+
+	// Expires is when temporary credentials expire.
+	Expires time.Time
+
+	// RetrievedAt is when the credentials were last retrieved, or zero if no
+	// request has needed them yet.
+	RetrievedAt time.Time
+}
+
+// credentialTracker records the credentials returned by the provider it wraps
+// so that their source and expiry can be reported without triggering a
+// refresh. It sits outside the SDK credentials cache and only observes.
+type credentialTracker struct {
+	provider aws.CredentialsProvider
+
+	mu    sync.Mutex
+	state CredentialState
+}
+
+// Retrieve returns credentials from the wrapped provider and records them.
+func (t *credentialTracker) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := t.provider.Retrieve(ctx)
+	if err != nil {
+		return creds, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state.RetrievedAt.IsZero() || t.state.Expires != creds.Expires || t.state.Source != creds.Source {
+		t.state = CredentialState{
+			Source:      creds.Source,
+			CanExpire:   creds.CanExpire,
+			Expires:     creds.Expires,
+			RetrievedAt: time.Now(),
+		}
+	}
+	return creds, nil
+}
+
+// snapshot returns the last recorded credential state.
+func (t *credentialTracker) snapshot() CredentialState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// baseCredentialsProvider returns the provider configured directly on cfg, or
+// nil to use the SDK default chain (environment, profile, web identity from
+// AWS_WEB_IDENTITY_TOKEN_FILE, container and instance roles).
+func baseCredentialsProvider(cfg *Config) aws.CredentialsProvider {
+	switch {
+	case cfg.HasCredentials():
+		return credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken)
+	case cfg.CredentialProcess != "":
+		return processcreds.NewProvider(cfg.CredentialProcess)
+	default:
+		return nil
+	}
+}
+
+// applyRoleCredentials layers web identity and AssumeRole providers on top of
+// the base credentials in awsCfg. Each role in cfg.AssumeRole is assumed with
+// the credentials of the step before it, so listing several roles chains them.
+func applyRoleCredentials(awsCfg *aws.Config, cfg *Config) {
+	if cfg.WebIdentityTokenFile != "" {
+		client := newSTSClient(*awsCfg, cfg)
+		provider := stscreds.NewWebIdentityRoleProvider(client, cfg.WebIdentityRoleARN,
+			stscreds.IdentityTokenFile(cfg.WebIdentityTokenFile),
+			func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = DefaultRoleSessionName
+			})
+		awsCfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	for _, role := range cfg.AssumeRole {
+		client := newSTSClient(*awsCfg, cfg)
+		provider := stscreds.NewAssumeRoleProvider(client, role.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = role.sessionName()
+			o.Duration = role.Duration
+			if role.ExternalID != "" {
+				o.ExternalID = aws.String(role.ExternalID)
+			}
+			o.Tags = sessionTags(role.SessionTags)
+			o.TransitiveTagKeys = role.TransitiveTagKeys
+		})
+		awsCfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+}
+
+// newSTSClient creates an STS client that shares awsCfg's credentials,
+// region, HTTP client, and retryer, optionally pointed at cfg.STSEndpoint.
+func newSTSClient(awsCfg aws.Config, cfg *Config) *sts.Client {
+	return sts.NewFromConfig(awsCfg, func(o *sts.Options) {
+		if cfg.STSEndpoint != "" {
+			o.BaseEndpoint = aws.String(resolveEndpoint(cfg.STSEndpoint, cfg.DisableSSL))
+		}
+	})
+}
+
+// sessionTags converts a tag map to STS tags in a stable order.
+func sessionTags(tags map[string]string) []ststypes.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]ststypes.Tag, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, ststypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return result
+}
+
+// validateCredentials checks that the credential sources are consistent.
+func (c *Config) validateCredentials() error {
+	if c.CredentialProcess != "" && c.HasCredentials() {
+		return fmt.Errorf("credential process cannot be combined with static credentials")
+	}
+	if c.WebIdentityTokenFile != "" && c.WebIdentityRoleARN == "" {
+		return fmt.Errorf("web identity token file requires a web identity role ARN")
+	}
+	for i, role := range c.AssumeRole {
+		if role.RoleARN == "" {
+			return fmt.Errorf("assume role step %d: role ARN is required", i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSTS serves STS AssumeRole and AssumeRoleWithWebIdentity calls and S3
+// ListBuckets from one endpoint, recording the access key that signed each
+// request.
+type fakeSTS struct {
+	mu      sync.Mutex
+	calls   []string
+	forms   []map[string]string
+	expires time.Time
+}
+
+func (f *fakeSTS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	signer := "anonymous"
+	if auth := r.Header.Get("Authorization"); strings.Contains(auth, "Credential=") {
+		signer = strings.SplitN(strings.SplitN(auth, "Credential=", 2)[1], "/", 2)[0]
+	}
+
+	if r.Method == http.MethodPost {
+		_ = r.ParseForm()
+		form := make(map[string]string)
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		action := form["Action"]
+
+		f.mu.Lock()
+		f.calls = append(f.calls, action+":"+signer)
+		f.forms = append(f.forms, form)
+		n := len(f.calls)
+		f.mu.Unlock()
+
+		key := fmt.Sprintf("ROLE%d", n)
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = fmt.Fprintf(w, `<%[1]sResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><%[1]sResult>
+<Credentials><AccessKeyId>%[2]s</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken>
+<Expiration>%[3]s</Expiration></Credentials>
+<AssumedRoleUser><Arn>arn:aws:sts::123456789012:assumed-role/r/s</Arn><AssumedRoleId>id:s</AssumedRoleId></AssumedRoleUser>
+</%[1]sResult><ResponseMetadata><RequestId>req</RequestId></ResponseMetadata></%[1]sResponse>`,
+			action, key, f.expires.UTC().Format(time.RFC3339))
+		return
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, "ListBuckets:"+signer)
+	f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(listBucketsXML))
+}
+
+func newFakeSTS(t *testing.T) (*fakeSTS, *httptest.Server) {
+	t.Helper()
+	fake := &fakeSTS{expires: time.Now().Add(time.Hour).Truncate(time.Second)}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return fake, srv
+}
+
+func TestNew_AssumeRoleChain(t *testing.T) {
+	fake, srv := newFakeSTS(t)
+	ctx := context.Background()
+
+	cfg := tlsTestConfig(srv.URL)
+	cfg.STSEndpoint = srv.URL
+	cfg.AssumeRole = []AssumeRoleConfig{
+		{RoleARN: "arn:aws:iam::111111111111:role/hop", ExternalID: "ext-123", SessionTags: map[string]string{"team": "data"}},
+		{RoleARN: "arn:aws:iam::222222222222:role/target", SessionName: "reader"},
+	}
+
+	c, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, ok := c.CredentialState(); !ok {
+		t.Fatal("expected credential state to be tracked")
+	}
+	if state, _ := c.CredentialState(); !state.RetrievedAt.IsZero() {
+		t.Errorf("credentials should not be retrieved before the first request, got %+v", state)
+	}
+
+	if _, err := c.ListBuckets(ctx); err != nil {
+		t.Fatalf("ListBuckets: %v", err)
+	}
+
+	// The first role is assumed with the static keys, the second with the
+	// first role's credentials, and S3 is called with the second role's.
+	want := []string{"AssumeRole:test", "AssumeRole:ROLE1", "ListBuckets:ROLE2"}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("calls = %v, want %v", fake.calls, want)
+	}
+
+	first := fake.forms[0]
+	if first["ExternalId"] != "ext-123" || first["RoleSessionName"] != DefaultRoleSessionName {
+		t.Errorf("first AssumeRole form = %v", first)
+	}
+	if first["Tags.member.1.Key"] != "team" || first["Tags.member.1.Value"] != "data" {
+		t.Errorf("session tags not sent: %v", first)
+	}
+	if fake.forms[1]["RoleSessionName"] != "reader" {
+		t.Errorf("second AssumeRole form = %v", fake.forms[1])
+	}
+
+	state, _ := c.CredentialState()
+	if !state.CanExpire || !state.Expires.Equal(fake.expires) || state.Source == "" {
+		t.Errorf("CredentialState() = %+v, want expiry %v", state, fake.expires)
+	}
+
+	// Cached credentials are reused for later requests.
+	if _, err := c.ListBuckets(ctx); err != nil {
+		t.Fatalf("ListBuckets: %v", err)
+	}
+	if len(fake.calls) != 4 {
+		t.Errorf("expected cached role credentials, calls = %v", fake.calls)
+	}
+}
+
+func TestNew_WebIdentity(t *testing.T) {
+	fake, srv := newFakeSTS(t)
+	ctx := context.Background()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("k8s-jwt"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg := tlsTestConfig(srv.URL)
+	cfg.AccessKeyID, cfg.SecretAccessKey = "", ""
+	cfg.STSEndpoint = srv.URL
+	cfg.WebIdentityTokenFile = tokenFile
+	cfg.WebIdentityRoleARN = "arn:aws:iam::111111111111:role/pod"
+
+	c, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(ctx); err != nil {
+		t.Fatalf("ListBuckets: %v", err)
+	}
+
+	if len(fake.calls) != 2 || fake.calls[0] != "AssumeRoleWithWebIdentity:anonymous" || fake.calls[1] != "ListBuckets:ROLE1" {
+		t.Errorf("calls = %v", fake.calls)
+	}
+	if fake.forms[0]["WebIdentityToken"] != "k8s-jwt" || fake.forms[0]["RoleArn"] != cfg.WebIdentityRoleARN {
+		t.Errorf("web identity form = %v", fake.forms[0])
+	}
+}
+
+func TestNew_CredentialProcess(t *testing.T) {
+	fake, srv := newFakeSTS(t)
+	ctx := context.Background()
+
+	cfg := tlsTestConfig(srv.URL)
+	cfg.AccessKeyID, cfg.SecretAccessKey = "", ""
+	cfg.CredentialProcess = `echo '{"Version":1,"AccessKeyId":"PROCESS","SecretAccessKey":"secret"}'`
+
+	c, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(ctx); err != nil {
+		t.Fatalf("ListBuckets: %v", err)
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "ListBuckets:PROCESS" {
+		t.Errorf("calls = %v", fake.calls)
+	}
+	if state, _ := c.CredentialState(); state.CanExpire {
+		t.Errorf("process credentials without expiry reported as temporary: %+v", state)
+	}
+}
+
+func TestConfig_ValidateCredentials(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"process with static keys", Config{AccessKeyID: "a", SecretAccessKey: "b", CredentialProcess: "x"}},
+		{"web identity without role", Config{WebIdentityTokenFile: "/token"}},
+		{"assume role without ARN", Config{AssumeRole: []AssumeRoleConfig{{ExternalID: "x"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestFromEnv_AssumeRole(t *testing.T) {
+	envVars := []string{"S3_ROLE_ARN", "S3_EXTERNAL_ID", "S3_ROLE_SESSION_NAME", "S3_ROLE_SESSION_TAGS"}
+	saved := saveEnv(envVars)
+	defer restoreEnv(saved)
+
+	os.Setenv("S3_ROLE_ARN", "arn:aws:iam::111111111111:role/reader")
+	os.Setenv("S3_EXTERNAL_ID", "ext")
+	os.Setenv("S3_ROLE_SESSION_NAME", "")
+	os.Setenv("S3_ROLE_SESSION_TAGS", "team=data, env=prod,bad")
+
+	cfg := FromEnv()
+	want := []AssumeRoleConfig{{
+		RoleARN:     "arn:aws:iam::111111111111:role/reader",
+		ExternalID:  "ext",
+		SessionTags: map[string]string{"team": "data", "env": "prod"},
+	}}
+	if !reflect.DeepEqual(cfg.AssumeRole, want) {
+		t.Errorf("AssumeRole = %+v, want %+v", cfg.AssumeRole, want)
+	}
+}
+
+func TestConfig_CloneAssumeRoleIsDeep(t *testing.T) {
+	original := &Config{AssumeRole: []AssumeRoleConfig{{RoleARN: "a", SessionTags: map[string]string{"k": "v"}}}}
+	clone := original.Clone()
+	clone.AssumeRole[0].SessionTags["k"] = "changed"
+	clone.AssumeRole[0].RoleARN = "b"
+	if original.AssumeRole[0].SessionTags["k"] != "v" || original.AssumeRole[0].RoleARN != "a" {
+		t.Error("Clone() shares AssumeRole state with the original")
+	}
+}
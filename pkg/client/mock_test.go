@@ -20,6 +20,10 @@ type mockS3API struct {
 	putObjectFunc     func(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
 	deleteObjectFunc  func(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
 	copyObjectFunc    func(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+
+	getBucketVersioningFunc        func(ctx context.Context, params *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error)
+	getBucketTaggingFunc           func(ctx context.Context, params *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error)
+	getObjectLockConfigurationFunc func(ctx context.Context, params *s3.GetObjectLockConfigurationInput) (*s3.GetObjectLockConfigurationOutput, error)
 }
 
 func (m *mockS3API) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
@@ -73,6 +77,33 @@ func (m *mockS3API) CopyObject(ctx context.Context, params *s3.CopyObjectInput,
 	return &s3.CopyObjectOutput{}, nil
 }
 
+func (m *mockS3API) GetBucketVersioning(
+	ctx context.Context, params *s3.GetBucketVersioningInput, _ ...func(*s3.Options),
+) (*s3.GetBucketVersioningOutput, error) {
+	if m.getBucketVersioningFunc != nil {
+		return m.getBucketVersioningFunc(ctx, params)
+	}
+	return &s3.GetBucketVersioningOutput{}, nil
+}
+
+func (m *mockS3API) GetBucketTagging(
+	ctx context.Context, params *s3.GetBucketTaggingInput, _ ...func(*s3.Options),
+) (*s3.GetBucketTaggingOutput, error) {
+	if m.getBucketTaggingFunc != nil {
+		return m.getBucketTaggingFunc(ctx, params)
+	}
+	return &s3.GetBucketTaggingOutput{}, nil
+}
+
+func (m *mockS3API) GetObjectLockConfiguration(
+	ctx context.Context, params *s3.GetObjectLockConfigurationInput, _ ...func(*s3.Options),
+) (*s3.GetObjectLockConfigurationOutput, error) {
+	if m.getObjectLockConfigurationFunc != nil {
+		return m.getObjectLockConfigurationFunc(ctx, params)
+	}
+	return &s3.GetObjectLockConfigurationOutput{}, nil
+}
+
 // mockPresignAPI is a mock implementation of PresignAPI for testing.
 type mockPresignAPI struct {
 	presignGetObjectFunc func(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Providers accepted by Config.Provider.
+const (
+	ProviderAWS       = "aws"
+	ProviderMinIO     = "minio"
+	ProviderR2        = "r2"
+	ProviderB2        = "b2"
+	ProviderWasabi    = "wasabi"
+	ProviderSeaweedFS = "seaweedfs"
+	ProviderCeph      = "ceph"
+)
+
+// Features that some S3-compatible services do not implement.
+const (
+	FeatureVersioning        = "versioning"
+	FeatureObjectTagging     = "object_tagging"
+	FeatureObjectLock        = "object_lock"
+	FeatureConditionalWrites = "conditional_writes"
+	FeaturePresign           = "presign"
+	FeatureACL               = "acl"
+)
+
+// ProviderPreset holds the settings an S3-compatible service needs, so a
+// connection can name its provider instead of setting each flag.
+type ProviderPreset struct {
+	// Name is the provider name used in Config.Provider.
+	Name string
+
+	// EndpointTemplate builds the endpoint when none is configured.
+	// "{region}" is replaced by the region and "{account}" by AccountID.
+	// Empty means the SDK resolves the endpoint, or, with RequiresEndpoint,
+	// that the endpoint must be configured.
+	EndpointTemplate string
+
+	// RequiresEndpoint means the service is self-hosted and has no
+	// well-known endpoint.
+	RequiresEndpoint bool
+
+	// DefaultRegion replaces DefaultRegion when no region is configured.
+	DefaultRegion string
+
+	// UsePathStyle forces path-style addressing.
+	UsePathStyle bool
+
+	// ChecksumWhenRequired limits request checksums and response
+	// validation to operations that require them. Services that reject the
+	// SDK's default CRC checksum headers need it.
+	ChecksumWhenRequired bool
+
+	// Unsupported lists the features the service does not implement.
+	Unsupported []string
+}
+
+// presets holds the built-in provider presets by name.
+var presets = map[string]ProviderPreset{
+	ProviderAWS: {
+		Name: ProviderAWS,
+	},
+	ProviderMinIO: {
+		Name:                 ProviderMinIO,
+		RequiresEndpoint:     true,
+		UsePathStyle:         true,
+		ChecksumWhenRequired: true,
+	},
+	ProviderR2: {
+		Name:                 ProviderR2,
+		EndpointTemplate:     "https://{account}.r2.cloudflarestorage.com",
+		DefaultRegion:        "auto",
+		ChecksumWhenRequired: true,
+		Unsupported:          []string{FeatureVersioning, FeatureObjectTagging, FeatureACL},
+	},
+	ProviderB2: {
+		Name:                 ProviderB2,
+		EndpointTemplate:     "https://s3.{region}.backblazeb2.com",
+		ChecksumWhenRequired: true,
+		Unsupported:          []string{FeatureObjectTagging, FeatureACL},
+	},
+	ProviderWasabi: {
+		Name:                 ProviderWasabi,
+		EndpointTemplate:     "https://s3.{region}.wasabisys.com",
+		ChecksumWhenRequired: true,
+	},
+	ProviderSeaweedFS: {
+		Name:                 ProviderSeaweedFS,
+		RequiresEndpoint:     true,
+		UsePathStyle:         true,
+		ChecksumWhenRequired: true,
+		Unsupported:          []string{FeatureObjectLock, FeatureACL},
+	},
+	ProviderCeph: {
+		Name:                 ProviderCeph,
+		RequiresEndpoint:     true,
+		UsePathStyle:         true,
+		ChecksumWhenRequired: true,
+	},
+}
+
+// LookupProvider returns the preset for a provider name. Names are case
+// insensitive.
+func LookupProvider(name string) (ProviderPreset, bool) {
+	preset, ok := presets[strings.ToLower(name)]
+	if !ok {
+		return ProviderPreset{}, false
+	}
+	preset.Unsupported = cloneStrings(preset.Unsupported)
+	return preset, true
+}
+
+// Providers returns the names of the built-in presets, sorted.
+func Providers() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Supports reports whether the preset's service implements feature.
+func (p ProviderPreset) Supports(feature string) bool {
+	return !slices.Contains(p.Unsupported, feature)
+}
+
+// Endpoint returns the endpoint the template gives for region and account,
+// or an empty string when the preset has no template.
+func (p ProviderPreset) Endpoint(region, account string) string {
+	return strings.NewReplacer("{region}", region, "{account}", account).Replace(p.EndpointTemplate)
+}
+
+// Preset returns the preset selected by c.Provider. The second result is
+// false when no provider, or an unknown one, is set.
+func (c *Config) Preset() (ProviderPreset, bool) {
+	if c.Provider == "" {
+		return ProviderPreset{}, false
+	}
+	return LookupProvider(c.Provider)
+}
+
+// Supports reports whether the connection's provider implements feature.
+// Connections without a provider are assumed to support everything.
+func (c *Config) Supports(feature string) bool {
+	preset, ok := c.Preset()
+	return !ok || preset.Supports(feature)
+}
+
+// applyProvider fills the region, endpoint, and addressing style from the
+// provider preset. Settings already configured are kept, except that
+// path-style addressing is forced on for providers that need it.
+func (c *Config) applyProvider() error {
+	if c.Provider == "" {
+		return nil
+	}
+	preset, ok := c.Preset()
+	if !ok {
+		return fmt.Errorf("unknown provider %q (want one of %s)", c.Provider, strings.Join(Providers(), ", "))
+	}
+
+	if c.Region == "" {
+		c.Region = preset.DefaultRegion
+	}
+	if c.Endpoint == "" {
+		switch {
+		case strings.Contains(preset.EndpointTemplate, "{account}") && c.AccountID == "":
+			return fmt.Errorf("provider %s requires an account ID or an endpoint", preset.Name)
+		case preset.EndpointTemplate != "":
+			region := c.Region
+			if region == "" {
+				region = DefaultRegion
+			}
+			c.Endpoint = preset.Endpoint(region, c.AccountID)
+		case preset.RequiresEndpoint:
+			return fmt.Errorf("provider %s requires an endpoint", preset.Name)
+		}
+	}
+	if preset.UsePathStyle {
+		c.UsePathStyle = true
+	}
+	return nil
+}
+
+// providerRegion returns the default region for a provider name, falling
+// back to DefaultRegion.
+func providerRegion(name string) string {
+	if preset, ok := LookupProvider(name); ok && preset.DefaultRegion != "" {
+		return preset.DefaultRegion
+	}
+	return DefaultRegion
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestConfig_ApplyProvider(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantErr      bool
+		wantEndpoint string
+		wantRegion   string
+		wantPath     bool
+	}{
+		{
+			name:       "no provider",
+			cfg:        Config{},
+			wantRegion: DefaultRegion,
+		},
+		{
+			name:       "aws",
+			cfg:        Config{Provider: "AWS", Region: "eu-west-1"},
+			wantRegion: "eu-west-1",
+		},
+		{
+			name:         "minio",
+			cfg:          Config{Provider: ProviderMinIO, Endpoint: "http://minio:9000"},
+			wantEndpoint: "http://minio:9000",
+			wantRegion:   DefaultRegion,
+			wantPath:     true,
+		},
+		{
+			name:    "minio without endpoint",
+			cfg:     Config{Provider: ProviderMinIO},
+			wantErr: true,
+		},
+		{
+			name:         "r2",
+			cfg:          Config{Provider: ProviderR2, AccountID: "abc123"},
+			wantEndpoint: "https://abc123.r2.cloudflarestorage.com",
+			wantRegion:   "auto",
+		},
+		{
+			name:    "r2 without account",
+			cfg:     Config{Provider: ProviderR2},
+			wantErr: true,
+		},
+		{
+			name:         "b2",
+			cfg:          Config{Provider: ProviderB2, Region: "us-west-004"},
+			wantEndpoint: "https://s3.us-west-004.backblazeb2.com",
+			wantRegion:   "us-west-004",
+		},
+		{
+			name:         "wasabi default region",
+			cfg:          Config{Provider: ProviderWasabi},
+			wantEndpoint: "https://s3.us-east-1.wasabisys.com",
+			wantRegion:   DefaultRegion,
+		},
+		{
+			name:         "explicit endpoint wins",
+			cfg:          Config{Provider: ProviderWasabi, Endpoint: "https://s3.eu-central-1.wasabisys.com"},
+			wantEndpoint: "https://s3.eu-central-1.wasabisys.com",
+			wantRegion:   DefaultRegion,
+		},
+		{
+			name:    "unknown provider",
+			cfg:     Config{Provider: "dropbox"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			err := cfg.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertString(t, "Endpoint", tt.wantEndpoint, cfg.Endpoint)
+			assertString(t, "Region", tt.wantRegion, cfg.Region)
+			assertBool(t, "UsePathStyle", tt.wantPath, cfg.UsePathStyle)
+		})
+	}
+}
+
+func TestConfig_Supports(t *testing.T) {
+	if !(&Config{}).Supports(FeatureVersioning) {
+		t.Error("connections without a provider should support versioning")
+	}
+	if (&Config{Provider: ProviderR2}).Supports(FeatureVersioning) {
+		t.Error("r2 should not support versioning")
+	}
+	if !(&Config{Provider: ProviderMinIO}).Supports(FeatureObjectTagging) {
+		t.Error("minio should support object tagging")
+	}
+
+	preset, _ := LookupProvider(ProviderR2)
+	preset.Unsupported[0] = "changed"
+	if again, _ := LookupProvider(ProviderR2); again.Unsupported[0] == "changed" {
+		t.Error("LookupProvider should return a copy of the preset")
+	}
+}
+
+func TestFromEnv_Provider(t *testing.T) {
+	envVars := []string{"AWS_REGION", "S3_PROVIDER", "S3_ACCOUNT_ID"}
+	saved := saveEnv(envVars)
+	defer restoreEnv(saved)
+	clearEnv(envVars)
+
+	setEnvVars(map[string]string{"S3_PROVIDER": ProviderR2, "S3_ACCOUNT_ID": "abc123"})
+	defer clearEnv(envVars)
+
+	cfg := FromEnv()
+	assertString(t, "Provider", ProviderR2, cfg.Provider)
+	assertString(t, "AccountID", "abc123", cfg.AccountID)
+	assertString(t, "Region", "auto", cfg.Region)
+
+	os.Setenv("AWS_REGION", "eu-west-1")
+	assertString(t, "Region", "eu-west-1", FromEnv().Region)
+}
+
+func TestNew_ProviderChecksums(t *testing.T) {
+	c, err := New(context.Background(), &Config{
+		Provider:        ProviderMinIO,
+		Endpoint:        "http://localhost:9000",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	opts := c.s3Client.(*s3.Client).Options()
+	if !opts.UsePathStyle {
+		t.Error("minio connections should use path-style addressing")
+	}
+	if opts.RequestChecksumCalculation != aws.RequestChecksumCalculationWhenRequired {
+		t.Errorf("RequestChecksumCalculation = %v, want when required", opts.RequestChecksumCalculation)
+	}
+	if opts.ResponseChecksumValidation != aws.ResponseChecksumValidationWhenRequired {
+		t.Errorf("ResponseChecksumValidation = %v, want when required", opts.ResponseChecksumValidation)
+	}
+}
@@ -18,6 +18,13 @@ type S3API interface {
 	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
 	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
 	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	GetBucketVersioning(
+		ctx context.Context, params *s3.GetBucketVersioningInput, optFns ...func(*s3.Options),
+	) (*s3.GetBucketVersioningOutput, error)
+	GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+	GetObjectLockConfiguration(
+		ctx context.Context, params *s3.GetObjectLockConfigurationInput, optFns ...func(*s3.Options),
+	) (*s3.GetObjectLockConfigurationOutput, error)
 }
 
 // PresignAPI defines the interface for presigning operations.
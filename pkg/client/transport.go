@@ -0,0 +1,122 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// hasTransportSettings returns true if any TLS, proxy, or connection pool
+// setting differs from the SDK default.
+func (c *Config) hasTransportSettings() bool {
+	return c.CABundle != "" || c.ClientCert != "" || c.ClientKey != "" || c.InsecureSkipVerify ||
+		c.ProxyURL != "" || c.MaxIdleConns > 0 || c.MaxIdleConnsPerHost > 0 ||
+		c.MaxConnsPerHost > 0 || c.IdleConnTimeout > 0
+}
+
+// newHTTPClient builds the HTTP client for the configured TLS, proxy, and
+// connection pool settings. It returns nil when nothing is configured so the
+// SDK default client is used unchanged.
+func newHTTPClient(cfg *Config) (*awshttp.BuildableClient, error) {
+	if !cfg.hasTransportSettings() {
+		return nil, nil
+	}
+
+	tlsCfg, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var proxy func(*http.Request) (*url.URL, error)
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	return awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		if tlsCfg != nil {
+			tr.TLSClientConfig = tlsCfg
+		}
+		if proxy != nil {
+			tr.Proxy = proxy
+		}
+		if cfg.MaxIdleConns > 0 {
+			tr.MaxIdleConns = cfg.MaxIdleConns
+		}
+		if cfg.MaxIdleConnsPerHost > 0 {
+			tr.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+		}
+		if cfg.MaxConnsPerHost > 0 {
+			tr.MaxConnsPerHost = cfg.MaxConnsPerHost
+		}
+		if cfg.IdleConnTimeout > 0 {
+			tr.IdleConnTimeout = cfg.IdleConnTimeout
+		}
+	}), nil
+}
+
+// newTLSConfig builds the TLS configuration for a custom CA bundle, client
+// certificate, or disabled verification. It returns nil when none is set.
+func newTLSConfig(cfg *Config) (*tls.Config, error) {
+	if cfg.CABundle == "" && cfg.ClientCert == "" && !cfg.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //#nosec G402 -- opt-in for development endpoints
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle) //#nosec G304 -- Path is intentionally user-provided CA bundle
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", cfg.CABundle)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+// resolveEndpoint applies DisableSSL to a custom endpoint. An endpoint without
+// a scheme gets https, or http when SSL is disabled; an https endpoint is
+// downgraded to http when SSL is disabled.
+func resolveEndpoint(endpoint string, disableSSL bool) string {
+	scheme := "https://"
+	if disableSSL {
+		scheme = "http://"
+	}
+
+	switch {
+	case !strings.Contains(endpoint, "://"):
+		return scheme + endpoint
+	case disableSSL && strings.HasPrefix(strings.ToLower(endpoint), "https://"):
+		return scheme + endpoint[len("https://"):]
+	default:
+		return endpoint
+	}
+}
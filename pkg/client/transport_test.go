@@ -0,0 +1,335 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const listBucketsXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ListAllMyBucketsResult><Buckets><Bucket><Name>private-ca</Name></Bucket></Buckets></ListAllMyBucketsResult>`
+
+// writeCert creates a self-signed certificate for 127.0.0.1 and writes the
+// certificate and key as PEM files in dir.
+func writeCert(t *testing.T, dir, name string) (certFile, keyFile string, cert tls.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	certFile = filepath.Join(dir, name+".pem")
+	keyFile = filepath.Join(dir, name+"-key.pem")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair: %v", err)
+	}
+	return certFile, keyFile, cert
+}
+
+// newTLSServer starts an S3-like HTTPS server using cert. When clientCA is
+// set, the server requires a client certificate signed by it.
+func newTLSServer(t *testing.T, cert tls.Certificate, clientCA string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(listBucketsXML))
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCA != "" {
+		pemData, err := os.ReadFile(clientCA)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(pemData)
+		srv.TLS.ClientCAs = pool
+		srv.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func tlsTestConfig(endpoint string) *Config {
+	return &Config{
+		Region:          "us-east-1",
+		Endpoint:        endpoint,
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		UsePathStyle:    true,
+		Timeout:         5 * time.Second,
+		RetryMode:       RetryModeOff,
+	}
+}
+
+func TestNew_CABundle(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _, cert := writeCert(t, dir, "server")
+	srv := newTLSServer(t, cert, "")
+	ctx := context.Background()
+
+	// Without the CA bundle the private certificate is rejected.
+	c, err := New(ctx, tlsTestConfig(srv.URL))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(ctx); err == nil {
+		t.Fatal("expected certificate verification failure without CA bundle")
+	}
+
+	cfg := tlsTestConfig(srv.URL)
+	cfg.CABundle = caFile
+	c, err = New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	buckets, err := c.ListBuckets(ctx)
+	if err != nil {
+		t.Fatalf("ListBuckets with CA bundle: %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "private-ca" {
+		t.Errorf("buckets = %+v", buckets)
+	}
+
+	cfg = tlsTestConfig(srv.URL)
+	cfg.InsecureSkipVerify = true
+	c, err = New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(ctx); err != nil {
+		t.Errorf("ListBuckets with InsecureSkipVerify: %v", err)
+	}
+}
+
+func TestNew_ClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _, serverCert := writeCert(t, dir, "server")
+	clientCertFile, clientKeyFile, _ := writeCert(t, dir, "client")
+	srv := newTLSServer(t, serverCert, clientCertFile)
+	ctx := context.Background()
+
+	cfg := tlsTestConfig(srv.URL)
+	cfg.CABundle = caFile
+	c, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(ctx); err == nil {
+		t.Fatal("expected handshake failure without client certificate")
+	}
+
+	cfg.ClientCert = clientCertFile
+	cfg.ClientKey = clientKeyFile
+	c, err = New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(ctx); err != nil {
+		t.Errorf("ListBuckets with client certificate: %v", err)
+	}
+}
+
+func TestNew_TransportErrors(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  func(*Config)
+	}{
+		{"missing CA bundle", func(c *Config) { c.CABundle = filepath.Join(dir, "missing.pem") }},
+		{"CA bundle without certificates", func(c *Config) { c.CABundle = notPEM }},
+		{"client cert without key", func(c *Config) { c.ClientCert = notPEM }},
+		{"unreadable key pair", func(c *Config) { c.ClientCert, c.ClientKey = notPEM, notPEM }},
+		{"invalid proxy", func(c *Config) { c.ProxyURL = "http://[::1" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tlsTestConfig("https://127.0.0.1:1")
+			tt.cfg(cfg)
+			if _, err := New(ctx, cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNew_ProxyAndPool(t *testing.T) {
+	var proxied atomic.Bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.Host == "s3.internal:9000")
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(listBucketsXML))
+	}))
+	defer proxy.Close()
+
+	cfg := tlsTestConfig("http://s3.internal:9000")
+	cfg.ProxyURL = proxy.URL
+	cfg.MaxIdleConns = 4
+	cfg.MaxIdleConnsPerHost = 2
+	cfg.MaxConnsPerHost = 8
+	cfg.IdleConnTimeout = time.Minute
+
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		t.Fatalf("newHTTPClient: %v", err)
+	}
+	tr := httpClient.GetTransport()
+	if tr.MaxIdleConns != 4 || tr.MaxIdleConnsPerHost != 2 || tr.MaxConnsPerHost != 8 || tr.IdleConnTimeout != time.Minute {
+		t.Errorf("pool settings not applied: %d/%d/%d/%v",
+			tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost, tr.IdleConnTimeout)
+	}
+
+	c, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(context.Background()); err != nil {
+		t.Fatalf("ListBuckets through proxy: %v", err)
+	}
+	if !proxied.Load() {
+		t.Error("expected request to go through the proxy")
+	}
+}
+
+func TestNewHTTPClient_Default(t *testing.T) {
+	httpClient, err := newHTTPClient(&Config{})
+	if err != nil || httpClient != nil {
+		t.Errorf("newHTTPClient() = %v, %v; want nil, nil", httpClient, err)
+	}
+}
+
+func TestResolveEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint   string
+		disableSSL bool
+		want       string
+	}{
+		{"https://ceph.internal", false, "https://ceph.internal"},
+		{"https://ceph.internal", true, "http://ceph.internal"},
+		{"HTTPS://ceph.internal", true, "http://ceph.internal"},
+		{"http://localhost:9000", false, "http://localhost:9000"},
+		{"localhost:9000", false, "https://localhost:9000"},
+		{"localhost:9000", true, "http://localhost:9000"},
+	}
+	for _, tt := range tests {
+		if got := resolveEndpoint(tt.endpoint, tt.disableSSL); got != tt.want {
+			t.Errorf("resolveEndpoint(%q, %v) = %q, want %q", tt.endpoint, tt.disableSSL, got, tt.want)
+		}
+	}
+}
+
+func TestNew_DisableSSL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(listBucketsXML))
+	}))
+	defer srv.Close()
+
+	// An https endpoint is downgraded to plain HTTP when SSL is disabled.
+	cfg := tlsTestConfig("https://" + srv.Listener.Addr().String())
+	cfg.DisableSSL = true
+	c, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(context.Background()); err != nil {
+		t.Errorf("ListBuckets with DisableSSL: %v", err)
+	}
+}
+
+func TestClient_CloseReleasesIdleConnections(t *testing.T) {
+	var mu sync.Mutex
+	states := make(map[http.ConnState]int)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(listBucketsXML))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		mu.Lock()
+		states[state]++
+		mu.Unlock()
+	}
+	srv.Start()
+	defer srv.Close()
+
+	c, err := New(context.Background(), tlsTestConfig(srv.URL))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.ListBuckets(context.Background()); err != nil {
+		t.Fatalf("ListBuckets: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		opened, closed := states[http.StateNew], states[http.StateClosed]
+		mu.Unlock()
+		if opened > 0 && closed == opened {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("opened %d connections, closed %d after Close", opened, closed)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The client stays usable after Close.
+	if _, err := c.ListBuckets(context.Background()); err != nil {
+		t.Errorf("ListBuckets after Close: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package extensions
+
+import (
+	"context"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/tools"
+)
+
+// AdminPolicyInterceptor restricts the connection admin tools to allowed
+// principals. With no principals configured, every caller is blocked.
+type AdminPolicyInterceptor struct {
+	principals []string
+}
+
+// NewAdminPolicyInterceptor creates an interceptor that allows the admin tools
+// only for the given principals.
+func NewAdminPolicyInterceptor(principals []string) *AdminPolicyInterceptor {
+	return &AdminPolicyInterceptor{principals: principals}
+}
+
+// Name returns the interceptor name.
+func (i *AdminPolicyInterceptor) Name() string {
+	return "adminpolicy"
+}
+
+// Intercept blocks admin tools for callers that are not allowed principals.
+func (i *AdminPolicyInterceptor) Intercept(_ context.Context, tc *tools.ToolContext, _ *mcp.CallToolRequest) tools.InterceptResult {
+	if !tools.IsAdminTool(tc.ToolName) {
+		return tools.Allowed()
+	}
+	if len(i.principals) == 0 {
+		return tools.Blocked("connection administration is not allowed for any principal")
+	}
+	if tc.Principal == "" {
+		return tools.Blocked("connection administration requires an authenticated principal")
+	}
+	if !slices.Contains(i.principals, tc.Principal) {
+		return tools.Blocked("principal " + tc.Principal + " is not allowed to administer connections")
+	}
+	return tools.Allowed()
+}
+
+// Ensure AdminPolicyInterceptor implements RequestInterceptor.
+var _ tools.RequestInterceptor = (*AdminPolicyInterceptor)(nil)
@@ -4,6 +4,10 @@ package extensions
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/txn2/mcp-s3/pkg/tools"
 )
 
 // Config holds configuration for all built-in extensions.
@@ -34,6 +38,26 @@ type Config struct {
 
 	// DeniedPrefixes is a list of prefixes that are denied when PrefixACL is enabled.
 	DeniedPrefixes []string
+
+	// Quota enables per-session, per-principal, and per-connection quotas.
+	Quota bool
+
+	// Quotas configures the quota window and limits when Quota is enabled.
+	Quotas QuotaConfig
+
+	// RateLimit enables per-tool and per-connection rate limiting.
+	RateLimit bool
+
+	// RateLimits configures the token buckets when RateLimit is enabled.
+	RateLimits RateLimitConfig
+
+	// Admin enables the connection admin tools (s3_add_connection,
+	// s3_remove_connection, s3_test_connection).
+	Admin bool
+
+	// AdminPrincipals are the principals allowed to use the admin tools.
+	// The admin tools are not enabled without them.
+	AdminPrincipals []string
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -46,6 +70,8 @@ func DefaultConfig() Config {
 		Logging:    false,
 		Audit:      false,
 		PrefixACL:  false,
+		Quota:      false,
+		Quotas:     QuotaConfig{Window: DefaultQuotaWindow},
 	}
 }
 
@@ -59,6 +85,16 @@ func DefaultConfig() Config {
 //   - MCP_S3_EXT_LOGGING: Enable logging (default: false)
 //   - MCP_S3_EXT_AUDIT: Enable audit logging (default: false)
 //   - MCP_S3_EXT_PREFIX_ACL: Enable prefix-based ACL (default: false)
+//   - MCP_S3_EXT_QUOTA: Enable quotas (default: false)
+//   - MCP_S3_QUOTA_WINDOW: Sliding quota window (default: 1h)
+//   - MCP_S3_QUOTA_{SESSION,PRINCIPAL,CONNECTION}_REQUESTS: Max requests per window
+//   - MCP_S3_QUOTA_{SESSION,PRINCIPAL,CONNECTION}_BYTES_READ: Max bytes read per window
+//   - MCP_S3_QUOTA_{SESSION,PRINCIPAL,CONNECTION}_BYTES_WRITTEN: Max bytes written per window
+//   - MCP_S3_EXT_RATELIMIT: Enable rate limiting (default: false)
+//   - MCP_S3_RATELIMIT_TOOLS: Per-tool limits as name=rate[:burst],... ("*" for all others)
+//   - MCP_S3_RATELIMIT_CONNECTIONS: Per-connection limits in the same format
+//   - MCP_S3_EXT_ADMIN: Enable connection admin tools (default: false)
+//   - MCP_S3_ADMIN_PRINCIPALS: Comma-separated principals allowed to use admin tools
 func FromEnv() Config {
 	cfg := DefaultConfig()
 
@@ -90,9 +126,85 @@ func FromEnv() Config {
 		cfg.PrefixACL = parseBool(v, false)
 	}
 
+	if v := os.Getenv("MCP_S3_EXT_QUOTA"); v != "" {
+		cfg.Quota = parseBool(v, false)
+	}
+
+	if v := os.Getenv("MCP_S3_QUOTA_WINDOW"); v != "" {
+		cfg.Quotas.Window = parseDuration(v, cfg.Quotas.Window)
+	}
+
+	cfg.Quotas.Session = quotaLimitsFromEnv("MCP_S3_QUOTA_SESSION")
+	cfg.Quotas.Principal = quotaLimitsFromEnv("MCP_S3_QUOTA_PRINCIPAL")
+	cfg.Quotas.Connection = quotaLimitsFromEnv("MCP_S3_QUOTA_CONNECTION")
+
+	if v := os.Getenv("MCP_S3_EXT_RATELIMIT"); v != "" {
+		cfg.RateLimit = parseBool(v, false)
+	}
+
+	if v := os.Getenv("MCP_S3_RATELIMIT_TOOLS"); v != "" {
+		limits, defaultLimit := ParseRateLimits(v)
+		cfg.RateLimits.Tools = make(map[tools.ToolName]RateLimit, len(limits))
+		for name, limit := range limits {
+			cfg.RateLimits.Tools[tools.ToolName(name)] = limit
+		}
+		cfg.RateLimits.DefaultTool = defaultLimit
+	}
+
+	if v := os.Getenv("MCP_S3_RATELIMIT_CONNECTIONS"); v != "" {
+		cfg.RateLimits.Connections, cfg.RateLimits.DefaultConnection = ParseRateLimits(v)
+	}
+
+	if v := os.Getenv("MCP_S3_EXT_ADMIN"); v != "" {
+		cfg.Admin = parseBool(v, false)
+	}
+
+	if v := os.Getenv("MCP_S3_ADMIN_PRINCIPALS"); v != "" {
+		cfg.AdminPrincipals = parseList(v)
+	}
+
 	return cfg
 }
 
+// quotaLimitsFromEnv reads the _REQUESTS, _BYTES_READ, and _BYTES_WRITTEN
+// variables under the given prefix. Unset or invalid values mean unlimited.
+func quotaLimitsFromEnv(prefix string) QuotaLimits {
+	var limits QuotaLimits
+	if v := os.Getenv(prefix + "_REQUESTS"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			limits.Requests = n
+		}
+	}
+	if v := os.Getenv(prefix + "_BYTES_READ"); v != "" {
+		limits.BytesRead = parseSize(v, 0)
+	}
+	if v := os.Getenv(prefix + "_BYTES_WRITTEN"); v != "" {
+		limits.BytesWritten = parseSize(v, 0)
+	}
+	return limits
+}
+
+// parseDuration parses a duration from a string, returning defaultValue on
+// error or for non-positive values.
+func parseDuration(s string, defaultValue time.Duration) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return defaultValue
+	}
+	return d
+}
+
+// parseList splits a comma-separated list, dropping empty entries.
+func parseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // parseBool parses a boolean from a string, returning defaultValue on error.
 func parseBool(s string, defaultValue bool) bool {
 	v, err := strconv.ParseBool(s)
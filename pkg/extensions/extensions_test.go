@@ -100,6 +100,14 @@ func TestReadOnlyInterceptor(t *testing.T) {
 		assertBool(t, "Allow", false, result.Allow)
 	})
 
+	t.Run("enabled blocks connection changes", func(t *testing.T) {
+		interceptor := NewReadOnlyInterceptor(true)
+		for _, name := range []tools.ToolName{tools.ToolAddConnection, tools.ToolRemoveConnection} {
+			result := interceptor.Intercept(context.Background(), tools.NewToolContext(name, ""), makeCallToolRequest(nil))
+			assertBool(t, "Allow", false, result.Allow)
+		}
+	})
+
 	t.Run("enabled allows read tools", func(t *testing.T) {
 		interceptor := NewReadOnlyInterceptor(true)
 		tc := tools.NewToolContext(tools.ToolListBuckets, "")
@@ -131,6 +139,23 @@ func TestSizeLimitInterceptor(t *testing.T) {
 		result := interceptor.Intercept(context.Background(), tc, req)
 		assertBool(t, "Allow", false, result.Allow)
 	})
+
+	t.Run("connection limits override", func(t *testing.T) {
+		interceptor := NewSizeLimitInterceptor(10*1024*1024, 10).WithConnectionLimits(
+			func(connection string) (int64, int64) {
+				if connection == "scratch" {
+					return 0, 1024
+				}
+				return 0, 0
+			})
+		req := makeCallToolRequest(map[string]any{"content": "this content is way too long"})
+
+		result := interceptor.Intercept(context.Background(), tools.NewToolContext(tools.ToolPutObject, "scratch"), req)
+		assertBool(t, "Allow scratch", true, result.Allow)
+
+		result = interceptor.Intercept(context.Background(), tools.NewToolContext(tools.ToolPutObject, "prod"), req)
+		assertBool(t, "Allow prod", false, result.Allow)
+	})
 }
 
 func TestPrefixACLInterceptor(t *testing.T) {
@@ -609,6 +634,89 @@ func TestPrefixACLInterceptor_ExtractKey(t *testing.T) {
 		assertBool(t, "Allow", false, result.Allow)
 	})
 
+	t.Run("extracts key from archive tools", func(t *testing.T) {
+		interceptor := NewPrefixACLInterceptor(nil, []string{"blocked/"})
+		for _, name := range []tools.ToolName{tools.ToolListArchive, tools.ToolGetArchiveEntry} {
+			tc := tools.NewToolContext(name, "")
+			req := makeCallToolRequest(map[string]any{"bucket": "b", "key": "blocked/site.tar.gz", "entry": "index.html"})
+			result := interceptor.Intercept(context.Background(), tc, req)
+			assertBool(t, "Allow "+string(name), false, result.Allow)
+		}
+	})
+
+	t.Run("extracts key from inspect parquet", func(t *testing.T) {
+		interceptor := NewPrefixACLInterceptor(nil, []string{"blocked/"})
+		tc := tools.NewToolContext(tools.ToolInspectParquet, "")
+		req := makeCallToolRequest(map[string]any{"bucket": "b", "key": "blocked/part-0.parquet", "rows": 5})
+		result := interceptor.Intercept(context.Background(), tc, req)
+		assertBool(t, "Allow", false, result.Allow)
+	})
+
+	t.Run("extracts key from preview table", func(t *testing.T) {
+		interceptor := NewPrefixACLInterceptor(nil, []string{"blocked/"})
+		tc := tools.NewToolContext(tools.ToolPreviewTable, "")
+		req := makeCallToolRequest(map[string]any{"bucket": "b", "key": "blocked/users.csv"})
+		result := interceptor.Intercept(context.Background(), tc, req)
+		assertBool(t, "Allow", false, result.Allow)
+	})
+
+	t.Run("checks the prefix of grep", func(t *testing.T) {
+		interceptor := NewPrefixACLInterceptor(nil, []string{"blocked/"})
+		tc := tools.NewToolContext(tools.ToolGrep, "")
+		for _, tt := range []struct {
+			prefix string
+			allow  bool
+		}{
+			{"blocked/logs/", false},
+			{"blocked", false},
+			{"", false},
+			{"public/", true},
+		} {
+			req := makeCallToolRequest(map[string]any{"bucket": "b", "pattern": "x", "prefix": tt.prefix})
+			result := interceptor.Intercept(context.Background(), tc, req)
+			assertBool(t, "Allow "+tt.prefix, tt.allow, result.Allow)
+		}
+
+		interceptor = NewPrefixACLInterceptor([]string{"allowed/"}, nil)
+		result := interceptor.Intercept(context.Background(), tc, makeCallToolRequest(map[string]any{"bucket": "b", "pattern": "x"}))
+		assertBool(t, "Allow whole bucket", false, result.Allow)
+		result = interceptor.Intercept(context.Background(), tc, makeCallToolRequest(map[string]any{"bucket": "b", "pattern": "x", "prefix": "allowed/logs/"}))
+		assertBool(t, "Allow allowed prefix", true, result.Allow)
+	})
+
+	t.Run("checks the prefix of find objects", func(t *testing.T) {
+		interceptor := NewPrefixACLInterceptor(nil, []string{"blocked/"})
+		tc := tools.NewToolContext(tools.ToolFindObjects, "")
+		for _, prefix := range []string{"blocked/2024/", ""} {
+			req := makeCallToolRequest(map[string]any{"bucket": "b", "prefix": prefix, "suffix": ".csv"})
+			result := interceptor.Intercept(context.Background(), tc, req)
+			assertBool(t, "Allow "+prefix, false, result.Allow)
+		}
+	})
+
+	t.Run("checks the prefix of summarize prefix", func(t *testing.T) {
+		interceptor := NewPrefixACLInterceptor([]string{"allowed/"}, []string{"allowed/private/"})
+		tc := tools.NewToolContext(tools.ToolSummarizePrefix, "")
+		for _, prefix := range []string{"allowed/private/", "allowed/", "other/"} {
+			req := makeCallToolRequest(map[string]any{"bucket": "b", "prefix": prefix})
+			result := interceptor.Intercept(context.Background(), tc, req)
+			assertBool(t, "Allow "+prefix, false, result.Allow)
+		}
+		req := makeCallToolRequest(map[string]any{"bucket": "b", "prefix": "allowed/public/"})
+		result := interceptor.Intercept(context.Background(), tc, req)
+		assertBool(t, "Allow allowed/public/", true, result.Allow)
+	})
+
+	t.Run("checks the prefix of tree", func(t *testing.T) {
+		interceptor := NewPrefixACLInterceptor(nil, []string{"blocked/"})
+		tc := tools.NewToolContext(tools.ToolTree, "")
+		for _, prefix := range []string{"blocked/", ""} {
+			req := makeCallToolRequest(map[string]any{"bucket": "b", "prefix": prefix, "depth": 3})
+			result := interceptor.Intercept(context.Background(), tc, req)
+			assertBool(t, "Allow "+prefix, false, result.Allow)
+		}
+	})
+
 	t.Run("allows when no prefix in request", func(t *testing.T) {
 		interceptor := NewPrefixACLInterceptor(nil, []string{"blocked/"})
 		tc := tools.NewToolContext(tools.ToolListBuckets, "")
@@ -617,3 +725,282 @@ func TestPrefixACLInterceptor_ExtractKey(t *testing.T) {
 		assertBool(t, "Allow", true, result.Allow)
 	})
 }
+
+func TestQuotaTracker_RequestLimit(t *testing.T) {
+	tracker := NewQuotaTracker(QuotaConfig{
+		Window:  time.Minute,
+		Session: QuotaLimits{Requests: 2},
+	})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tc := tools.NewToolContext(tools.ToolListObjects, "prod")
+	tc.SessionID = "s1"
+
+	for i := 0; i < 2; i++ {
+		result := tracker.Intercept(context.Background(), tc, makeCallToolRequest(nil))
+		assertBool(t, "Allow", true, result.Allow)
+	}
+
+	result := tracker.Intercept(context.Background(), tc, makeCallToolRequest(nil))
+	assertBool(t, "Allow", false, result.Allow)
+	if !strings.Contains(result.Reason, "retry after") {
+		t.Errorf("expected retry-after hint in reason, got %q", result.Reason)
+	}
+
+	// Another session is unaffected.
+	other := tools.NewToolContext(tools.ToolListObjects, "prod")
+	other.SessionID = "s2"
+	result = tracker.Intercept(context.Background(), other, makeCallToolRequest(nil))
+	assertBool(t, "Allow", true, result.Allow)
+
+	// Usage slides out of the window.
+	now = now.Add(time.Minute + time.Second)
+	result = tracker.Intercept(context.Background(), tc, makeCallToolRequest(nil))
+	assertBool(t, "Allow", true, result.Allow)
+}
+
+func TestQuotaTracker_ByteLimits(t *testing.T) {
+	tracker := NewQuotaTracker(QuotaConfig{
+		Window:     time.Hour,
+		Connection: QuotaLimits{BytesRead: 100, BytesWritten: 50},
+	})
+
+	read := tools.NewToolContext(tools.ToolGetObject, "prod")
+	assertBool(t, "Allow", true, tracker.Intercept(context.Background(), read, makeCallToolRequest(nil)).Allow)
+	read.AddBytesRead(150)
+	_, _ = tracker.After(context.Background(), read, tools.TextResult("ok"), nil)
+
+	t.Run("read budget blocks reads", func(t *testing.T) {
+		tc := tools.NewToolContext(tools.ToolGetObject, "prod")
+		assertBool(t, "Allow", false, tracker.Intercept(context.Background(), tc, makeCallToolRequest(nil)).Allow)
+	})
+
+	t.Run("read budget does not block writes", func(t *testing.T) {
+		tc := tools.NewToolContext(tools.ToolPutObject, "prod")
+		assertBool(t, "Allow", true, tracker.Intercept(context.Background(), tc, makeCallToolRequest(nil)).Allow)
+	})
+
+	t.Run("other connections are unaffected", func(t *testing.T) {
+		tc := tools.NewToolContext(tools.ToolGetObject, "scratch")
+		assertBool(t, "Allow", true, tracker.Intercept(context.Background(), tc, makeCallToolRequest(nil)).Allow)
+	})
+
+	t.Run("get_quota is never blocked", func(t *testing.T) {
+		tc := tools.NewToolContext(tools.ToolGetQuota, "prod")
+		assertBool(t, "Allow", true, tracker.Intercept(context.Background(), tc, makeCallToolRequest(nil)).Allow)
+	})
+}
+
+func TestQuotaTracker_QuotaUsage(t *testing.T) {
+	tracker := NewQuotaTracker(QuotaConfig{
+		Window:    time.Hour,
+		Session:   QuotaLimits{Requests: 1},
+		Principal: QuotaLimits{BytesRead: 1024},
+	})
+
+	tc := tools.NewToolContext(tools.ToolGetObject, "prod")
+	tc.SessionID = "s1"
+	tc.Principal = "alice"
+	tracker.Intercept(context.Background(), tc, makeCallToolRequest(nil))
+	tc.AddBytesRead(10)
+	_, _ = tracker.After(context.Background(), tc, tools.TextResult("ok"), nil)
+
+	usage := tracker.QuotaUsage(tools.QuotaSubject{SessionID: "s1", Principal: "alice", Connection: "prod"})
+
+	// The connection scope has no limits and is not reported.
+	if len(usage) != 2 {
+		t.Fatalf("expected 2 usage entries, got %d", len(usage))
+	}
+	if usage[0].Scope != tools.QuotaScopeSession || !usage[0].Exhausted || usage[0].RetryAfterSeconds <= 0 {
+		t.Errorf("unexpected session usage: %+v", usage[0])
+	}
+	if usage[1].Scope != tools.QuotaScopePrincipal || usage[1].BytesRead.Used != 10 || usage[1].Exhausted {
+		t.Errorf("unexpected principal usage: %+v", usage[1])
+	}
+}
+
+func TestFromEnv_Quota(t *testing.T) {
+	envVars := []string{
+		"MCP_S3_EXT_QUOTA", "MCP_S3_QUOTA_WINDOW",
+		"MCP_S3_QUOTA_SESSION_REQUESTS", "MCP_S3_QUOTA_SESSION_BYTES_READ",
+		"MCP_S3_QUOTA_CONNECTION_BYTES_WRITTEN",
+	}
+	saved := saveEnv(envVars)
+	defer restoreEnv(saved)
+	clearEnv(envVars)
+
+	setEnvVars(map[string]string{
+		"MCP_S3_EXT_QUOTA":                      "true",
+		"MCP_S3_QUOTA_WINDOW":                   "10m",
+		"MCP_S3_QUOTA_SESSION_REQUESTS":         "500",
+		"MCP_S3_QUOTA_SESSION_BYTES_READ":       "1GB",
+		"MCP_S3_QUOTA_CONNECTION_BYTES_WRITTEN": "10MB",
+	})
+
+	cfg := FromEnv()
+	assertBool(t, "Quota", true, cfg.Quota)
+	if cfg.Quotas.Window != 10*time.Minute {
+		t.Errorf("Window = %v, want 10m", cfg.Quotas.Window)
+	}
+	assertInt64(t, "Session.Requests", 500, cfg.Quotas.Session.Requests)
+	assertInt64(t, "Session.BytesRead", 1024*1024*1024, cfg.Quotas.Session.BytesRead)
+	assertInt64(t, "Connection.BytesWritten", 10*1024*1024, cfg.Quotas.Connection.BytesWritten)
+	assertBool(t, "Principal.IsZero", true, cfg.Quotas.Principal.IsZero())
+}
+
+func TestRateLimitInterceptor_ToolAndConnection(t *testing.T) {
+	limiter := NewRateLimitInterceptor(RateLimitConfig{
+		Tools:             map[tools.ToolName]RateLimit{tools.ToolListObjects: {Rate: 1, Burst: 2}},
+		DefaultConnection: RateLimit{Rate: 10, Burst: 3},
+	})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	list := tools.NewToolContext(tools.ToolListObjects, "prod")
+	for i := 0; i < 2; i++ {
+		result := limiter.Intercept(context.Background(), list, makeCallToolRequest(nil))
+		assertBool(t, "Allow", true, result.Allow)
+	}
+
+	result := limiter.Intercept(context.Background(), list, makeCallToolRequest(nil))
+	assertBool(t, "Allow", false, result.Allow)
+	if !strings.Contains(result.Reason, "tool s3_list_objects") || !strings.Contains(result.Reason, "retry after 1s") {
+		t.Errorf("unexpected reason %q", result.Reason)
+	}
+
+	// An unlimited tool still draws from the connection bucket, which the
+	// rejected call above did not charge.
+	get := tools.NewToolContext(tools.ToolGetObject, "prod")
+	result = limiter.Intercept(context.Background(), get, makeCallToolRequest(nil))
+	assertBool(t, "Allow", true, result.Allow)
+	result = limiter.Intercept(context.Background(), get, makeCallToolRequest(nil))
+	assertBool(t, "Allow", false, result.Allow)
+	if !strings.Contains(result.Reason, "connection prod") {
+		t.Errorf("unexpected reason %q", result.Reason)
+	}
+
+	// Other connections have their own bucket.
+	result = limiter.Intercept(context.Background(), tools.NewToolContext(tools.ToolGetObject, "staging"), makeCallToolRequest(nil))
+	assertBool(t, "Allow", true, result.Allow)
+
+	// Tokens refill over time.
+	now = now.Add(time.Second)
+	result = limiter.Intercept(context.Background(), list, makeCallToolRequest(nil))
+	assertBool(t, "Allow", true, result.Allow)
+}
+
+func TestRateLimitInterceptor_Unlimited(t *testing.T) {
+	limiter := NewRateLimitInterceptor(RateLimitConfig{})
+	tc := tools.NewToolContext(tools.ToolListObjects, "prod")
+	for i := 0; i < 100; i++ {
+		result := limiter.Intercept(context.Background(), tc, makeCallToolRequest(nil))
+		assertBool(t, "Allow", true, result.Allow)
+	}
+}
+
+func TestParseRateLimits(t *testing.T) {
+	limits, defaultLimit := ParseRateLimits("s3_list_objects=5:10, s3_get_object=0.5,*=20,bad,x=abc,y=1:0")
+
+	if got := limits["s3_list_objects"]; got != (RateLimit{Rate: 5, Burst: 10}) {
+		t.Errorf("s3_list_objects = %+v", got)
+	}
+	if got := limits["s3_get_object"]; got != (RateLimit{Rate: 0.5}) {
+		t.Errorf("s3_get_object = %+v", got)
+	}
+	if defaultLimit != (RateLimit{Rate: 20}) {
+		t.Errorf("default = %+v", defaultLimit)
+	}
+	if len(limits) != 2 {
+		t.Errorf("expected invalid entries to be skipped, got %v", limits)
+	}
+	if b := (RateLimit{Rate: 0.5}).burst(); b != 1 {
+		t.Errorf("burst for rate 0.5 = %v, want 1", b)
+	}
+}
+
+func TestFromEnv_RateLimit(t *testing.T) {
+	envVars := []string{"MCP_S3_EXT_RATELIMIT", "MCP_S3_RATELIMIT_TOOLS", "MCP_S3_RATELIMIT_CONNECTIONS"}
+	saved := saveEnv(envVars)
+	defer restoreEnv(saved)
+	clearEnv(envVars)
+
+	setEnvVars(map[string]string{
+		"MCP_S3_EXT_RATELIMIT":         "true",
+		"MCP_S3_RATELIMIT_TOOLS":       "s3_list_objects=2:4",
+		"MCP_S3_RATELIMIT_CONNECTIONS": "prod=10,*=50",
+	})
+
+	cfg := FromEnv()
+	assertBool(t, "RateLimit", true, cfg.RateLimit)
+	if got := cfg.RateLimits.Tools[tools.ToolListObjects]; got != (RateLimit{Rate: 2, Burst: 4}) {
+		t.Errorf("Tools[s3_list_objects] = %+v", got)
+	}
+	if got := cfg.RateLimits.Connections["prod"]; got != (RateLimit{Rate: 10}) {
+		t.Errorf("Connections[prod] = %+v", got)
+	}
+	if cfg.RateLimits.DefaultConnection != (RateLimit{Rate: 50}) {
+		t.Errorf("DefaultConnection = %+v", cfg.RateLimits.DefaultConnection)
+	}
+	assertBool(t, "DefaultTool.IsZero", true, cfg.RateLimits.DefaultTool.IsZero())
+}
+
+func TestFromEnv_Admin(t *testing.T) {
+	envVars := []string{"MCP_S3_EXT_ADMIN", "MCP_S3_ADMIN_PRINCIPALS"}
+	saved := saveEnv(envVars)
+	defer restoreEnv(saved)
+	clearEnv(envVars)
+
+	assertBool(t, "Admin default", false, FromEnv().Admin)
+
+	setEnvVars(map[string]string{
+		"MCP_S3_EXT_ADMIN":        "true",
+		"MCP_S3_ADMIN_PRINCIPALS": "alice, bob,,",
+	})
+	cfg := FromEnv()
+	assertBool(t, "Admin", true, cfg.Admin)
+	if len(cfg.AdminPrincipals) != 2 || cfg.AdminPrincipals[0] != "alice" || cfg.AdminPrincipals[1] != "bob" {
+		t.Errorf("AdminPrincipals = %v", cfg.AdminPrincipals)
+	}
+}
+
+func TestAdminPolicyInterceptor(t *testing.T) {
+	adminTool := func(principal string) *tools.ToolContext {
+		tc := tools.NewToolContext(tools.ToolAddConnection, "")
+		tc.Principal = principal
+		return tc
+	}
+	req := makeCallToolRequest(nil)
+
+	t.Run("no principals blocks every caller", func(t *testing.T) {
+		for _, principal := range []string{"", "alice"} {
+			result := NewAdminPolicyInterceptor(nil).Intercept(context.Background(), adminTool(principal), req)
+			assertBool(t, "Allow", false, result.Allow)
+		}
+	})
+
+	t.Run("allowed principal", func(t *testing.T) {
+		result := NewAdminPolicyInterceptor([]string{"alice"}).Intercept(context.Background(), adminTool("alice"), req)
+		assertBool(t, "Allow", true, result.Allow)
+	})
+
+	t.Run("other principal is blocked", func(t *testing.T) {
+		result := NewAdminPolicyInterceptor([]string{"alice"}).Intercept(context.Background(), adminTool("mallory"), req)
+		assertBool(t, "Allow", false, result.Allow)
+	})
+
+	t.Run("anonymous caller is blocked", func(t *testing.T) {
+		result := NewAdminPolicyInterceptor([]string{"alice"}).Intercept(context.Background(), adminTool(""), req)
+		assertBool(t, "Allow", false, result.Allow)
+	})
+
+	t.Run("other tools are not affected", func(t *testing.T) {
+		tc := tools.NewToolContext(tools.ToolListBuckets, "")
+		result := NewAdminPolicyInterceptor([]string{"alice"}).Intercept(context.Background(), tc, req)
+		assertBool(t, "Allow", true, result.Allow)
+	})
+
+	if name := NewAdminPolicyInterceptor(nil).Name(); name != "adminpolicy" {
+		t.Errorf("Name() = %q", name)
+	}
+}
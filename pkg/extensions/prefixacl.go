@@ -37,14 +37,16 @@ func (i *PrefixACLInterceptor) Intercept(_ context.Context, tc *tools.ToolContex
 	}
 
 	// Get the key from the request
-	key := i.extractKey(tc.ToolName, args)
-	if key == "" {
+	key, walk := i.extractKey(tc.ToolName, args)
+	if key == "" && !walk {
 		return tools.Allowed()
 	}
 
-	// Check denied prefixes first
+	// Check denied prefixes first. A tool that walks everything under key
+	// also reaches denied prefixes that start with key; an empty key walks
+	// the whole bucket.
 	for _, prefix := range i.deniedPrefixes {
-		if strings.HasPrefix(key, prefix) {
+		if strings.HasPrefix(key, prefix) || (walk && strings.HasPrefix(prefix, key)) {
 			return tools.Blocked("access to prefix " + prefix + " is denied")
 		}
 	}
@@ -66,28 +68,35 @@ func (i *PrefixACLInterceptor) Intercept(_ context.Context, tc *tools.ToolContex
 	return tools.Allowed()
 }
 
-// extractKey extracts the object key from the request arguments based on the tool.
-func (i *PrefixACLInterceptor) extractKey(toolName tools.ToolName, args map[string]any) string {
+// extractKey extracts the object key from the request arguments based on the
+// tool. walk is true for tools that read every object under the returned
+// prefix.
+func (i *PrefixACLInterceptor) extractKey(toolName tools.ToolName, args map[string]any) (key string, walk bool) {
 	switch toolName { //nolint:exhaustive // only key-based tools need prefix checking
-	case tools.ToolGetObject, tools.ToolGetObjectMetadata, tools.ToolPutObject, tools.ToolDeleteObject, tools.ToolPresignURL:
+	case tools.ToolGetObject, tools.ToolGetObjectMetadata, tools.ToolPutObject, tools.ToolDeleteObject, tools.ToolPresignURL,
+		tools.ToolListArchive, tools.ToolGetArchiveEntry, tools.ToolInspectParquet,
+		tools.ToolPreviewTable:
 		if key, ok := args["key"].(string); ok {
-			return key
+			return key, false
 		}
 	case tools.ToolListObjects:
 		if prefix, ok := args["prefix"].(string); ok {
-			return prefix
+			return prefix, false
 		}
+	case tools.ToolGrep, tools.ToolFindObjects, tools.ToolSummarizePrefix, tools.ToolTree:
+		prefix, _ := args["prefix"].(string) //nolint:errcheck // type assertion with ok pattern
+		return prefix, true
 	case tools.ToolCopyObject:
 		// Check both source and destination keys
 		sourceKey, _ := args["source_key"].(string) //nolint:errcheck // type assertion with ok pattern
 		destKey, _ := args["dest_key"].(string)     //nolint:errcheck // type assertion with ok pattern
 		// Return source key for checking; dest key would need separate check
 		if sourceKey != "" {
-			return sourceKey
+			return sourceKey, false
 		}
-		return destKey
+		return destKey, false
 	}
-	return ""
+	return "", false
 }
 
 // Ensure PrefixACLInterceptor implements RequestInterceptor.
@@ -0,0 +1,311 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/tools"
+)
+
+// DefaultQuotaWindow is the default sliding window for quota accounting.
+const DefaultQuotaWindow = time.Hour
+
+// quotaBuckets is the number of slots a sliding window is divided into.
+// Usage older than the window expires one slot at a time.
+const quotaBuckets = 60
+
+// QuotaLimits bounds usage within one quota window. Zero means unlimited.
+type QuotaLimits struct {
+	// Requests is the maximum number of tool calls.
+	Requests int64
+
+	// BytesRead is the maximum number of object content bytes read.
+	BytesRead int64
+
+	// BytesWritten is the maximum number of object content bytes written.
+	BytesWritten int64
+}
+
+// IsZero returns true if no limit is set.
+func (l QuotaLimits) IsZero() bool {
+	return l.Requests <= 0 && l.BytesRead <= 0 && l.BytesWritten <= 0
+}
+
+// QuotaConfig configures quota tracking.
+type QuotaConfig struct {
+	// Window is the sliding window over which usage is summed (default: 1h).
+	Window time.Duration
+
+	// Session limits usage per MCP session.
+	Session QuotaLimits
+
+	// Principal limits usage per authenticated principal.
+	Principal QuotaLimits
+
+	// Connection limits usage per S3 connection.
+	Connection QuotaLimits
+}
+
+// quotaBucket holds the usage recorded during one slot of a window.
+type quotaBucket struct {
+	start        time.Time
+	requests     int64
+	bytesRead    int64
+	bytesWritten int64
+}
+
+// slidingWindow sums usage over the trailing window using fixed slots.
+type slidingWindow struct {
+	buckets [quotaBuckets]quotaBucket
+}
+
+// add records usage in the slot covering now, resetting the slot if it is stale.
+func (w *slidingWindow) add(now time.Time, window time.Duration, requests, bytesRead, bytesWritten int64) {
+	slot := window / quotaBuckets
+	if slot <= 0 {
+		slot = time.Nanosecond
+	}
+	start := now.Truncate(slot)
+	b := &w.buckets[(start.UnixNano()/int64(slot))%quotaBuckets]
+	if !b.start.Equal(start) {
+		*b = quotaBucket{start: start}
+	}
+	b.requests += requests
+	b.bytesRead += bytesRead
+	b.bytesWritten += bytesWritten
+}
+
+// sum returns the usage within the window ending at now and the start of the
+// oldest slot still counted (zero if the window is empty).
+func (w *slidingWindow) sum(now time.Time, window time.Duration) (quotaBucket, time.Time) {
+	var total quotaBucket
+	var oldest time.Time
+	cutoff := now.Add(-window)
+	for i := range w.buckets {
+		b := &w.buckets[i]
+		if b.start.IsZero() || !b.start.After(cutoff) {
+			continue
+		}
+		total.requests += b.requests
+		total.bytesRead += b.bytesRead
+		total.bytesWritten += b.bytesWritten
+		if oldest.IsZero() || b.start.Before(oldest) {
+			oldest = b.start
+		}
+	}
+	return total, oldest
+}
+
+// QuotaTracker enforces request and byte quotas per session, principal, and
+// connection over a sliding window.
+//
+// It is both a RequestInterceptor (blocking calls once a quota is exhausted)
+// and a ToolMiddleware (charging bytes transferred after each call), and it
+// implements tools.QuotaReporter for the s3_get_quota tool. Register the same
+// instance with tools.WithInterceptor, tools.WithMiddleware, and
+// tools.WithQuotaReporter.
+type QuotaTracker struct {
+	cfg       QuotaConfig
+	windows   map[string]*slidingWindow
+	lastPrune time.Time
+	mu        sync.Mutex
+
+	// now returns the current time (overridable for testing).
+	now func() time.Time
+}
+
+// NewQuotaTracker creates a quota tracker with the given configuration.
+func NewQuotaTracker(cfg QuotaConfig) *QuotaTracker {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultQuotaWindow
+	}
+	return &QuotaTracker{
+		cfg:     cfg,
+		windows: make(map[string]*slidingWindow),
+		now:     time.Now,
+	}
+}
+
+// Name returns the interceptor and middleware name.
+func (q *QuotaTracker) Name() string {
+	return "quota"
+}
+
+// quotaScope pairs a tracked key with the limits that apply to it.
+type quotaScope struct {
+	scope  string
+	key    string
+	limits QuotaLimits
+}
+
+// scopes returns the limited scopes that apply to the subject, skipping
+// empty keys and scopes without any configured limit.
+func (q *QuotaTracker) scopes(subject tools.QuotaSubject) []quotaScope {
+	candidates := []quotaScope{
+		{scope: tools.QuotaScopeSession, key: subject.SessionID, limits: q.cfg.Session},
+		{scope: tools.QuotaScopePrincipal, key: subject.Principal, limits: q.cfg.Principal},
+		{scope: tools.QuotaScopeConnection, key: subject.Connection, limits: q.cfg.Connection},
+	}
+	scopes := make([]quotaScope, 0, len(candidates))
+	for _, s := range candidates {
+		if s.key != "" && !s.limits.IsZero() {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// window returns the sliding window for a scope, creating it if needed.
+// Caller must hold q.mu.
+func (q *QuotaTracker) window(s quotaScope) *slidingWindow {
+	id := s.scope + "\x00" + s.key
+	w, ok := q.windows[id]
+	if !ok {
+		w = &slidingWindow{}
+		q.windows[id] = w
+	}
+	return w
+}
+
+// prune drops windows with no usage left so that ended sessions do not
+// accumulate. It runs at most once per window. Caller must hold q.mu.
+func (q *QuotaTracker) prune(now time.Time) {
+	if now.Sub(q.lastPrune) < q.cfg.Window {
+		return
+	}
+	q.lastPrune = now
+	for id, w := range q.windows {
+		if _, oldest := w.sum(now, q.cfg.Window); oldest.IsZero() {
+			delete(q.windows, id)
+		}
+	}
+}
+
+// subjectFromToolContext builds the quota subject for a tool call.
+func subjectFromToolContext(tc *tools.ToolContext) tools.QuotaSubject {
+	return tools.QuotaSubject{
+		SessionID:  tc.SessionID,
+		Principal:  tc.Principal,
+		Connection: tc.ConnectionName,
+	}
+}
+
+// Intercept blocks the call if any applicable quota is exhausted and otherwise
+// charges one request against each scope. Byte quotas only block the tools
+// that consume them: read quotas block read tools, write quotas block write tools.
+func (q *QuotaTracker) Intercept(_ context.Context, tc *tools.ToolContext, _ *mcp.CallToolRequest) tools.InterceptResult {
+	if tc.ToolName == tools.ToolGetQuota {
+		return tools.Allowed()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	q.prune(now)
+	scopes := q.scopes(subjectFromToolContext(tc))
+	isWrite := tools.IsWriteTool(tc.ToolName)
+
+	for _, s := range scopes {
+		usage, oldest := q.window(s).sum(now, q.cfg.Window)
+		if reason := exhaustedReason(s, usage, isWrite); reason != "" {
+			return tools.Blocked(fmt.Sprintf("%s quota for %s exceeded: %s; retry after %ds",
+				s.scope, s.key, reason, q.retryAfter(now, oldest)))
+		}
+	}
+
+	for _, s := range scopes {
+		q.window(s).add(now, q.cfg.Window, 1, 0, 0)
+	}
+	return tools.Allowed()
+}
+
+// exhaustedReason describes which limit of s is exhausted by usage, or returns
+// an empty string if the call may proceed.
+func exhaustedReason(s quotaScope, usage quotaBucket, isWrite bool) string {
+	limits := s.limits
+	switch {
+	case limits.Requests > 0 && usage.requests >= limits.Requests:
+		return fmt.Sprintf("%d of %d requests used", usage.requests, limits.Requests)
+	case !isWrite && limits.BytesRead > 0 && usage.bytesRead >= limits.BytesRead:
+		return fmt.Sprintf("%d of %d bytes read", usage.bytesRead, limits.BytesRead)
+	case isWrite && limits.BytesWritten > 0 && usage.bytesWritten >= limits.BytesWritten:
+		return fmt.Sprintf("%d of %d bytes written", usage.bytesWritten, limits.BytesWritten)
+	}
+	return ""
+}
+
+// retryAfter returns the number of whole seconds until the oldest counted
+// slot leaves the window, which is when usage next decreases.
+func (q *QuotaTracker) retryAfter(now, oldest time.Time) int64 {
+	if oldest.IsZero() {
+		return 0
+	}
+	wait := oldest.Add(q.cfg.Window).Sub(now)
+	if wait <= 0 {
+		return 1
+	}
+	return int64((wait + time.Second - 1) / time.Second)
+}
+
+// Before is a no-op; requests are charged in Intercept.
+func (q *QuotaTracker) Before(ctx context.Context, _ *tools.ToolContext) (context.Context, error) {
+	return ctx, nil
+}
+
+// After charges the bytes read and written by the tool call.
+func (q *QuotaTracker) After(
+	_ context.Context, tc *tools.ToolContext, result *mcp.CallToolResult, handlerErr error,
+) (*mcp.CallToolResult, error) {
+	read, written := tc.BytesRead(), tc.BytesWritten()
+	if read == 0 && written == 0 {
+		return result, handlerErr
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	for _, s := range q.scopes(subjectFromToolContext(tc)) {
+		q.window(s).add(now, q.cfg.Window, 0, read, written)
+	}
+	return result, handlerErr
+}
+
+// QuotaUsage reports current usage for each scope that applies to subject.
+func (q *QuotaTracker) QuotaUsage(subject tools.QuotaSubject) []tools.QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	scopes := q.scopes(subject)
+	usages := make([]tools.QuotaUsage, 0, len(scopes))
+	for _, s := range scopes {
+		usage, oldest := q.window(s).sum(now, q.cfg.Window)
+		u := tools.QuotaUsage{
+			Scope:         s.scope,
+			Key:           s.key,
+			WindowSeconds: int64(q.cfg.Window / time.Second),
+			Requests:      tools.QuotaCounter{Used: usage.requests, Limit: s.limits.Requests},
+			BytesRead:     tools.QuotaCounter{Used: usage.bytesRead, Limit: s.limits.BytesRead},
+			BytesWritten:  tools.QuotaCounter{Used: usage.bytesWritten, Limit: s.limits.BytesWritten},
+		}
+		if exhaustedReason(s, usage, false) != "" || exhaustedReason(s, usage, true) != "" {
+			u.Exhausted = true
+			u.RetryAfterSeconds = q.retryAfter(now, oldest)
+		}
+		usages = append(usages, u)
+	}
+	return usages
+}
+
+// Ensure QuotaTracker implements the extension interfaces it is registered as.
+var (
+	_ tools.RequestInterceptor = (*QuotaTracker)(nil)
+	_ tools.ToolMiddleware     = (*QuotaTracker)(nil)
+	_ tools.QuotaReporter      = (*QuotaTracker)(nil)
+)
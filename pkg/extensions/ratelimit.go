@@ -0,0 +1,237 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/tools"
+)
+
+// RateLimit is a token bucket refilled at Rate tokens per second and holding
+// at most Burst tokens. Each tool call takes one token. A non-positive Rate
+// means unlimited.
+type RateLimit struct {
+	// Rate is the sustained number of calls allowed per second.
+	Rate float64
+
+	// Burst is the number of calls allowed at once (default: Rate rounded up, at least 1).
+	Burst int
+}
+
+// IsZero returns true if the limit does not restrict calls.
+func (l RateLimit) IsZero() bool {
+	return l.Rate <= 0
+}
+
+// burst returns the bucket capacity.
+func (l RateLimit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return math.Max(1, math.Ceil(l.Rate))
+}
+
+// RateLimitConfig configures per-tool and per-connection rate limits.
+// A call must obtain a token from both its tool bucket and its connection
+// bucket to proceed.
+type RateLimitConfig struct {
+	// Tools sets limits for specific tools.
+	Tools map[tools.ToolName]RateLimit
+
+	// DefaultTool applies to tools without an entry in Tools.
+	DefaultTool RateLimit
+
+	// Connections sets limits for specific connections.
+	Connections map[string]RateLimit
+
+	// DefaultConnection applies to connections without an entry in Connections.
+	DefaultConnection RateLimit
+}
+
+// tokenBucket tracks the tokens available to one tool or connection.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accrued since the last refill, capped at the burst.
+func (b *tokenBucket) refill(now time.Time, limit RateLimit) {
+	if b.last.IsZero() {
+		b.tokens = limit.burst()
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(limit.burst(), b.tokens+elapsed*limit.Rate)
+	}
+	b.last = now
+}
+
+// wait returns how long until the bucket holds a whole token.
+func (b *tokenBucket) wait(limit RateLimit) time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// RateLimitInterceptor throttles tool calls with token buckets per tool name
+// and per connection. Calls over the limit are rejected with a retry-after
+// hint rather than queued, so a runaway client backs off before the storage
+// provider starts returning SlowDown errors.
+type RateLimitInterceptor struct {
+	cfg         RateLimitConfig
+	tools       map[tools.ToolName]*tokenBucket
+	connections map[string]*tokenBucket
+	mu          sync.Mutex
+
+	// now returns the current time (overridable for testing).
+	now func() time.Time
+}
+
+// NewRateLimitInterceptor creates a rate limit interceptor with the given configuration.
+func NewRateLimitInterceptor(cfg RateLimitConfig) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		cfg:         cfg,
+		tools:       make(map[tools.ToolName]*tokenBucket),
+		connections: make(map[string]*tokenBucket),
+		now:         time.Now,
+	}
+}
+
+// Name returns the interceptor name.
+func (i *RateLimitInterceptor) Name() string {
+	return "ratelimit"
+}
+
+// toolLimit returns the limit that applies to a tool.
+func (i *RateLimitInterceptor) toolLimit(name tools.ToolName) RateLimit {
+	if limit, ok := i.cfg.Tools[name]; ok {
+		return limit
+	}
+	return i.cfg.DefaultTool
+}
+
+// connectionLimit returns the limit that applies to a connection.
+func (i *RateLimitInterceptor) connectionLimit(name string) RateLimit {
+	if limit, ok := i.cfg.Connections[name]; ok {
+		return limit
+	}
+	return i.cfg.DefaultConnection
+}
+
+// limitedBucket pairs a bucket with its limit and a label for error messages.
+type limitedBucket struct {
+	label  string
+	bucket *tokenBucket
+	limit  RateLimit
+}
+
+// buckets returns the buckets a call must draw from. Caller must hold i.mu.
+func (i *RateLimitInterceptor) buckets(tc *tools.ToolContext) []limitedBucket {
+	buckets := make([]limitedBucket, 0, 2)
+
+	if limit := i.toolLimit(tc.ToolName); !limit.IsZero() {
+		b, ok := i.tools[tc.ToolName]
+		if !ok {
+			b = &tokenBucket{}
+			i.tools[tc.ToolName] = b
+		}
+		buckets = append(buckets, limitedBucket{label: "tool " + string(tc.ToolName), bucket: b, limit: limit})
+	}
+
+	if tc.ConnectionName != "" {
+		if limit := i.connectionLimit(tc.ConnectionName); !limit.IsZero() {
+			b, ok := i.connections[tc.ConnectionName]
+			if !ok {
+				b = &tokenBucket{}
+				i.connections[tc.ConnectionName] = b
+			}
+			buckets = append(buckets, limitedBucket{label: "connection " + tc.ConnectionName, bucket: b, limit: limit})
+		}
+	}
+
+	return buckets
+}
+
+// Intercept takes a token from the tool and connection buckets, blocking the
+// call if either is empty. Tokens are only taken when both buckets have one.
+func (i *RateLimitInterceptor) Intercept(_ context.Context, tc *tools.ToolContext, _ *mcp.CallToolRequest) tools.InterceptResult {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	buckets := i.buckets(tc)
+	for _, lb := range buckets {
+		lb.bucket.refill(now, lb.limit)
+	}
+
+	for _, lb := range buckets {
+		if wait := lb.bucket.wait(lb.limit); wait > 0 {
+			return tools.Blocked(fmt.Sprintf("rate limit exceeded for %s (%g/s); retry after %ds",
+				lb.label, lb.limit.Rate, retryAfterSeconds(wait)))
+		}
+	}
+
+	for _, lb := range buckets {
+		lb.bucket.tokens--
+	}
+	return tools.Allowed()
+}
+
+// retryAfterSeconds rounds a wait up to whole seconds, with a minimum of one.
+func retryAfterSeconds(wait time.Duration) int64 {
+	secs := int64((wait + time.Second - 1) / time.Second)
+	if secs < 1 {
+		return 1
+	}
+	return secs
+}
+
+// ParseRateLimits parses a comma-separated list of name=rate[:burst] entries,
+// such as "s3_list_objects=5:10,*=20". The name "*" sets the default limit.
+// Invalid entries are skipped.
+func ParseRateLimits(s string) (limits map[string]RateLimit, defaultLimit RateLimit) {
+	limits = make(map[string]RateLimit)
+	for _, entry := range strings.Split(s, ",") {
+		name, spec, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			continue
+		}
+		limit, ok := parseRateLimit(spec)
+		if !ok {
+			continue
+		}
+		if name == "*" {
+			defaultLimit = limit
+		} else {
+			limits[name] = limit
+		}
+	}
+	return limits, defaultLimit
+}
+
+// parseRateLimit parses a rate[:burst] spec.
+func parseRateLimit(spec string) (RateLimit, bool) {
+	rateStr, burstStr, hasBurst := strings.Cut(spec, ":")
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return RateLimit{}, false
+	}
+	limit := RateLimit{Rate: rate}
+	if hasBurst {
+		burst, err := strconv.Atoi(burstStr)
+		if err != nil || burst <= 0 {
+			return RateLimit{}, false
+		}
+		limit.Burst = burst
+	}
+	return limit, true
+}
+
+// Ensure RateLimitInterceptor implements RequestInterceptor.
+var _ tools.RequestInterceptor = (*RateLimitInterceptor)(nil)
@@ -11,10 +11,15 @@ import (
 	"github.com/txn2/mcp-s3/pkg/tools"
 )
 
+// ConnectionSizeLimits returns the size limits configured on a connection.
+// A zero value means the connection uses the interceptor's own limit.
+type ConnectionSizeLimits func(connection string) (maxGetSize, maxPutSize int64)
+
 // SizeLimitInterceptor enforces size limits on object operations.
 type SizeLimitInterceptor struct {
-	maxGetSize int64
-	maxPutSize int64
+	maxGetSize       int64
+	maxPutSize       int64
+	connectionLimits ConnectionSizeLimits
 }
 
 // NewSizeLimitInterceptor creates a new size limit interceptor.
@@ -25,24 +30,49 @@ func NewSizeLimitInterceptor(maxGetSize, maxPutSize int64) *SizeLimitInterceptor
 	}
 }
 
+// WithConnectionLimits lets connections override the interceptor's limits,
+// such as the per-connection limits of a multiserver.Manager.
+func (i *SizeLimitInterceptor) WithConnectionLimits(fn ConnectionSizeLimits) *SizeLimitInterceptor {
+	i.connectionLimits = fn
+	return i
+}
+
 // Name returns the interceptor name.
 func (i *SizeLimitInterceptor) Name() string {
 	return "sizelimit"
 }
 
+// limits returns the size limits that apply to a connection.
+func (i *SizeLimitInterceptor) limits(connection string) (maxGetSize, maxPutSize int64) {
+	maxGetSize, maxPutSize = i.maxGetSize, i.maxPutSize
+	if i.connectionLimits == nil || connection == "" {
+		return maxGetSize, maxPutSize
+	}
+	connGet, connPut := i.connectionLimits(connection)
+	if connGet > 0 {
+		maxGetSize = connGet
+	}
+	if connPut > 0 {
+		maxPutSize = connPut
+	}
+	return maxGetSize, maxPutSize
+}
+
 // Intercept checks size limits for PUT operations.
 // GET size limits are handled in the tool itself since we need to check the object size.
 func (i *SizeLimitInterceptor) Intercept(_ context.Context, tc *tools.ToolContext, request *mcp.CallToolRequest) tools.InterceptResult {
+	maxGetSize, maxPutSize := i.limits(tc.ConnectionName)
+
 	// Only check PUT operations for content size
 	if tc.ToolName != tools.ToolPutObject {
 		// Store the limits in context for tools to use
-		tc.Set("max_get_size", i.maxGetSize)
-		tc.Set("max_put_size", i.maxPutSize)
+		tc.Set("max_get_size", maxGetSize)
+		tc.Set("max_put_size", maxPutSize)
 		return tools.Allowed()
 	}
 
 	// Check content size for PUT
-	if i.maxPutSize <= 0 {
+	if maxPutSize <= 0 {
 		return tools.Allowed()
 	}
 
@@ -74,8 +104,8 @@ func (i *SizeLimitInterceptor) Intercept(_ context.Context, tc *tools.ToolContex
 		size = int64(len(content))
 	}
 
-	if size > i.maxPutSize {
-		return tools.Blocked(fmt.Sprintf("content size %d bytes exceeds limit of %d bytes", size, i.maxPutSize))
+	if size > maxPutSize {
+		return tools.Blocked(fmt.Sprintf("content size %d bytes exceeds limit of %d bytes", size, maxPutSize))
 	}
 
 	return tools.Allowed()
@@ -0,0 +1,321 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression formats recognized by DetectCompression.
+const (
+	CompressionNone  = ""
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionBzip2 = "bzip2"
+)
+
+// DefaultMaxDecompressedSize is the decompressed-size limit used when none is
+// configured.
+const DefaultMaxDecompressedSize = 100 * 1024 * 1024
+
+// ErrDecompressedTooLarge is returned when decompressed content exceeds the
+// configured limit. A small object that expands past the limit is most likely
+// a decompression bomb.
+var ErrDecompressedTooLarge = errors.New("decompressed content exceeds size limit")
+
+// sniffLen is the number of leading bytes DetectCompression needs to
+// recognize every supported format.
+const sniffLen = 10
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	// A bzip2 stream starts with "BZh", the block size, and either the
+	// block magic (the BCD digits of pi) or, when empty, the end-of-stream
+	// magic (the digits of sqrt(pi)).
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2EndMagic   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// DetectCompression returns the compression format of an object from its
+// Content-Encoding, its key's extension, and its leading bytes. The header
+// and extension name a candidate format; head, when it holds at least the
+// first bytes of the object, must confirm it. Content whose leading bytes
+// identify a format is reported as that format whatever the header or key
+// say, so objects that are compressed but labelled otherwise are still
+// recognized.
+func DetectCompression(contentEncoding, key string, head []byte) string {
+	if format := sniffCompression(head); format != CompressionNone {
+		return format
+	}
+	if len(head) > 0 {
+		return CompressionNone
+	}
+	if format := compressionFromEncoding(contentEncoding); format != CompressionNone {
+		return format
+	}
+	return compressionFromKey(key)
+}
+
+// sniffCompression identifies a format from its magic bytes.
+func sniffCompression(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return CompressionGzip
+	case bytes.HasPrefix(head, zstdMagic):
+		return CompressionZstd
+	case len(head) >= sniffLen && bytes.HasPrefix(head, []byte("BZh")) && head[3] >= '1' && head[3] <= '9' &&
+		(bytes.Equal(head[4:10], bzip2BlockMagic) || bytes.Equal(head[4:10], bzip2EndMagic)):
+		return CompressionBzip2
+	}
+	return CompressionNone
+}
+
+// compressionFromEncoding maps a Content-Encoding header to a format.
+func compressionFromEncoding(contentEncoding string) string {
+	for _, enc := range strings.Split(strings.ToLower(contentEncoding), ",") {
+		switch strings.TrimSpace(enc) {
+		case "gzip", "x-gzip":
+			return CompressionGzip
+		case "zstd":
+			return CompressionZstd
+		case "bzip2", "x-bzip2":
+			return CompressionBzip2
+		}
+	}
+	return CompressionNone
+}
+
+// compressionFromKey maps a key's extension to a format.
+func compressionFromKey(key string) string {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".gz", ".gzip", ".tgz":
+		return CompressionGzip
+	case ".zst", ".zstd":
+		return CompressionZstd
+	case ".bz2", ".tbz2":
+		return CompressionBzip2
+	}
+	return CompressionNone
+}
+
+// StripCompressionExt returns key without a trailing compression extension,
+// so "logs/app.log.gz" becomes "logs/app.log". Tarball extensions such as
+// ".tgz" become ".tar".
+func StripCompressionExt(key string) string {
+	ext := path.Ext(key)
+	switch strings.ToLower(ext) {
+	case ".gz", ".gzip", ".zst", ".zstd", ".bz2":
+		return strings.TrimSuffix(key, ext)
+	case ".tgz", ".tbz2":
+		return strings.TrimSuffix(key, ext) + ".tar"
+	}
+	return key
+}
+
+// NewDecompressReader returns a reader of the content of r decompressed from
+// format. CompressionNone returns r unchanged.
+func NewDecompressReader(r io.Reader, format string) (io.ReadCloser, error) {
+	switch format {
+	case CompressionNone:
+		return io.NopCloser(r), nil
+	case CompressionGzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip data: %w", err)
+		}
+		return zr, nil
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid zstd data: %w", err)
+		}
+		return zr.IOReadCloser(), nil
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	}
+	return nil, fmt.Errorf("unsupported compression format %q", format)
+}
+
+// Decompress decompresses data from format. At most limit bytes are returned
+// and truncated reports whether the content continued past them. A limit of
+// zero or less means DefaultMaxDecompressedSize.
+func Decompress(data []byte, format string, limit int64) (out []byte, truncated bool, err error) {
+	if limit <= 0 {
+		limit = DefaultMaxDecompressedSize
+	}
+	zr, err := NewDecompressReader(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = zr.Close() }()
+
+	out, err = io.ReadAll(io.LimitReader(zr, limit+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decompress %s data: %w", format, err)
+	}
+	if int64(len(out)) > limit {
+		return out[:limit], true, nil
+	}
+	return out, false, nil
+}
+
+// DecompressedContent is object content with any compression removed.
+type DecompressedContent struct {
+	// Data is the decompressed content.
+	Data []byte
+
+	// Compression is the format the object was stored in, or
+	// CompressionNone.
+	Compression string
+
+	// CompressedSize is the size of the object as stored.
+	CompressedSize int64
+
+	// Size is the size of Data.
+	Size int64
+}
+
+// DecompressingContentProvider wraps a ContentProvider so that gzip, zstd,
+// and bzip2 objects are returned decompressed. Content that would decompress
+// past the size limit fails with ErrDecompressedTooLarge.
+type DecompressingContentProvider struct {
+	inner ContentProvider
+	limit int64
+}
+
+// NewDecompressingContentProvider creates a decompressing content provider.
+// A limit of zero or less means DefaultMaxDecompressedSize.
+func NewDecompressingContentProvider(inner ContentProvider, limit int64) *DecompressingContentProvider {
+	if limit <= 0 {
+		limit = DefaultMaxDecompressedSize
+	}
+	return &DecompressingContentProvider{inner: inner, limit: limit}
+}
+
+// GetDecompressed retrieves an object's content, decompressed, with its
+// stored and decompressed sizes.
+func (p *DecompressingContentProvider) GetDecompressed(ctx context.Context, ref *ObjectReference) (*DecompressedContent, error) {
+	data, err := p.inner.GetContent(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	result := &DecompressedContent{
+		Data:           data,
+		Compression:    DetectCompression("", ref.Key, data),
+		CompressedSize: int64(len(data)),
+	}
+	if result.Compression != CompressionNone {
+		out, truncated, err := Decompress(data, result.Compression, p.limit)
+		if err != nil {
+			return nil, err
+		}
+		if truncated {
+			return nil, fmt.Errorf("%w: %s expands past %d bytes", ErrDecompressedTooLarge, ref, p.limit)
+		}
+		result.Data = out
+	}
+	result.Size = int64(len(result.Data))
+	return result, nil
+}
+
+// GetContent implements ContentProvider, returning decompressed content.
+func (p *DecompressingContentProvider) GetContent(ctx context.Context, ref *ObjectReference) ([]byte, error) {
+	content, err := p.GetDecompressed(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return content.Data, nil
+}
+
+// GetContentStream implements ContentProvider, decompressing as the stream is
+// read. Reads fail with ErrDecompressedTooLarge once the limit is passed.
+func (p *DecompressingContentProvider) GetContentStream(ctx context.Context, ref *ObjectReference) (io.ReadCloser, error) {
+	stream, err := p.inner.GetContentStream(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(stream)
+	head, _ := br.Peek(sniffLen)
+	format := DetectCompression("", ref.Key, head)
+	if format == CompressionNone {
+		return readCloser{Reader: br, close: stream.Close}, nil
+	}
+	zr, err := NewDecompressReader(br, format)
+	if err != nil {
+		_ = stream.Close()
+		return nil, err
+	}
+	return readCloser{
+		Reader: &limitReader{r: zr, n: p.limit},
+		close: func() error {
+			return errors.Join(zr.Close(), stream.Close())
+		},
+	}, nil
+}
+
+// GetContentType implements ContentProvider. Compressed objects report the
+// type of their decompressed content, guessed from the key without its
+// compression extension.
+func (p *DecompressingContentProvider) GetContentType(ctx context.Context, ref *ObjectReference) (string, error) {
+	contentType, err := p.inner.GetContentType(ctx, ref)
+	if err != nil || compressionFromKey(ref.Key) == CompressionNone {
+		return contentType, err
+	}
+	if t := mime.TypeByExtension(path.Ext(StripCompressionExt(ref.Key))); t != "" {
+		return t, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// GetSize implements ContentProvider. It returns the stored size; use
+// GetDecompressed for the decompressed size.
+func (p *DecompressingContentProvider) GetSize(ctx context.Context, ref *ObjectReference) (int64, error) {
+	return p.inner.GetSize(ctx, ref)
+}
+
+// Ensure DecompressingContentProvider implements ContentProvider.
+var _ ContentProvider = (*DecompressingContentProvider)(nil)
+
+// readCloser pairs a reader with a close function.
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
+
+// limitReader reads from r and fails with ErrDecompressedTooLarge after n
+// bytes.
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrDecompressedTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n + int(l.n), ErrDecompressedTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,171 @@
+package integration
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// bzip2Hello is "hello bzip2\n" compressed with bzip2.
+var bzip2Hello = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xab, 0x6b,
+	0xa1, 0xf1, 0x00, 0x00, 0x02, 0xd9, 0x80, 0x00, 0x10, 0x40, 0x00, 0x10,
+	0x00, 0x12, 0x64, 0xc0, 0x10, 0x20, 0x00, 0x31, 0x00, 0xd3, 0x4d, 0x04,
+	0x00, 0x1e, 0xa3, 0xef, 0x4e, 0x51, 0xa2, 0x07, 0x8b, 0xb9, 0x22, 0x9c,
+	0x28, 0x48, 0x55, 0xb5, 0xd0, 0xf8, 0x80,
+}
+
+func gzipData(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(s))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zstdData(t *testing.T, s string) []byte {
+	t.Helper()
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer enc.Close()
+	return enc.EncodeAll([]byte(s), nil)
+}
+
+func TestDetectCompression(t *testing.T) {
+	gz := gzipData(t, "x")
+	tests := []struct {
+		name     string
+		encoding string
+		key      string
+		head     []byte
+		want     string
+	}{
+		{"gzip magic", "", "data.bin", gz, CompressionGzip},
+		{"zstd magic", "", "data", zstdData(t, "x"), CompressionZstd},
+		{"bzip2 magic", "", "data", bzip2Hello, CompressionBzip2},
+		{"magic wins over extension", "", "data.zst", gz, CompressionGzip},
+		{"extension without magic", "", "app.log.gz", []byte("plain text"), CompressionNone},
+		{"bzip2 lookalike text", "", "notes.txt", []byte("BZh9 is not a header"), CompressionNone},
+		{"encoding without head", "x-gzip", "data", nil, CompressionGzip},
+		{"extension without head", "", "export.CSV.ZST", nil, CompressionZstd},
+		{"tarball without head", "", "backup.tbz2", nil, CompressionBzip2},
+		{"nothing", "identity", "data.json", nil, CompressionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectCompression(tt.encoding, tt.key, tt.head); got != tt.want {
+				t.Errorf("DetectCompression() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	for format, data := range map[string][]byte{
+		CompressionGzip:  gzipData(t, "hello bzip2\n"),
+		CompressionZstd:  zstdData(t, "hello bzip2\n"),
+		CompressionBzip2: bzip2Hello,
+	} {
+		out, truncated, err := Decompress(data, format, 0)
+		if err != nil || truncated || string(out) != "hello bzip2\n" {
+			t.Errorf("%s: Decompress() = %q, %v, %v", format, out, truncated, err)
+		}
+	}
+
+	bomb := gzipData(t, strings.Repeat("a", 1<<20))
+	out, truncated, err := Decompress(bomb, CompressionGzip, 1000)
+	if err != nil || !truncated || len(out) != 1000 {
+		t.Errorf("limited Decompress() = %d bytes, truncated %v, %v", len(out), truncated, err)
+	}
+
+	if _, _, err := Decompress([]byte("not gzip"), CompressionGzip, 0); err == nil {
+		t.Error("expected an error for invalid gzip data")
+	}
+	if _, _, err := Decompress(nil, "lz4", 0); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestStripCompressionExt(t *testing.T) {
+	tests := map[string]string{
+		"logs/app.log.gz": "logs/app.log",
+		"export.csv.zst":  "export.csv",
+		"backup.tgz":      "backup.tar",
+		"data.json":       "data.json",
+	}
+	for key, want := range tests {
+		if got := StripCompressionExt(key); got != want {
+			t.Errorf("StripCompressionExt(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestDecompressingContentProvider(t *testing.T) {
+	objects := map[string][]byte{
+		"app.log.gz": gzipData(t, "line 1\nline 2\n"),
+		"plain.txt":  []byte("plain"),
+		"bomb.gz":    gzipData(t, strings.Repeat("a", 1<<20)),
+	}
+	inner := NewSimpleContentProvider(
+		func(_ context.Context, ref *ObjectReference) ([]byte, error) {
+			return objects[ref.Key], nil
+		},
+		func(_ context.Context, _ *ObjectReference) (string, error) {
+			return "application/gzip", nil
+		},
+		nil,
+	)
+	provider := NewDecompressingContentProvider(inner, 4096)
+	ctx := context.Background()
+
+	content, err := provider.GetDecompressed(ctx, &ObjectReference{Key: "app.log.gz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content.Data) != "line 1\nline 2\n" || content.Compression != CompressionGzip ||
+		content.Size != 14 || content.CompressedSize != int64(len(objects["app.log.gz"])) {
+		t.Errorf("GetDecompressed() = %+v", content)
+	}
+
+	if data, _ := provider.GetContent(ctx, &ObjectReference{Key: "plain.txt"}); string(data) != "plain" {
+		t.Errorf("GetContent(plain) = %q", data)
+	}
+	if _, err := provider.GetContent(ctx, &ObjectReference{Key: "bomb.gz"}); !errors.Is(err, ErrDecompressedTooLarge) {
+		t.Errorf("GetContent(bomb) error = %v, want ErrDecompressedTooLarge", err)
+	}
+
+	stream, err := provider.GetContentStream(ctx, &ObjectReference{Key: "app.log.gz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(stream)
+	_ = stream.Close()
+	if err != nil || string(data) != "line 1\nline 2\n" {
+		t.Errorf("GetContentStream() = %q, %v", data, err)
+	}
+
+	stream, err = provider.GetContentStream(ctx, &ObjectReference{Key: "bomb.gz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = io.ReadAll(stream)
+	_ = stream.Close()
+	if !errors.Is(err, ErrDecompressedTooLarge) || len(data) != 4096 {
+		t.Errorf("GetContentStream(bomb) read %d bytes, error %v", len(data), err)
+	}
+
+	if ct, _ := provider.GetContentType(ctx, &ObjectReference{Key: "report.json.gz"}); ct != "application/json" {
+		t.Errorf("GetContentType() = %q, want application/json", ct)
+	}
+}
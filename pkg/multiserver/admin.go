@@ -0,0 +1,271 @@
+package multiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+	"github.com/txn2/mcp-s3/pkg/tools"
+)
+
+// Admin changes a Manager's connections on behalf of the connection admin
+// tools. New configurations are probed before they are applied, and changes
+// can be saved back to the configuration file.
+type Admin struct {
+	manager *Manager
+	file    string
+
+	// mu serializes changes so the saved file matches the manager.
+	mu sync.Mutex
+}
+
+// AdminOption configures an Admin.
+type AdminOption func(*Admin)
+
+// WithPersistFile sets the configuration file that changes are saved to when
+// a tool asks to persist them. The format follows the extension, as in
+// LoadFile. Comments and formatting in the file are not preserved.
+func WithPersistFile(path string) AdminOption {
+	return func(a *Admin) {
+		a.file = path
+	}
+}
+
+// NewAdmin creates an admin for manager.
+func NewAdmin(manager *Manager, opts ...AdminOption) *Admin {
+	a := &Admin{manager: manager}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// AddConnection decodes config, probes it, and adds it to the manager. The
+// connection is not added if the probe fails. The default connection cannot
+// be replaced.
+func (a *Admin) AddConnection(ctx context.Context, name string, config map[string]any, replace, persist bool) (tools.HealthStatus, error) {
+	if err := a.checkPersist(persist); err != nil {
+		return tools.HealthStatus{}, err
+	}
+	conn, err := decodeConnection(name, config)
+	if err != nil {
+		return tools.HealthStatus{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.manager.HasConnection(name) && !replace {
+		return tools.HealthStatus{}, fmt.Errorf("connection %q already exists", name)
+	}
+	if name == a.manager.DefaultConnectionName() {
+		return tools.HealthStatus{}, fmt.Errorf("cannot replace the default connection %q", name)
+	}
+
+	status := a.manager.probeConfig(ctx, conn)
+	if status.Status != tools.HealthHealthy {
+		return status, fmt.Errorf("probe failed: %s", status.LastError)
+	}
+	if err := a.manager.AddConnection(*conn, false); err != nil {
+		return status, err
+	}
+
+	if persist {
+		if err := a.save(); err != nil {
+			return status, fmt.Errorf("connection added but not saved: %w", err)
+		}
+	}
+	return status, nil
+}
+
+// RemoveConnection removes the named connection from the manager.
+func (a *Admin) RemoveConnection(_ context.Context, name string, persist bool) error {
+	if err := a.checkPersist(persist); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.manager.RemoveConnection(name); err != nil {
+		return err
+	}
+	if persist {
+		if err := a.save(); err != nil {
+			return fmt.Errorf("connection removed but not saved: %w", err)
+		}
+	}
+	return nil
+}
+
+// TestConnection probes config without adding it, or the named connection
+// when config is nil. Testing an existing connection refreshes its cached
+// health.
+func (a *Admin) TestConnection(ctx context.Context, name string, config map[string]any) (tools.HealthStatus, error) {
+	if config == nil {
+		if !a.manager.HasConnection(name) {
+			return tools.HealthStatus{}, fmt.Errorf("connection %q not found", name)
+		}
+		return a.manager.checkHealth(ctx, name, false), nil
+	}
+
+	if name == "" {
+		name = "test"
+	}
+	conn, err := decodeConnection(name, config)
+	if err != nil {
+		return tools.HealthStatus{}, err
+	}
+	return a.manager.probeConfig(ctx, conn), nil
+}
+
+// checkPersist fails when persisting is requested without a file.
+func (a *Admin) checkPersist(persist bool) error {
+	if persist && a.file == "" {
+		return fmt.Errorf("cannot persist: no configuration file is configured")
+	}
+	return nil
+}
+
+// save writes the manager's configuration to the file. The file is replaced
+// atomically so a watcher never reads a partial write.
+func (a *Admin) save() error {
+	cfg := a.manager.snapshot()
+	cfg.Connections = slices.DeleteFunc(cfg.Connections, func(c ConnectionConfig) bool {
+		return c.discovered
+	})
+
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(a.file), ".json") {
+		data, err = json.MarshalIndent(cfg, "", "  ")
+	} else {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err = enc.Encode(cfg); err == nil {
+			err = enc.Close()
+		}
+		data = buf.Bytes()
+	}
+	if err != nil {
+		return fmt.Errorf("encoding configuration: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(a.file), "."+filepath.Base(a.file)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), a.file)
+}
+
+// adminConnectionInput is the part of a connection configuration that the
+// admin tools accept. Tool callers are less trusted than the configuration
+// file, so fields that read local files, run commands, resolve secret
+// references, or loosen server-wide limits are left out.
+type adminConnectionInput struct {
+	Region            string   `json:"region,omitempty"`
+	Endpoint          string   `json:"endpoint,omitempty"`
+	PresignEndpoint   string   `json:"presign_endpoint,omitempty"`
+	AccessKeyID       string   `json:"access_key_id,omitempty"`
+	SecretAccessKey   string   `json:"secret_access_key,omitempty"`
+	SessionToken      string   `json:"session_token,omitempty"` //#nosec G117 -- AWS credential field
+	UsePathStyle      bool     `json:"use_path_style,omitempty"`
+	Provider          string   `json:"provider,omitempty"`
+	AccountID         string   `json:"account_id,omitempty"`
+	Timeout           Duration `json:"timeout,omitempty"`
+	ReadOnly          bool     `json:"read_only,omitempty"`
+	AllowedBuckets    []string `json:"allowed_buckets,omitempty"`
+	AllowedPrefixes   []string `json:"allowed_prefixes,omitempty"`
+	HealthCheckBucket string   `json:"health_check_bucket,omitempty"`
+}
+
+// decodeConnection converts tool input into a validated connection
+// configuration named name. Fields outside adminConnectionInput are rejected,
+// as are credentials that look like secret references. A connection to a
+// custom endpoint must carry its own keys, so the server's ambient
+// credentials are never used to sign requests to a host a caller chose.
+func decodeConnection(name string, config map[string]any) (*ConnectionConfig, error) {
+	if name == "" {
+		return nil, fmt.Errorf("connection name must not be empty")
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var in adminConnectionInput
+	if err := dec.Decode(&in); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	for field, value := range map[string]string{
+		"access_key_id":     in.AccessKeyID,
+		"secret_access_key": in.SecretAccessKey,
+		"session_token":     in.SessionToken,
+	} {
+		if isSecretReference(value) {
+			return nil, fmt.Errorf("invalid config: %s must be a literal value, not a secret reference", field)
+		}
+	}
+	customEndpoint := in.Endpoint != "" || (in.Provider != "" && !strings.EqualFold(in.Provider, client.ProviderAWS))
+	if customEndpoint && (in.AccessKeyID == "" || in.SecretAccessKey == "") {
+		return nil, fmt.Errorf("invalid config: access_key_id and secret_access_key are required with a custom endpoint or provider")
+	}
+
+	conn := ConnectionConfig{
+		Name:              name,
+		Region:            in.Region,
+		Endpoint:          in.Endpoint,
+		PresignEndpoint:   in.PresignEndpoint,
+		AccessKeyID:       in.AccessKeyID,
+		SecretAccessKey:   in.SecretAccessKey,
+		SessionToken:      in.SessionToken,
+		UsePathStyle:      in.UsePathStyle,
+		Provider:          in.Provider,
+		AccountID:         in.AccountID,
+		Timeout:           in.Timeout,
+		ReadOnly:          in.ReadOnly,
+		AllowedBuckets:    in.AllowedBuckets,
+		AllowedPrefixes:   in.AllowedPrefixes,
+		HealthCheckBucket: in.HealthCheckBucket,
+	}
+	if err := conn.ToClientConfig().Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &conn, nil
+}
+
+// isSecretReference reports whether a credential value could be taken for a
+// secret reference by the built-in or a custom resolver. Literal AWS-style
+// keys contain neither "${" nor ":".
+func isSecretReference(value string) bool {
+	return strings.Contains(value, "${") || strings.Contains(value, ":")
+}
+
+// snapshot returns a copy of the current configuration.
+func (m *Manager) snapshot() *MultiConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.clone()
+}
+
+var _ tools.ConnectionAdmin = (*Admin)(nil)
@@ -4,6 +4,7 @@ package multiserver
 import (
 	"encoding/json"
 	"os"
+	"slices"
 
 	"gopkg.in/yaml.v3"
 
@@ -21,6 +22,9 @@ type ConnectionConfig struct {
 	// Endpoint is an optional custom endpoint URL.
 	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
 
+	// PresignEndpoint is an optional public endpoint used only for presigned URLs.
+	PresignEndpoint string `json:"presign_endpoint,omitempty" yaml:"presign_endpoint,omitempty"`
+
 	// AccessKeyID is the AWS access key ID.
 	AccessKeyID string `json:"access_key_id,omitempty" yaml:"access_key_id,omitempty"`
 
@@ -36,8 +40,124 @@ type ConnectionConfig struct {
 	// UsePathStyle enables path-style addressing.
 	UsePathStyle bool `json:"use_path_style,omitempty" yaml:"use_path_style,omitempty"`
 
+	// Provider selects a preset for an S3-compatible service: aws, minio, r2,
+	// b2, wasabi, seaweedfs, or ceph.
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+
+	// AccountID is the provider account used to build the endpoint (r2).
+	AccountID string `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+
 	// DisableSSL disables SSL/TLS.
 	DisableSSL bool `json:"disable_ssl,omitempty" yaml:"disable_ssl,omitempty"`
+
+	// Timeout is the timeout for S3 operations on this connection (e.g., "2m").
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// MaxInFlight caps concurrent S3 requests on this connection (0 = unlimited).
+	// Requests over the cap wait for a free slot until their context is done.
+	MaxInFlight int `json:"max_in_flight,omitempty" yaml:"max_in_flight,omitempty"`
+
+	// RetryMode selects the SDK retry strategy: standard, adaptive, or off.
+	RetryMode string `json:"retry_mode,omitempty" yaml:"retry_mode,omitempty"`
+
+	// MaxAttempts is the maximum number of attempts per operation, including the first.
+	MaxAttempts int `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+
+	// MaxBackoff caps the delay between retry attempts (e.g., "5s").
+	MaxBackoff Duration `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+
+	// CircuitBreakerThreshold is the number of consecutive transport errors
+	// after which requests fail fast (0 = disabled).
+	CircuitBreakerThreshold int `json:"circuit_breaker_threshold,omitempty" yaml:"circuit_breaker_threshold,omitempty"`
+
+	// CircuitBreakerCooldown is how long an open circuit waits before letting a probe through.
+	CircuitBreakerCooldown Duration `json:"circuit_breaker_cooldown,omitempty" yaml:"circuit_breaker_cooldown,omitempty"`
+
+	// CABundle is the path to a PEM file of additional trusted CA certificates.
+	CABundle string `json:"ca_bundle,omitempty" yaml:"ca_bundle,omitempty"`
+
+	// ClientCert is the path to a PEM client certificate for mutual TLS.
+	ClientCert string `json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
+
+	// ClientKey is the path to the PEM private key for ClientCert.
+	ClientKey string `json:"client_key,omitempty" yaml:"client_key,omitempty"`
+
+	// InsecureSkipVerify disables TLS certificate verification (development only).
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+
+	// ProxyURL routes requests through an HTTP proxy.
+	ProxyURL string `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty"`
+
+	// MaxIdleConns caps idle connections across all hosts.
+	MaxIdleConns int `json:"max_idle_conns,omitempty" yaml:"max_idle_conns,omitempty"`
+
+	// MaxIdleConnsPerHost caps idle connections per host.
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host,omitempty" yaml:"max_idle_conns_per_host,omitempty"`
+
+	// MaxConnsPerHost caps total connections per host.
+	MaxConnsPerHost int `json:"max_conns_per_host,omitempty" yaml:"max_conns_per_host,omitempty"`
+
+	// IdleConnTimeout closes idle connections after this duration (e.g., "90s").
+	IdleConnTimeout Duration `json:"idle_conn_timeout,omitempty" yaml:"idle_conn_timeout,omitempty"`
+
+	// CredentialProcess is a command that prints credentials in the AWS credential_process format.
+	CredentialProcess string `json:"credential_process,omitempty" yaml:"credential_process,omitempty"`
+
+	// WebIdentityTokenFile is the path to an OIDC token exchanged for WebIdentityRoleARN credentials.
+	WebIdentityTokenFile string `json:"web_identity_token_file,omitempty" yaml:"web_identity_token_file,omitempty"`
+
+	// WebIdentityRoleARN is the role assumed with WebIdentityTokenFile.
+	WebIdentityRoleARN string `json:"web_identity_role_arn,omitempty" yaml:"web_identity_role_arn,omitempty"`
+
+	// AssumeRole is a chain of roles to assume, each with the credentials of the one before.
+	AssumeRole []AssumeRoleConfig `json:"assume_role,omitempty" yaml:"assume_role,omitempty"`
+
+	// STSEndpoint is an optional custom STS endpoint for assuming roles.
+	STSEndpoint string `json:"sts_endpoint,omitempty" yaml:"sts_endpoint,omitempty"`
+
+	// ReadOnly blocks write tools on this connection.
+	ReadOnly bool `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+
+	// MaxGetSize overrides the server-wide retrieval limit in bytes (0 = server-wide limit).
+	MaxGetSize int64 `json:"max_get_size,omitempty" yaml:"max_get_size,omitempty"`
+
+	// MaxPutSize overrides the server-wide upload limit in bytes (0 = server-wide limit).
+	MaxPutSize int64 `json:"max_put_size,omitempty" yaml:"max_put_size,omitempty"`
+
+	// AllowedBuckets restricts tools to these buckets (empty = all buckets).
+	AllowedBuckets []string `json:"allowed_buckets,omitempty" yaml:"allowed_buckets,omitempty"`
+
+	// AllowedPrefixes restricts tools to keys under these prefixes (empty = all keys).
+	AllowedPrefixes []string `json:"allowed_prefixes,omitempty" yaml:"allowed_prefixes,omitempty"`
+
+	// HealthCheckBucket is listed by health checks instead of listing all
+	// buckets, for credentials that cannot call ListBuckets.
+	HealthCheckBucket string `json:"health_check_bucket,omitempty" yaml:"health_check_bucket,omitempty"`
+
+	// discovered marks connections added by ProfileDiscovery, which are not
+	// saved back to the configuration file.
+	discovered bool
+}
+
+// AssumeRoleConfig describes one role in a connection's AssumeRole chain.
+type AssumeRoleConfig struct {
+	// RoleARN is the ARN of the role to assume.
+	RoleARN string `json:"role_arn" yaml:"role_arn"`
+
+	// ExternalID is the external ID required by the role's trust policy.
+	ExternalID string `json:"external_id,omitempty" yaml:"external_id,omitempty"`
+
+	// SessionName identifies the role session (default: mcp-s3).
+	SessionName string `json:"session_name,omitempty" yaml:"session_name,omitempty"`
+
+	// SessionTags are passed as STS session tags.
+	SessionTags map[string]string `json:"session_tags,omitempty" yaml:"session_tags,omitempty"`
+
+	// TransitiveTagKeys lists session tag keys that carry over to later roles in the chain.
+	TransitiveTagKeys []string `json:"transitive_tag_keys,omitempty" yaml:"transitive_tag_keys,omitempty"`
+
+	// Duration is the lifetime of the role credentials (e.g., "1h").
+	Duration Duration `json:"duration,omitempty" yaml:"duration,omitempty"`
 }
 
 // ToClientConfig converts a ConnectionConfig to a client.Config.
@@ -46,15 +166,132 @@ func (c *ConnectionConfig) ToClientConfig() *client.Config {
 		Name:            c.Name,
 		Region:          c.Region,
 		Endpoint:        c.Endpoint,
+		PresignEndpoint: c.PresignEndpoint,
 		AccessKeyID:     c.AccessKeyID,
 		SecretAccessKey: c.SecretAccessKey,
 		SessionToken:    c.SessionToken,
 		Profile:         c.Profile,
 		UsePathStyle:    c.UsePathStyle,
+		Provider:        c.Provider,
+		AccountID:       c.AccountID,
 		DisableSSL:      c.DisableSSL,
+		Timeout:         c.Timeout.Std(),
+
+		RetryMode:               c.RetryMode,
+		MaxAttempts:             c.MaxAttempts,
+		MaxBackoff:              c.MaxBackoff.Std(),
+		CircuitBreakerThreshold: c.CircuitBreakerThreshold,
+		CircuitBreakerCooldown:  c.CircuitBreakerCooldown.Std(),
+
+		CABundle:            c.CABundle,
+		ClientCert:          c.ClientCert,
+		ClientKey:           c.ClientKey,
+		InsecureSkipVerify:  c.InsecureSkipVerify,
+		ProxyURL:            c.ProxyURL,
+		MaxIdleConns:        c.MaxIdleConns,
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.MaxConnsPerHost,
+		IdleConnTimeout:     c.IdleConnTimeout.Std(),
+
+		CredentialProcess:    c.CredentialProcess,
+		WebIdentityTokenFile: c.WebIdentityTokenFile,
+		WebIdentityRoleARN:   c.WebIdentityRoleARN,
+		AssumeRole:           toClientAssumeRoles(c.AssumeRole),
+		STSEndpoint:          c.STSEndpoint,
+
+		ReadOnly:        c.ReadOnly,
+		MaxGetSize:      c.MaxGetSize,
+		MaxPutSize:      c.MaxPutSize,
+		AllowedBuckets:  c.AllowedBuckets,
+		AllowedPrefixes: c.AllowedPrefixes,
 	}
 }
 
+// toClientAssumeRoles converts a role chain to its client form.
+func toClientAssumeRoles(roles []AssumeRoleConfig) []client.AssumeRoleConfig {
+	if len(roles) == 0 {
+		return nil
+	}
+	result := make([]client.AssumeRoleConfig, 0, len(roles))
+	for _, r := range roles {
+		result = append(result, client.AssumeRoleConfig{
+			RoleARN:           r.RoleARN,
+			ExternalID:        r.ExternalID,
+			SessionName:       r.SessionName,
+			SessionTags:       r.SessionTags,
+			TransitiveTagKeys: r.TransitiveTagKeys,
+			Duration:          r.Duration.Std(),
+		})
+	}
+	return result
+}
+
+// FromClientConfig converts a client.Config to a ConnectionConfig.
+func FromClientConfig(c *client.Config) ConnectionConfig {
+	return ConnectionConfig{
+		Name:            c.Name,
+		Region:          c.Region,
+		Endpoint:        c.Endpoint,
+		PresignEndpoint: c.PresignEndpoint,
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		Profile:         c.Profile,
+		UsePathStyle:    c.UsePathStyle,
+		Provider:        c.Provider,
+		AccountID:       c.AccountID,
+		DisableSSL:      c.DisableSSL,
+		Timeout:         Duration(c.Timeout),
+
+		RetryMode:               c.RetryMode,
+		MaxAttempts:             c.MaxAttempts,
+		MaxBackoff:              Duration(c.MaxBackoff),
+		CircuitBreakerThreshold: c.CircuitBreakerThreshold,
+		CircuitBreakerCooldown:  Duration(c.CircuitBreakerCooldown),
+
+		CABundle:            c.CABundle,
+		ClientCert:          c.ClientCert,
+		ClientKey:           c.ClientKey,
+		InsecureSkipVerify:  c.InsecureSkipVerify,
+		ProxyURL:            c.ProxyURL,
+		MaxIdleConns:        c.MaxIdleConns,
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.MaxConnsPerHost,
+		IdleConnTimeout:     Duration(c.IdleConnTimeout),
+
+		CredentialProcess:    c.CredentialProcess,
+		WebIdentityTokenFile: c.WebIdentityTokenFile,
+		WebIdentityRoleARN:   c.WebIdentityRoleARN,
+		AssumeRole:           fromClientAssumeRoles(c.AssumeRole),
+		STSEndpoint:          c.STSEndpoint,
+
+		ReadOnly:        c.ReadOnly,
+		MaxGetSize:      c.MaxGetSize,
+		MaxPutSize:      c.MaxPutSize,
+		AllowedBuckets:  c.AllowedBuckets,
+		AllowedPrefixes: c.AllowedPrefixes,
+	}
+}
+
+// fromClientAssumeRoles converts a client role chain to its configuration form.
+func fromClientAssumeRoles(roles []client.AssumeRoleConfig) []AssumeRoleConfig {
+	if len(roles) == 0 {
+		return nil
+	}
+	result := make([]AssumeRoleConfig, 0, len(roles))
+	for _, r := range roles {
+		result = append(result, AssumeRoleConfig{
+			RoleARN:           r.RoleARN,
+			ExternalID:        r.ExternalID,
+			SessionName:       r.SessionName,
+			SessionTags:       r.SessionTags,
+			TransitiveTagKeys: r.TransitiveTagKeys,
+			Duration:          Duration(r.Duration),
+		})
+	}
+	return result
+}
+
 // MultiConfig holds configuration for multiple S3 connections.
 type MultiConfig struct {
 	// DefaultConnection is the name of the default connection.
@@ -62,6 +299,34 @@ type MultiConfig struct {
 
 	// Connections is a list of connection configurations.
 	Connections []ConnectionConfig `json:"connections" yaml:"connections"`
+
+	// Groups combine connections that hold replicas of the same data. A
+	// group name can be used wherever a connection name is accepted.
+	Groups []GroupConfig `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// Group routing strategies.
+const (
+	// GroupFailover reads from members in order, moving to the next member
+	// when one is unavailable.
+	GroupFailover = "failover"
+
+	// GroupLatency reads from the healthy member with the lowest observed
+	// latency, moving to the next fastest when one is unavailable.
+	GroupLatency = "latency"
+)
+
+// GroupConfig describes a group of connections that replicate each other.
+type GroupConfig struct {
+	// Name is the unique identifier for this group.
+	Name string `json:"name" yaml:"name"`
+
+	// Members are the connections in the group. The first is the primary,
+	// which receives all writes.
+	Members []string `json:"members" yaml:"members"`
+
+	// Strategy selects how reads are routed: failover (default) or latency.
+	Strategy string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
 }
 
 // FromEnvJSON loads multi-connection configuration from the S3_ADDITIONAL_CONNECTIONS
@@ -132,6 +397,28 @@ func (c *MultiConfig) GetConnection(name string) *ConnectionConfig {
 	return nil
 }
 
+// GetGroup returns the configuration for a specific group by name.
+func (c *MultiConfig) GetGroup(name string) *GroupConfig {
+	for i := range c.Groups {
+		if c.Groups[i].Name == name {
+			return &c.Groups[i]
+		}
+	}
+	return nil
+}
+
+// groupOf returns the name of the first group that has the connection as a
+// member, or "" if none does.
+// Not thread-safe; caller must hold appropriate lock.
+func (c *MultiConfig) groupOf(connection string) string {
+	for _, g := range c.Groups {
+		if slices.Contains(g.Members, connection) {
+			return g.Name
+		}
+	}
+	return ""
+}
+
 // ConnectionNames returns a list of all connection names.
 func (c *MultiConfig) ConnectionNames() []string {
 	names := make([]string, 0, len(c.Connections))
@@ -0,0 +1,388 @@
+package multiserver
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+	"github.com/txn2/mcp-s3/pkg/tools"
+)
+
+// groupFailureCooldown is how long a member that failed a read is tried
+// after the other members.
+const groupFailureCooldown = 30 * time.Second
+
+// groupLatencyWeight is the weight of the newest sample in a member's
+// moving average latency.
+const groupLatencyWeight = 0.3
+
+// memberStats records how a group member has performed.
+type memberStats struct {
+	latency  time.Duration // moving average of successful reads
+	failedAt time.Time
+}
+
+// groupClient routes calls across the members of a connection group. Reads
+// go to the members in routing order until one succeeds or fails with an
+// error other than unavailability; writes always go to the primary. The
+// member that served a call is recorded with tools.RecordServedBy.
+type groupClient struct {
+	manager *Manager
+	config  GroupConfig
+
+	mu    sync.Mutex
+	stats map[string]*memberStats
+	now   func() time.Time
+}
+
+// newGroupClient creates a client for the group that resolves its members
+// through manager.
+func newGroupClient(manager *Manager, cfg GroupConfig) *groupClient {
+	return &groupClient{
+		manager: manager,
+		config:  cfg,
+		stats:   make(map[string]*memberStats, len(cfg.Members)),
+		now:     time.Now,
+	}
+}
+
+// primary returns the name of the member that receives writes.
+func (g *groupClient) primary() string {
+	return g.config.Members[0]
+}
+
+// readOrder returns the members in the order reads should try them. Members
+// that failed within the cooldown come last. With the latency strategy, the
+// others are ordered by average latency, and members without samples come
+// first so they are measured.
+func (g *groupClient) readOrder() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	failed := func(name string) bool {
+		s := g.stats[name]
+		return s != nil && now.Sub(s.failedAt) < groupFailureCooldown
+	}
+	latency := func(name string) time.Duration {
+		if s := g.stats[name]; s != nil {
+			return s.latency
+		}
+		return 0
+	}
+
+	order := slices.Clone(g.config.Members)
+	slices.SortStableFunc(order, func(a, b string) int {
+		if fa, fb := failed(a), failed(b); fa != fb {
+			if fa {
+				return 1
+			}
+			return -1
+		}
+		if g.config.Strategy == GroupLatency {
+			return cmp.Compare(latency(a), latency(b))
+		}
+		return 0
+	})
+	return order
+}
+
+// succeeded records a successful read from member that took d.
+func (g *groupClient) succeeded(member string, d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s := g.member(member)
+	s.failedAt = time.Time{}
+	if s.latency == 0 {
+		s.latency = d
+		return
+	}
+	s.latency = time.Duration(groupLatencyWeight*float64(d) + (1-groupLatencyWeight)*float64(s.latency))
+}
+
+// failed records that member was unavailable.
+func (g *groupClient) failed(member string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.member(member).failedAt = g.now()
+}
+
+// member returns the stats for member, creating them if needed.
+// Caller must hold g.mu.
+func (g *groupClient) member(name string) *memberStats {
+	s, ok := g.stats[name]
+	if !ok {
+		s = &memberStats{}
+		g.stats[name] = s
+	}
+	return s
+}
+
+// read runs call against the members in routing order. It moves to the next
+// member when a member cannot be created or is unavailable, and returns any
+// other error as is.
+func (g *groupClient) read(ctx context.Context, call func(tools.S3Client) error) error {
+	_, err := g.readFrom(ctx, call)
+	return err
+}
+
+// readFrom is read that also returns the member that served the call.
+func (g *groupClient) readFrom(ctx context.Context, call func(tools.S3Client) error) (string, error) {
+	var errs []error
+	for _, member := range g.readOrder() {
+		c, err := g.manager.GetClient(ctx, member)
+		if err == nil {
+			start := time.Now()
+			err = call(c)
+			if err == nil {
+				g.succeeded(member, time.Since(start))
+				tools.RecordServedBy(ctx, member)
+				return member, nil
+			}
+			if !client.IsUnavailable(err) {
+				return "", err
+			}
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		g.failed(member)
+		errs = append(errs, fmt.Errorf("%s: %w", member, err))
+	}
+	return "", fmt.Errorf("all members of group %s are unavailable: %w", g.config.Name, errors.Join(errs...))
+}
+
+// write runs call against the primary.
+func (g *groupClient) write(ctx context.Context, call func(tools.S3Client) error) error {
+	c, err := g.manager.GetClient(ctx, g.primary())
+	if err != nil {
+		return err
+	}
+	if err := call(c); err != nil {
+		return err
+	}
+	tools.RecordServedBy(ctx, g.primary())
+	return nil
+}
+
+// ConnectionName returns the group name.
+func (g *groupClient) ConnectionName() string {
+	return g.config.Name
+}
+
+// Config returns the primary's configuration under the group name, so the
+// group has the primary's guardrails, which every member shares. When the
+// primary's client cannot be created, the guardrails come from its
+// configuration; if that is gone too, the group is read-only and allows no
+// bucket.
+func (g *groupClient) Config() *client.Config {
+	if c, err := g.manager.GetClient(context.Background(), g.primary()); err == nil && c.Config() != nil {
+		cfg := *c.Config()
+		cfg.Name = g.config.Name
+		return &cfg
+	}
+	conn := g.manager.connectionConfig(g.primary())
+	if conn == nil {
+		return &client.Config{Name: g.config.Name, ReadOnly: true, AllowedBuckets: []string{""}}
+	}
+	return &client.Config{
+		Name:            g.config.Name,
+		Region:          conn.Region,
+		Endpoint:        conn.Endpoint,
+		Provider:        conn.Provider,
+		ReadOnly:        conn.ReadOnly,
+		MaxGetSize:      conn.MaxGetSize,
+		MaxPutSize:      conn.MaxPutSize,
+		AllowedBuckets:  conn.AllowedBuckets,
+		AllowedPrefixes: conn.AllowedPrefixes,
+	}
+}
+
+// ListBuckets lists buckets from the first available member.
+func (g *groupClient) ListBuckets(ctx context.Context) ([]client.BucketInfo, error) {
+	var out []client.BucketInfo
+	err := g.read(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.ListBuckets(ctx)
+		return err
+	})
+	return out, err
+}
+
+// ListObjects lists objects from the first available member. Continuation
+// tokens are only valid on the member that issued them, so the returned
+// token names that member and a continued listing goes to it without
+// failover.
+func (g *groupClient) ListObjects(
+	ctx context.Context, bucket, prefix, delimiter string, maxKeys int32, continueToken string,
+) (*client.ListObjectsOutput, error) {
+	if continueToken != "" {
+		member, token, err := g.parseContinueToken(continueToken)
+		if err != nil {
+			return nil, err
+		}
+		c, err := g.manager.GetClient(ctx, member)
+		if err != nil {
+			return nil, err
+		}
+		out, err := c.ListObjects(ctx, bucket, prefix, delimiter, maxKeys, token)
+		if err != nil {
+			return nil, fmt.Errorf("continue listing on group member %s: %w", member, err)
+		}
+		tools.RecordServedBy(ctx, member)
+		return pinContinueToken(member, out), nil
+	}
+
+	var out *client.ListObjectsOutput
+	member, err := g.readFrom(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.ListObjects(ctx, bucket, prefix, delimiter, maxKeys, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pinContinueToken(member, out), nil
+}
+
+// pinContinueToken prefixes the continuation token in out with the encoded
+// name of the member that issued it.
+func pinContinueToken(member string, out *client.ListObjectsOutput) *client.ListObjectsOutput {
+	if out == nil || out.NextContinueToken == "" {
+		return out
+	}
+	pinned := *out
+	pinned.NextContinueToken = base64.RawURLEncoding.EncodeToString([]byte(member)) + "." + out.NextContinueToken
+	return &pinned
+}
+
+// parseContinueToken returns the member and the member's own token from a
+// token returned by ListObjects.
+func (g *groupClient) parseContinueToken(token string) (member, memberToken string, err error) {
+	encoded, memberToken, ok := strings.Cut(token, ".")
+	name, decodeErr := base64.RawURLEncoding.DecodeString(encoded)
+	if !ok || decodeErr != nil || memberToken == "" {
+		return "", "", fmt.Errorf("invalid continuation token for group %s", g.config.Name)
+	}
+	if !slices.Contains(g.config.Members, string(name)) {
+		return "", "", fmt.Errorf("continuation token names %s, which is not a member of group %s", name, g.config.Name)
+	}
+	return string(name), memberToken, nil
+}
+
+// GetObject retrieves an object from the first available member.
+func (g *groupClient) GetObject(ctx context.Context, bucket, key string) (*client.ObjectContent, error) {
+	var out *client.ObjectContent
+	err := g.read(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.GetObject(ctx, bucket, key)
+		return err
+	})
+	return out, err
+}
+
+// GetObjectRange retrieves part of an object from the first available member.
+func (g *groupClient) GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (*client.ObjectContent, error) {
+	var out *client.ObjectContent
+	err := g.read(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.GetObjectRange(ctx, bucket, key, offset, length)
+		return err
+	})
+	return out, err
+}
+
+// GetObjectMetadata retrieves object metadata from the first available member.
+func (g *groupClient) GetObjectMetadata(ctx context.Context, bucket, key string) (*client.ObjectMetadata, error) {
+	var out *client.ObjectMetadata
+	err := g.read(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.GetObjectMetadata(ctx, bucket, key)
+		return err
+	})
+	return out, err
+}
+
+// PutObject uploads an object to the primary.
+func (g *groupClient) PutObject(ctx context.Context, input *client.PutObjectInput) (*client.PutObjectOutput, error) {
+	var out *client.PutObjectOutput
+	err := g.write(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.PutObject(ctx, input)
+		return err
+	})
+	return out, err
+}
+
+// DeleteObject deletes an object from the primary.
+func (g *groupClient) DeleteObject(ctx context.Context, bucket, key string) error {
+	return g.write(ctx, func(c tools.S3Client) error {
+		return c.DeleteObject(ctx, bucket, key)
+	})
+}
+
+// CopyObject copies an object on the primary.
+func (g *groupClient) CopyObject(ctx context.Context, input *client.CopyObjectInput) (*client.CopyObjectOutput, error) {
+	var out *client.CopyObjectOutput
+	err := g.write(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.CopyObject(ctx, input)
+		return err
+	})
+	return out, err
+}
+
+// PresignGetURL presigns a download URL on the primary. Presigning does not
+// contact the endpoint, so there is nothing to fail over from.
+func (g *groupClient) PresignGetURL(ctx context.Context, bucket, key string, expires time.Duration) (*client.PresignedURL, error) {
+	var out *client.PresignedURL
+	err := g.write(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.PresignGetURL(ctx, bucket, key, expires)
+		return err
+	})
+	return out, err
+}
+
+// PresignPutURL presigns an upload URL on the primary.
+func (g *groupClient) PresignPutURL(ctx context.Context, bucket, key string, expires time.Duration) (*client.PresignedURL, error) {
+	var out *client.PresignedURL
+	err := g.write(ctx, func(c tools.S3Client) (err error) {
+		out, err = c.PresignPutURL(ctx, bucket, key, expires)
+		return err
+	})
+	return out, err
+}
+
+// Close does nothing; member clients belong to the Manager.
+func (g *groupClient) Close() error {
+	return nil
+}
+
+// syncGroups creates clients for new or changed groups in m.config and drops
+// clients for groups that were removed or changed. It returns the names of
+// added, removed, and updated groups. Caller must hold m.mu.
+func (m *Manager) syncGroups() (added, removed, updated []string) {
+	next := make(map[string]*groupClient, len(m.config.Groups))
+	for _, cfg := range m.config.Groups {
+		existing, ok := m.groups[cfg.Name]
+		switch {
+		case !ok:
+			added = append(added, cfg.Name)
+		case !slices.Equal(existing.config.Members, cfg.Members) || existing.config.Strategy != cfg.Strategy:
+			updated = append(updated, cfg.Name)
+		default:
+			next[cfg.Name] = existing
+			continue
+		}
+		next[cfg.Name] = newGroupClient(m, cfg)
+	}
+	for name := range m.groups {
+		if _, ok := next[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	m.groups = next
+	return added, removed, updated
+}
+
+var _ tools.S3Client = (*groupClient)(nil)
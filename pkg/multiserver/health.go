@@ -0,0 +1,170 @@
+package multiserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/txn2/mcp-s3/pkg/tools"
+)
+
+// DefaultHealthCacheTTL is how long a health check result is reused before
+// the connection is probed again.
+const DefaultHealthCacheTTL = 30 * time.Second
+
+// healthCheckTimeout bounds a single probe so a hung endpoint cannot stall
+// s3_list_connections.
+const healthCheckTimeout = 10 * time.Second
+
+// WithHealthCacheTTL sets how long health check results are cached. A
+// negative value disables caching.
+func WithHealthCacheTTL(d time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.healthTTL = d
+	}
+}
+
+// HealthCheck probes the named connections concurrently, or every configured
+// connection when none are named, and returns the results in that order.
+// Cached results younger than the health cache TTL are returned without
+// probing.
+func (m *Manager) HealthCheck(ctx context.Context, names ...string) []tools.HealthStatus {
+	if len(names) == 0 {
+		names = m.ListConnections()
+	}
+	results := make([]tools.HealthStatus, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.CheckHealth(ctx, name)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// CheckHealth probes the named connection, or returns its cached result. The
+// probe lists objects in the connection's health_check_bucket when one is
+// configured, which works for credentials scoped to a single bucket, and
+// lists buckets otherwise. Creating the client counts as part of the probe,
+// so unresolved secrets and invalid settings are reported as errors.
+func (m *Manager) CheckHealth(ctx context.Context, name string) tools.HealthStatus {
+	return m.checkHealth(ctx, name, true)
+}
+
+// CachedHealth returns the last health check result for the named connection
+// without probing, however old it is. The second result is false when the
+// connection has not been checked.
+func (m *Manager) CachedHealth(name string) (tools.HealthStatus, bool) {
+	m.healthMu.Lock()
+	cached, ok := m.health[name]
+	m.healthMu.Unlock()
+	if ok {
+		cached.Initialized = m.IsClientInitialized(name)
+	}
+	return cached, ok
+}
+
+// checkHealth probes the named connection and records the result. With
+// useCache, a fresh cached result is returned instead.
+func (m *Manager) checkHealth(ctx context.Context, name string, useCache bool) tools.HealthStatus {
+	m.healthMu.Lock()
+	cached, ok := m.health[name]
+	m.healthMu.Unlock()
+	if useCache && ok && m.healthTTL > 0 && time.Since(cached.CheckedAt) < m.healthTTL {
+		cached.Initialized = m.IsClientInitialized(name)
+		return cached
+	}
+
+	status := m.probe(ctx, name)
+	if status.LastError == "" {
+		// Keep the previous error visible after the connection recovers.
+		status.LastError, status.LastErrorAt = cached.LastError, cached.LastErrorAt
+	}
+
+	m.healthMu.Lock()
+	m.health[name] = status
+	m.healthMu.Unlock()
+	return status
+}
+
+// probe runs a single health check against the named connection.
+func (m *Manager) probe(ctx context.Context, name string) tools.HealthStatus {
+	status := tools.HealthStatus{
+		Connection:  name,
+		Initialized: m.IsClientInitialized(name),
+	}
+
+	var bucket string
+	m.mu.RLock()
+	if conn := m.config.GetConnection(name); conn != nil {
+		bucket = conn.HealthCheckBucket
+	}
+	m.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	c, err := m.GetClient(ctx, name)
+	if err == nil {
+		err = probeClient(ctx, c, bucket)
+	}
+	finishProbe(&status, start, err)
+	return status
+}
+
+// probeConfig runs a health check against a connection that is not
+// configured, using a client that is closed afterwards.
+func (m *Manager) probeConfig(ctx context.Context, conn *ConnectionConfig) tools.HealthStatus {
+	status := tools.HealthStatus{Connection: conn.Name}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	c, err := m.newClient(ctx, conn)
+	if err == nil {
+		err = probeClient(ctx, c, conn.HealthCheckBucket)
+		_ = c.Close()
+	}
+	finishProbe(&status, start, err)
+	return status
+}
+
+// probeClient lists one key in bucket, or all buckets when bucket is empty.
+func probeClient(ctx context.Context, c tools.S3Client, bucket string) error {
+	if bucket != "" {
+		_, err := c.ListObjects(ctx, bucket, "", "", 1, "")
+		return err
+	}
+	_, err := c.ListBuckets(ctx)
+	return err
+}
+
+// finishProbe records the outcome of a probe that began at start.
+func finishProbe(status *tools.HealthStatus, start time.Time, err error) {
+	status.Latency = time.Since(start)
+	status.CheckedAt = time.Now()
+	if err != nil {
+		status.Status = tools.HealthUnhealthy
+		status.LastError = err.Error()
+		status.LastErrorAt = status.CheckedAt
+		return
+	}
+	status.Status = tools.HealthHealthy
+}
+
+// forgetHealth drops cached health results for the named connections.
+func (m *Manager) forgetHealth(names ...string) {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	for _, name := range names {
+		delete(m.health, name)
+	}
+}
+
+var _ tools.HealthChecker = (*Manager)(nil)
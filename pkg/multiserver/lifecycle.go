@@ -0,0 +1,339 @@
+package multiserver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+	"github.com/txn2/mcp-s3/pkg/tools"
+)
+
+// EvictReason explains why a cached client left the Manager's cache.
+type EvictReason string
+
+// Eviction reasons passed to OnEvict hooks.
+const (
+	// EvictIdle means the client was unused for longer than the idle TTL.
+	EvictIdle EvictReason = "idle"
+
+	// EvictCapacity means the cache was full and the client was the least
+	// recently used.
+	EvictCapacity EvictReason = "capacity"
+
+	// EvictRemoved means the connection was removed.
+	EvictRemoved EvictReason = "removed"
+
+	// EvictReplaced means the connection's settings changed.
+	EvictReplaced EvictReason = "replaced"
+
+	// EvictClosed means the Manager was closed.
+	EvictClosed EvictReason = "closed"
+)
+
+// WithIdleTTL evicts cached clients that have not been used for d. Idle
+// clients are evicted when other clients are requested and by
+// RunEviction. Zero, the default, keeps clients until they are removed.
+func WithIdleTTL(d time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.idleTTL = d
+	}
+}
+
+// WithMaxClients caps the number of cached clients. Creating a client beyond
+// the cap evicts the least recently used one. Zero, the default, means no cap.
+func WithMaxClients(n int) ManagerOption {
+	return func(m *Manager) {
+		m.maxClients = n
+	}
+}
+
+// WithOnCreate sets a function called after a client is created and cached.
+// It runs with the Manager locked and must not call the Manager.
+func WithOnCreate(fn func(connection string)) ManagerOption {
+	return func(m *Manager) {
+		m.onCreate = fn
+	}
+}
+
+// WithOnEvict sets a function called when a client leaves the cache. The
+// client itself is closed once its in-flight calls finish. It runs with the
+// Manager locked and must not call the Manager.
+func WithOnEvict(fn func(connection string, reason EvictReason)) ManagerOption {
+	return func(m *Manager) {
+		m.onEvict = fn
+	}
+}
+
+// tracksClients reports whether cached clients are wrapped to record use, which
+// idle and capacity eviction need.
+func (m *Manager) tracksClients() bool {
+	return m.idleTTL > 0 || m.maxClients > 0
+}
+
+// EvictIdle evicts clients unused for longer than the idle TTL and returns
+// how many were evicted. It does nothing when no idle TTL is set.
+func (m *Manager) EvictIdle() int {
+	if m.idleTTL <= 0 {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.evictIdleLocked(time.Now())
+}
+
+// RunEviction evicts idle clients periodically until ctx is done. It returns
+// immediately when no idle TTL is set.
+func (m *Manager) RunEviction(ctx context.Context) {
+	if m.idleTTL <= 0 {
+		return
+	}
+	interval := max(m.idleTTL/2, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.EvictIdle()
+		}
+	}
+}
+
+// evictIdleLocked evicts clients idle since before now minus the idle TTL.
+// Caller must hold m.mu.
+func (m *Manager) evictIdleLocked(now time.Time) int {
+	if m.idleTTL <= 0 {
+		return 0
+	}
+	evicted := 0
+	for name, c := range m.clients {
+		if tracked, ok := c.(*trackedClient); ok && tracked.idleSince(now, m.idleTTL) {
+			m.evictLocked(name, EvictIdle)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// evictOverflowLocked evicts least recently used clients until the cache is
+// within its cap. keep is never evicted. Caller must hold m.mu.
+func (m *Manager) evictOverflowLocked(keep string) {
+	if m.maxClients <= 0 {
+		return
+	}
+	for len(m.clients) > m.maxClients {
+		oldest := ""
+		var oldestUse int64
+		for name, c := range m.clients {
+			tracked, ok := c.(*trackedClient)
+			if name == keep || !ok {
+				continue
+			}
+			if used := tracked.lastUsed.Load(); oldest == "" || used < oldestUse {
+				oldest, oldestUse = name, used
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		m.evictLocked(oldest, EvictCapacity)
+	}
+}
+
+// evictLocked removes the named client from the cache and closes it once it
+// is idle. Caller must hold m.mu.
+func (m *Manager) evictLocked(name string, reason EvictReason) error {
+	c, ok := m.clients[name]
+	if !ok {
+		return nil
+	}
+	delete(m.clients, name)
+	if m.onEvict != nil {
+		m.onEvict(name, reason)
+	}
+	if tracked, ok := c.(*trackedClient); ok {
+		tracked.retire()
+		return nil
+	}
+	return c.Close()
+}
+
+// trackedClient records when a cached client was last used and how many calls
+// are running on it, so an evicted client is only closed once it is idle.
+type trackedClient struct {
+	tools.S3Client
+
+	lastUsed atomic.Int64 // Unix nanoseconds
+
+	mu      sync.Mutex
+	refs    int
+	retired bool
+	closed  bool
+}
+
+// newTrackedClient wraps c, marking it used now.
+func newTrackedClient(c tools.S3Client) *trackedClient {
+	t := &trackedClient{S3Client: c}
+	t.touch()
+	return t
+}
+
+// Unwrap returns the underlying client.
+func (t *trackedClient) Unwrap() tools.S3Client {
+	return t.S3Client
+}
+
+// touch marks the client used now.
+func (t *trackedClient) touch() {
+	t.lastUsed.Store(time.Now().UnixNano())
+}
+
+// idleSince reports whether the client has no calls running and was last used
+// more than ttl before now.
+func (t *trackedClient) idleSince(now time.Time, ttl time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.refs == 0 && now.Sub(time.Unix(0, t.lastUsed.Load())) > ttl
+}
+
+// acquire records the start of a call.
+func (t *trackedClient) acquire() {
+	t.mu.Lock()
+	t.refs++
+	t.mu.Unlock()
+	t.touch()
+}
+
+// release records the end of a call, closing a retired client once its last
+// call finishes.
+func (t *trackedClient) release() {
+	t.touch()
+	t.mu.Lock()
+	t.refs--
+	closeNow := t.retired && t.refs == 0 && !t.closed
+	if closeNow {
+		t.closed = true
+	}
+	t.mu.Unlock()
+	if closeNow {
+		_ = t.S3Client.Close()
+	}
+}
+
+// retire marks the client evicted and closes it now if no calls are running.
+// Close errors are ignored, as when the Manager replaces a client.
+func (t *trackedClient) retire() {
+	t.mu.Lock()
+	t.retired = true
+	closeNow := t.refs == 0 && !t.closed
+	if closeNow {
+		t.closed = true
+	}
+	t.mu.Unlock()
+	if closeNow {
+		_ = t.S3Client.Close()
+	}
+}
+
+// Hold holds a reference until release is called, so the client is not closed
+// while a tool call spanning several S3 calls uses it.
+func (t *trackedClient) Hold() (release func()) {
+	t.acquire()
+	var once sync.Once
+	return func() { once.Do(t.release) }
+}
+
+// InFlight returns the number of calls running on the client.
+func (t *trackedClient) InFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.refs
+}
+
+// Close retires the client; the Manager owns its lifecycle.
+func (t *trackedClient) Close() error {
+	t.retire()
+	return nil
+}
+
+// ListBuckets lists buckets, holding a reference for the call.
+func (t *trackedClient) ListBuckets(ctx context.Context) ([]client.BucketInfo, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.ListBuckets(ctx)
+}
+
+// ListObjects lists objects, holding a reference for the call.
+func (t *trackedClient) ListObjects(
+	ctx context.Context, bucket, prefix, delimiter string, maxKeys int32, continueToken string,
+) (*client.ListObjectsOutput, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.ListObjects(ctx, bucket, prefix, delimiter, maxKeys, continueToken)
+}
+
+// GetObject retrieves an object, holding a reference for the call.
+func (t *trackedClient) GetObject(ctx context.Context, bucket, key string) (*client.ObjectContent, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.GetObject(ctx, bucket, key)
+}
+
+// GetObjectRange retrieves part of an object, holding a reference for the call.
+func (t *trackedClient) GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (*client.ObjectContent, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.GetObjectRange(ctx, bucket, key, offset, length)
+}
+
+// GetObjectMetadata retrieves object metadata, holding a reference for the call.
+func (t *trackedClient) GetObjectMetadata(ctx context.Context, bucket, key string) (*client.ObjectMetadata, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.GetObjectMetadata(ctx, bucket, key)
+}
+
+// PutObject uploads an object, holding a reference for the call.
+func (t *trackedClient) PutObject(ctx context.Context, input *client.PutObjectInput) (*client.PutObjectOutput, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.PutObject(ctx, input)
+}
+
+// DeleteObject deletes an object, holding a reference for the call.
+func (t *trackedClient) DeleteObject(ctx context.Context, bucket, key string) error {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.DeleteObject(ctx, bucket, key)
+}
+
+// CopyObject copies an object, holding a reference for the call.
+func (t *trackedClient) CopyObject(ctx context.Context, input *client.CopyObjectInput) (*client.CopyObjectOutput, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.CopyObject(ctx, input)
+}
+
+// PresignGetURL presigns a download URL, holding a reference for the call.
+func (t *trackedClient) PresignGetURL(ctx context.Context, bucket, key string, expires time.Duration) (*client.PresignedURL, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.PresignGetURL(ctx, bucket, key, expires)
+}
+
+// PresignPutURL presigns an upload URL, holding a reference for the call.
+func (t *trackedClient) PresignPutURL(ctx context.Context, bucket, key string, expires time.Duration) (*client.PresignedURL, error) {
+	t.acquire()
+	defer t.release()
+	return t.S3Client.PresignPutURL(ctx, bucket, key, expires)
+}
+
+// Ensure trackedClient implements S3Client and ClientHolder.
+var (
+	_ tools.S3Client     = (*trackedClient)(nil)
+	_ tools.ClientHolder = (*trackedClient)(nil)
+)
@@ -0,0 +1,148 @@
+package multiserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+	"github.com/txn2/mcp-s3/pkg/tools"
+)
+
+// limitedClient caps the number of concurrent S3 requests issued through a
+// connection. Calls beyond the cap wait for a free slot or until their
+// context is done, whichever comes first.
+type limitedClient struct {
+	tools.S3Client
+	slots chan struct{}
+}
+
+// newLimitedClient wraps c so that at most maxInFlight requests run at once.
+// It returns c unchanged when maxInFlight is not positive.
+func newLimitedClient(c tools.S3Client, maxInFlight int) tools.S3Client {
+	if maxInFlight <= 0 {
+		return c
+	}
+	return &limitedClient{
+		S3Client: c,
+		slots:    make(chan struct{}, maxInFlight),
+	}
+}
+
+// Unwrap returns the underlying client.
+func (l *limitedClient) Unwrap() tools.S3Client {
+	return l.S3Client
+}
+
+// InFlight returns the number of requests currently holding a slot.
+func (l *limitedClient) InFlight() int {
+	return len(l.slots)
+}
+
+// acquire waits for a free slot, returning an error if ctx is done first.
+func (l *limitedClient) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("connection %s: waiting for an in-flight slot: %w", l.ConnectionName(), ctx.Err())
+	}
+}
+
+// release frees a slot taken by acquire.
+func (l *limitedClient) release() {
+	<-l.slots
+}
+
+// ListBuckets lists buckets once a slot is available.
+func (l *limitedClient) ListBuckets(ctx context.Context) ([]client.BucketInfo, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+	return l.S3Client.ListBuckets(ctx)
+}
+
+// ListObjects lists objects once a slot is available.
+func (l *limitedClient) ListObjects(
+	ctx context.Context, bucket, prefix, delimiter string, maxKeys int32, continueToken string,
+) (*client.ListObjectsOutput, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+	return l.S3Client.ListObjects(ctx, bucket, prefix, delimiter, maxKeys, continueToken)
+}
+
+// GetObject retrieves an object once a slot is available.
+func (l *limitedClient) GetObject(ctx context.Context, bucket, key string) (*client.ObjectContent, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+	return l.S3Client.GetObject(ctx, bucket, key)
+}
+
+// GetObjectRange retrieves part of an object once a slot is available.
+func (l *limitedClient) GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (*client.ObjectContent, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+	return l.S3Client.GetObjectRange(ctx, bucket, key, offset, length)
+}
+
+// GetObjectMetadata retrieves object metadata once a slot is available.
+func (l *limitedClient) GetObjectMetadata(ctx context.Context, bucket, key string) (*client.ObjectMetadata, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+	return l.S3Client.GetObjectMetadata(ctx, bucket, key)
+}
+
+// PutObject uploads an object once a slot is available.
+func (l *limitedClient) PutObject(ctx context.Context, input *client.PutObjectInput) (*client.PutObjectOutput, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+	return l.S3Client.PutObject(ctx, input)
+}
+
+// DeleteObject deletes an object once a slot is available.
+func (l *limitedClient) DeleteObject(ctx context.Context, bucket, key string) error {
+	if err := l.acquire(ctx); err != nil {
+		return err
+	}
+	defer l.release()
+	return l.S3Client.DeleteObject(ctx, bucket, key)
+}
+
+// CopyObject copies an object once a slot is available.
+func (l *limitedClient) CopyObject(ctx context.Context, input *client.CopyObjectInput) (*client.CopyObjectOutput, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+	return l.S3Client.CopyObject(ctx, input)
+}
+
+// PresignGetURL is not limited; presigning is local and issues no request.
+func (l *limitedClient) PresignGetURL(ctx context.Context, bucket, key string, expires time.Duration) (*client.PresignedURL, error) {
+	return l.S3Client.PresignGetURL(ctx, bucket, key, expires)
+}
+
+// PresignPutURL is not limited; presigning is local and issues no request.
+func (l *limitedClient) PresignPutURL(ctx context.Context, bucket, key string, expires time.Duration) (*client.PresignedURL, error) {
+	return l.S3Client.PresignPutURL(ctx, bucket, key, expires)
+}
+
+// Ensure limitedClient implements S3Client.
+var _ tools.S3Client = (*limitedClient)(nil)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/txn2/mcp-s3/pkg/client"
 	"github.com/txn2/mcp-s3/pkg/tools"
@@ -13,42 +14,82 @@ import (
 type Manager struct {
 	config  *MultiConfig
 	clients map[string]tools.S3Client
+	groups  map[string]*groupClient
 	mu      sync.RWMutex
 
 	// Factory function for creating clients (allows for mocking in tests)
 	clientFactory func(ctx context.Context, cfg *client.Config) (tools.S3Client, error)
+
+	// resolvers resolve secret references in connection credentials.
+	resolvers []SecretResolver
+
+	// Cached health check results, keyed by connection name.
+	healthTTL time.Duration
+	healthMu  sync.Mutex
+	health    map[string]tools.HealthStatus
+
+	// Client lifecycle settings; see lifecycle.go.
+	idleTTL    time.Duration
+	maxClients int
+	onCreate   func(connection string)
+	onEvict    func(connection string, reason EvictReason)
 }
 
-// NewManager creates a new connection manager with the given configuration.
-func NewManager(config *MultiConfig) *Manager {
-	return &Manager{
-		config:  config,
-		clients: make(map[string]tools.S3Client),
-		clientFactory: func(ctx context.Context, cfg *client.Config) (tools.S3Client, error) {
-			return client.New(ctx, cfg)
-		},
+// ManagerOption configures a Manager.
+type ManagerOption func(*Manager)
+
+// WithSecretResolvers adds resolvers for secret references in connection
+// credentials. They are tried after the built-in "${env:NAME}" and
+// "file:/path" resolvers.
+func WithSecretResolvers(resolvers ...SecretResolver) ManagerOption {
+	return func(m *Manager) {
+		for _, r := range resolvers {
+			if r != nil {
+				m.resolvers = append(m.resolvers, r)
+			}
+		}
 	}
 }
 
+// NewManager creates a new connection manager with the given configuration.
+func NewManager(config *MultiConfig, opts ...ManagerOption) *Manager {
+	return NewManagerWithFactory(config, func(ctx context.Context, cfg *client.Config) (tools.S3Client, error) {
+		return client.New(ctx, cfg)
+	}, opts...)
+}
+
 // NewManagerWithFactory creates a new connection manager with a custom client factory.
 // Useful for testing.
-func NewManagerWithFactory(config *MultiConfig, factory func(ctx context.Context, cfg *client.Config) (tools.S3Client, error)) *Manager {
-	return &Manager{
+func NewManagerWithFactory(config *MultiConfig, factory func(ctx context.Context, cfg *client.Config) (tools.S3Client, error), opts ...ManagerOption) *Manager {
+	m := &Manager{
 		config:        config,
 		clients:       make(map[string]tools.S3Client),
 		clientFactory: factory,
+		resolvers:     defaultSecretResolvers(),
+		healthTTL:     DefaultHealthCacheTTL,
+		health:        make(map[string]tools.HealthStatus),
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	m.syncGroups()
+	return m
 }
 
-// GetClient returns a client for the given connection name.
+// GetClient returns a client for the given connection or group name.
 // If the client doesn't exist, it is created lazily.
 func (m *Manager) GetClient(ctx context.Context, name string) (tools.S3Client, error) {
 	// Check if we already have the client
 	m.mu.RLock()
 	if cached, ok := m.clients[name]; ok {
 		m.mu.RUnlock()
+		touch(cached)
 		return cached, nil
 	}
+	if group, ok := m.groups[name]; ok {
+		m.mu.RUnlock()
+		return group, nil
+	}
 	m.mu.RUnlock()
 
 	// Need to create the client
@@ -57,6 +98,7 @@ func (m *Manager) GetClient(ctx context.Context, name string) (tools.S3Client, e
 
 	// Double-check after acquiring write lock
 	if cached, ok := m.clients[name]; ok {
+		touch(cached)
 		return cached, nil
 	}
 
@@ -67,14 +109,49 @@ func (m *Manager) GetClient(ctx context.Context, name string) (tools.S3Client, e
 	}
 
 	// Create the client
-	clientCfg := connCfg.ToClientConfig()
-	newClient, err := m.clientFactory(ctx, clientCfg)
+	newClient, err := m.newClient(ctx, connCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client for %s: %w", name, err)
 	}
 
-	m.clients[name] = newClient
-	return newClient, nil
+	m.cacheLocked(name, newClient)
+	return m.clients[name], nil
+}
+
+// cacheLocked stores a new client for name, wrapping it for tracking when
+// eviction is enabled, and evicts idle and excess clients. Caller must hold m.mu.
+func (m *Manager) cacheLocked(name string, c tools.S3Client) {
+	if m.tracksClients() {
+		c = newTrackedClient(c)
+	}
+	m.clients[name] = c
+	if m.onCreate != nil {
+		m.onCreate(name)
+	}
+	m.evictIdleLocked(time.Now())
+	m.evictOverflowLocked(name)
+}
+
+// touch marks a tracked client used now.
+func touch(c tools.S3Client) {
+	if tracked, ok := c.(*trackedClient); ok {
+		tracked.touch()
+	}
+}
+
+// newClient creates a client for the connection using the factory and applies
+// the connection's in-flight limit. Secret references in the credentials are
+// resolved here, so secrets are only read when a connection is first used.
+func (m *Manager) newClient(ctx context.Context, connCfg *ConnectionConfig) (tools.S3Client, error) {
+	cfg := connCfg.ToClientConfig()
+	if err := resolveSecrets(ctx, m.resolvers, cfg); err != nil {
+		return nil, err
+	}
+	c, err := m.clientFactory(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newLimitedClient(c, connCfg.MaxInFlight), nil
 }
 
 // GetDefaultClient returns the client for the default connection.
@@ -87,11 +164,16 @@ func (m *Manager) GetDefaultClient(ctx context.Context) (tools.S3Client, error)
 	return m.GetClient(ctx, defaultName)
 }
 
-// ListConnections returns a list of all available connection names.
+// ListConnections returns a list of all available connection names,
+// followed by the names of connection groups.
 func (m *Manager) ListConnections() []string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.config.ConnectionNames()
+	names := m.config.ConnectionNames()
+	for _, g := range m.config.Groups {
+		names = append(names, g.Name)
+	}
+	return names
 }
 
 // DefaultConnectionName returns the name of the default connection.
@@ -126,19 +208,18 @@ func (m *Manager) ClientProvider() func(name string) (tools.S3Client, error) {
 	}
 }
 
-// Close closes all managed clients.
+// Close closes all managed clients. Clients with calls still running are
+// closed when those calls finish.
 func (m *Manager) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	var lastErr error
-	for name, client := range m.clients {
-		if err := client.Close(); err != nil {
+	for name := range m.clients {
+		if err := m.evictLocked(name, EvictClosed); err != nil {
 			lastErr = fmt.Errorf("failed to close client %s: %w", name, err)
 		}
 	}
-
-	m.clients = make(map[string]tools.S3Client)
 	return lastErr
 }
 
@@ -161,25 +242,39 @@ func (m *Manager) AddConnection(cfg ConnectionConfig, createNow bool) error {
 	if cfg.Name == m.defaultConnectionName() {
 		return fmt.Errorf("cannot replace the default connection %q via AddConnection", cfg.Name)
 	}
+	if m.config.GetGroup(cfg.Name) != nil {
+		return fmt.Errorf("%q is a connection group", cfg.Name)
+	}
+
+	// A group member cannot change the guardrails the group shares.
+	if group := m.config.GetGroup(m.config.groupOf(cfg.Name)); group != nil {
+		candidate := m.config.clone()
+		candidate.addOrReplace(cfg)
+		if err := candidate.checkGroupGuardrails(group); err != nil {
+			return err
+		}
+	}
 
-	// Save previous config in case we need to roll back
-	previousCfg := m.config.GetConnection(cfg.Name)
+	// Save previous config in case we need to roll back. addOrReplace
+	// overwrites the entry in place, so keep a copy.
+	var previousCfg *ConnectionConfig
+	if prev := m.config.GetConnection(cfg.Name); prev != nil {
+		saved := *prev
+		previousCfg = &saved
+	}
 
 	// Close existing cached client if replacing.
 	// Close errors are intentionally ignored: the client is being replaced
 	// and S3 clients have no server-side session to clean up.
-	if existing, ok := m.clients[cfg.Name]; ok {
-		_ = existing.Close()
-		delete(m.clients, cfg.Name)
-	}
+	_ = m.evictLocked(cfg.Name, EvictReplaced)
 
 	// Add or replace in config
 	m.config.addOrReplace(cfg)
+	m.forgetHealth(cfg.Name)
 
 	// Optionally create the client now
 	if createNow {
-		clientCfg := cfg.ToClientConfig()
-		newClient, err := m.clientFactory(context.Background(), clientCfg)
+		newClient, err := m.newClient(context.Background(), &cfg)
 		if err != nil {
 			// Roll back: restore previous config or remove the new entry
 			if previousCfg != nil {
@@ -189,7 +284,7 @@ func (m *Manager) AddConnection(cfg ConnectionConfig, createNow bool) error {
 			}
 			return fmt.Errorf("failed to create client for %s: %w", cfg.Name, err)
 		}
-		m.clients[cfg.Name] = newClient
+		m.cacheLocked(cfg.Name, newClient)
 	}
 
 	return nil
@@ -212,25 +307,68 @@ func (m *Manager) RemoveConnection(name string) error {
 	if !m.config.hasConnection(name) {
 		return fmt.Errorf("connection %q not found", name)
 	}
+	if group := m.config.groupOf(name); group != "" {
+		return fmt.Errorf("cannot remove connection %q: it is a member of group %q", name, group)
+	}
 
 	// Close and remove the cached client if it exists.
 	// Close errors are intentionally ignored: the connection is being
 	// removed and S3 clients have no server-side session to clean up.
-	if existing, ok := m.clients[name]; ok {
-		_ = existing.Close()
-		delete(m.clients, name)
-	}
+	_ = m.evictLocked(name, EvictRemoved)
 
 	// Remove from config
 	m.config.remove(name)
+	m.forgetHealth(name)
 	return nil
 }
 
-// HasConnection returns true if a connection with the given name exists.
+// HasConnection returns true if a connection or group with the given name exists.
 func (m *Manager) HasConnection(name string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.config.hasConnection(name)
+	return m.config.hasConnection(name) || m.config.GetGroup(name) != nil
+}
+
+// InFlight returns the number of requests currently running on the named
+// connection. It is only tracked for connections with MaxInFlight set and
+// returns 0 otherwise.
+func (m *Manager) InFlight(name string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c := m.clients[name]
+	if tracked, ok := c.(*trackedClient); ok {
+		c = tracked.Unwrap()
+	}
+	if limited, ok := c.(*limitedClient); ok {
+		return limited.InFlight()
+	}
+	return 0
+}
+
+// SizeLimits returns the size limits configured on the named connection, or
+// on the primary of the named group. A zero value means the connection uses
+// the server-wide limit.
+func (m *Manager) SizeLimits(name string) (maxGetSize, maxPutSize int64) {
+	if conn := m.connectionConfig(name); conn != nil {
+		return conn.MaxGetSize, conn.MaxPutSize
+	}
+	return 0, 0
+}
+
+// connectionConfig returns a copy of the configuration of the named
+// connection, or of the primary of the named group, or nil if there is none.
+func (m *Manager) connectionConfig(name string) *ConnectionConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if g := m.config.GetGroup(name); g != nil {
+		name = g.Members[0]
+	}
+	conn := m.config.GetConnection(name)
+	if conn == nil {
+		return nil
+	}
+	cfg := *conn
+	return &cfg
 }
 
 // IsClientInitialized returns true if a client for the given connection has been created.
@@ -2,12 +2,23 @@ package multiserver
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"gopkg.in/yaml.v3"
+
 	"github.com/txn2/mcp-s3/pkg/client"
 	"github.com/txn2/mcp-s3/pkg/tools"
 )
@@ -34,6 +45,9 @@ func (m *mockClient) ListObjects(ctx context.Context, bucket, prefix, delimiter
 func (m *mockClient) GetObject(ctx context.Context, bucket, key string) (*client.ObjectContent, error) {
 	return nil, nil
 }
+func (m *mockClient) GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (*client.ObjectContent, error) {
+	return nil, nil
+}
 func (m *mockClient) GetObjectMetadata(ctx context.Context, bucket, key string) (*client.ObjectMetadata, error) {
 	return nil, nil
 }
@@ -80,6 +94,46 @@ func TestConnectionConfig_ToClientConfig(t *testing.T) {
 	}
 }
 
+func TestFromClientConfig(t *testing.T) {
+	want := &client.Config{
+		Name:        "primary",
+		Region:      "us-west-2",
+		Endpoint:    "http://localhost:9000",
+		Profile:     "dev",
+		Timeout:     2 * time.Minute,
+		MaxBackoff:  5 * time.Second,
+		AssumeRole:  []client.AssumeRoleConfig{{RoleARN: "arn:aws:iam::123456789012:role/reader", Duration: time.Hour}},
+		ReadOnly:    true,
+		MaxGetSize:  1024,
+		MaxAttempts: 3,
+	}
+	conn := FromClientConfig(want)
+	if got := conn.ToClientConfig(); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestMultiConfig_Provider(t *testing.T) {
+	var cfg MultiConfig
+	data := `{"connections":[{"name":"r2","provider":"r2","account_id":"abc123"}]}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	clientCfg := cfg.Connections[0].ToClientConfig()
+	if clientCfg.Provider != "r2" || clientCfg.AccountID != "abc123" {
+		t.Errorf("provider settings = %q/%q", clientCfg.Provider, clientCfg.AccountID)
+	}
+
+	cfg.Connections[0].Provider = "unknown"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
 func TestMultiConfig_GetConnection(t *testing.T) {
 	cfg := &MultiConfig{
 		DefaultConnection: "conn1",
@@ -865,3 +919,1288 @@ func TestMultiConfig_hasConnection(t *testing.T) {
 		t.Error("expected hasConnection to return false")
 	}
 }
+
+// blockingClient is a mock whose ListBuckets blocks until release is closed.
+type blockingClient struct {
+	mockClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingClient) ListBuckets(ctx context.Context) ([]client.BucketInfo, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return nil, nil
+}
+
+func TestManager_SizeLimits(t *testing.T) {
+	manager := NewManager(&MultiConfig{Connections: []ConnectionConfig{
+		{Name: "scratch", MaxGetSize: 1 << 20, MaxPutSize: 1 << 30},
+		{Name: "prod"},
+	}})
+
+	if get, put := manager.SizeLimits("scratch"); get != 1<<20 || put != 1<<30 {
+		t.Errorf("SizeLimits(scratch) = %d, %d", get, put)
+	}
+	if get, put := manager.SizeLimits("prod"); get != 0 || put != 0 {
+		t.Errorf("SizeLimits(prod) = %d, %d, want zeros", get, put)
+	}
+	if get, put := manager.SizeLimits("missing"); get != 0 || put != 0 {
+		t.Errorf("SizeLimits(missing) = %d, %d, want zeros", get, put)
+	}
+}
+
+func TestManager_MaxInFlight(t *testing.T) {
+	blocking := &blockingClient{
+		mockClient: mockClient{name: "limited"},
+		started:    make(chan struct{}, 4),
+		release:    make(chan struct{}),
+	}
+	cfg := &MultiConfig{
+		Connections: []ConnectionConfig{{Name: "limited", MaxInFlight: 1}},
+	}
+	manager := NewManagerWithFactory(cfg, func(_ context.Context, _ *client.Config) (tools.S3Client, error) {
+		return blocking, nil
+	})
+
+	c, err := manager.GetClient(context.Background(), "limited")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if unwrapper, ok := c.(interface{ Unwrap() tools.S3Client }); !ok || unwrapper.Unwrap() != blocking {
+		t.Error("expected limited client to unwrap to the factory client")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.ListBuckets(context.Background())
+		done <- err
+	}()
+	<-blocking.started
+
+	if got := manager.InFlight("limited"); got != 1 {
+		t.Errorf("InFlight() = %d, want 1", got)
+	}
+
+	// A second call waits for the slot and gives up when its context ends.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListBuckets(ctx); err == nil {
+		t.Error("expected queued call to fail when its context is done")
+	}
+
+	close(blocking.release)
+	if err := <-done; err != nil {
+		t.Errorf("ListBuckets() error = %v", err)
+	}
+	if got := manager.InFlight("limited"); got != 0 {
+		t.Errorf("InFlight() after release = %d, want 0", got)
+	}
+
+	// The slot is free again.
+	go func() { <-blocking.started }()
+	if _, err := c.ListBuckets(context.Background()); err != nil {
+		t.Errorf("ListBuckets() error = %v", err)
+	}
+}
+
+func TestManager_MaxInFlight_Unset(t *testing.T) {
+	mock := &mockClient{name: "plain"}
+	cfg := &MultiConfig{Connections: []ConnectionConfig{{Name: "plain"}}}
+	manager := NewManagerWithFactory(cfg, func(_ context.Context, _ *client.Config) (tools.S3Client, error) {
+		return mock, nil
+	})
+
+	c, err := manager.GetClient(context.Background(), "plain")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if c != mock {
+		t.Error("expected unwrapped client when MaxInFlight is unset")
+	}
+}
+
+func TestConnectionConfig_RetrySettings(t *testing.T) {
+	yamlContent := `
+connections:
+  - name: minio
+    retry_mode: adaptive
+    max_attempts: 5
+    max_backoff: 2s
+    circuit_breaker_threshold: 3
+    circuit_breaker_cooldown: 1m
+`
+	tmpFile, err := os.CreateTemp("", "retry-*.yaml")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(yamlContent); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	cfg, err := FromYAMLFile(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("FromYAMLFile() error = %v", err)
+	}
+
+	clientCfg := cfg.Connections[0].ToClientConfig()
+	if clientCfg.RetryMode != "adaptive" || clientCfg.MaxAttempts != 5 {
+		t.Errorf("retry settings = %q/%d", clientCfg.RetryMode, clientCfg.MaxAttempts)
+	}
+	if clientCfg.MaxBackoff != 2*time.Second {
+		t.Errorf("MaxBackoff = %v, want 2s", clientCfg.MaxBackoff)
+	}
+	if clientCfg.CircuitBreakerThreshold != 3 || clientCfg.CircuitBreakerCooldown != time.Minute {
+		t.Errorf("breaker settings = %d/%v", clientCfg.CircuitBreakerThreshold, clientCfg.CircuitBreakerCooldown)
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	var conn ConnectionConfig
+	if err := json.Unmarshal([]byte(`{"name":"a","max_backoff":"1m30s","circuit_breaker_cooldown":1000000000}`), &conn); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if conn.MaxBackoff.Std() != 90*time.Second {
+		t.Errorf("MaxBackoff = %v, want 1m30s", conn.MaxBackoff)
+	}
+	if conn.CircuitBreakerCooldown.Std() != time.Second {
+		t.Errorf("CircuitBreakerCooldown = %v, want 1s", conn.CircuitBreakerCooldown)
+	}
+
+	data, err := json.Marshal(conn)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"max_backoff":"1m30s"`) {
+		t.Errorf("Marshal() = %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"max_backoff":"soon"}`), &conn); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
+
+func TestConnectionConfig_AssumeRole(t *testing.T) {
+	var cfg MultiConfig
+	err := json.Unmarshal([]byte(`{"connections":[{
+		"name": "prod",
+		"web_identity_token_file": "/var/run/secrets/token",
+		"web_identity_role_arn": "arn:aws:iam::111111111111:role/pod",
+		"assume_role": [
+			{"role_arn": "arn:aws:iam::222222222222:role/reader", "external_id": "ext",
+			 "session_tags": {"team": "data"}, "duration": "30m"}
+		],
+		"credential_process": "",
+		"sts_endpoint": "https://sts.internal"
+	}]}`), &cfg)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	clientCfg := cfg.Connections[0].ToClientConfig()
+	if clientCfg.WebIdentityTokenFile != "/var/run/secrets/token" || clientCfg.STSEndpoint != "https://sts.internal" {
+		t.Errorf("web identity settings = %+v", clientCfg)
+	}
+	if len(clientCfg.AssumeRole) != 1 {
+		t.Fatalf("AssumeRole = %+v", clientCfg.AssumeRole)
+	}
+	role := clientCfg.AssumeRole[0]
+	if role.ExternalID != "ext" || role.SessionTags["team"] != "data" || role.Duration != 30*time.Minute {
+		t.Errorf("role = %+v", role)
+	}
+	if err := clientCfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestConnectionConfig_Guardrails(t *testing.T) {
+	var cfg MultiConfig
+	err := yaml.Unmarshal([]byte(`
+connections:
+  - name: production
+    endpoint: http://minio.internal:9000
+    presign_endpoint: https://s3.example.com
+    timeout: 2m
+    read_only: true
+    max_get_size: 1048576
+    allowed_buckets: [reports]
+    allowed_prefixes: [public/, shared/]
+  - name: scratch
+    max_put_size: 1073741824
+`), &cfg)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	prod := cfg.Connections[0].ToClientConfig()
+	if !prod.ReadOnly || prod.MaxGetSize != 1<<20 || prod.Timeout != 2*time.Minute ||
+		prod.PresignEndpoint != "https://s3.example.com" {
+		t.Errorf("production = %+v", prod)
+	}
+	if len(prod.AllowedBuckets) != 1 || len(prod.AllowedPrefixes) != 2 {
+		t.Errorf("allow lists = %v, %v", prod.AllowedBuckets, prod.AllowedPrefixes)
+	}
+
+	scratch := cfg.Connections[1].ToClientConfig()
+	if scratch.ReadOnly || scratch.MaxPutSize != 1<<30 {
+		t.Errorf("scratch = %+v", scratch)
+	}
+}
+
+func TestMultiConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     MultiConfig
+		wantErr string
+	}{
+		{"valid", MultiConfig{DefaultConnection: "a", Connections: []ConnectionConfig{{Name: "a"}, {Name: "b"}}}, ""},
+		{"empty", MultiConfig{}, "no connections"},
+		{"missing name", MultiConfig{Connections: []ConnectionConfig{{Region: "us-east-1"}}}, "name is required"},
+		{"duplicate", MultiConfig{Connections: []ConnectionConfig{{Name: "a"}, {Name: "a"}}}, "duplicate"},
+		{"unknown default", MultiConfig{DefaultConnection: "x", Connections: []ConnectionConfig{{Name: "a"}}}, "not configured"},
+		{"invalid client config", MultiConfig{Connections: []ConnectionConfig{{Name: "a", RetryMode: "sometimes"}}}, "connection a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_Reload(t *testing.T) {
+	factory := func(ctx context.Context, cfg *client.Config) (tools.S3Client, error) {
+		return &mockClient{name: cfg.Name, config: cfg}, nil
+	}
+	manager := NewManagerWithFactory(&MultiConfig{
+		DefaultConnection: "primary",
+		Connections: []ConnectionConfig{
+			{Name: "primary", AccessKeyID: "old"},
+			{Name: "stale"},
+			{Name: "stable"},
+		},
+	}, factory)
+	ctx := context.Background()
+	for _, name := range []string{"primary", "stale", "stable"} {
+		if _, err := manager.GetClient(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stable, _ := manager.GetClient(ctx, "stable")
+
+	result, err := manager.Reload(&MultiConfig{Connections: []ConnectionConfig{
+		{Name: "stable"},
+		{Name: "primary", AccessKeyID: "rotated"},
+		{Name: "fresh"},
+	}})
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if fmt.Sprint(result.Added, result.Removed, result.Updated) != "[fresh] [stale] [primary]" {
+		t.Errorf("result = %+v", result)
+	}
+	if !result.ConnectionsChanged() {
+		t.Error("expected ConnectionsChanged")
+	}
+
+	if manager.HasConnection("stale") || !manager.HasConnection("fresh") {
+		t.Errorf("connections = %v", manager.ListConnections())
+	}
+	if manager.IsClientInitialized("primary") {
+		t.Error("expected updated connection's client to be evicted")
+	}
+	if c, _ := manager.GetClient(ctx, "primary"); c.Config().AccessKeyID != "rotated" {
+		t.Errorf("primary access key = %q, want rotated", c.Config().AccessKeyID)
+	}
+	if c, _ := manager.GetClient(ctx, "stable"); c != stable {
+		t.Error("expected unchanged connection to keep its client")
+	}
+	if manager.DefaultConnectionName() != "primary" {
+		t.Errorf("default = %q, want primary even though it moved in the list", manager.DefaultConnectionName())
+	}
+}
+
+func TestManager_Reload_Rejected(t *testing.T) {
+	original := []ConnectionConfig{{Name: "primary"}, {Name: "other"}}
+	tests := []struct {
+		name string
+		cfg  *MultiConfig
+	}{
+		{"nil", nil},
+		{"invalid", &MultiConfig{Connections: []ConnectionConfig{{Name: "primary"}, {Name: "primary"}}}},
+		{"default removed", &MultiConfig{Connections: []ConnectionConfig{{Name: "other"}}}},
+		{"default renamed", &MultiConfig{DefaultConnection: "other", Connections: original}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager(&MultiConfig{
+				DefaultConnection: "primary",
+				Connections:       append([]ConnectionConfig(nil), original...),
+			})
+			if _, err := manager.Reload(tt.cfg); err == nil {
+				t.Fatal("expected Reload() to fail")
+			}
+			if got := manager.ListConnections(); fmt.Sprint(got) != "[primary other]" {
+				t.Errorf("connections changed after rejected reload: %v", got)
+			}
+		})
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "connections.json")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"default_connection": "a", "connections": [{"name": "a"}]}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	manager := NewManager(cfg)
+	reloads := make(chan ReloadResult, 4)
+	watcher := NewWatcher(manager, path,
+		WithPollInterval(10*time.Millisecond),
+		WithOnReload(func(r ReloadResult) { reloads <- r }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	waitReload := func() ReloadResult {
+		t.Helper()
+		select {
+		case r := <-reloads:
+			return r
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for reload")
+			return ReloadResult{}
+		}
+	}
+
+	write(`{"default_connection": "a", "connections": [{"name": "a"}, {"name": "b"}]}`)
+	if r := waitReload(); len(r.Added) != 1 || r.Added[0] != "b" {
+		t.Errorf("reload = %+v, want b added", r)
+	}
+
+	// An invalid file is rejected and the live configuration is kept.
+	write(`{"connections": [{"name": "b"}]}`)
+	if _, err := watcher.Reload(); err == nil {
+		t.Error("expected invalid file to be rejected")
+	}
+	if !manager.HasConnection("a") || !manager.HasConnection("b") {
+		t.Errorf("connections = %v after rejected reload", manager.ListConnections())
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestManager_SecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secretFile, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, KeyfileKeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	keyfile := filepath.Join(dir, "secrets.kf")
+	if err := WriteKeyfile(keyfile, key, map[string]string{"token": "keyfile-token"}); err != nil {
+		t.Fatal(err)
+	}
+	keyfileResolver, err := NewKeyfileSecretResolver(keyfile, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MCP_S3_TEST_ACCESS_KEY", "env-key")
+
+	var created *client.Config
+	factory := func(ctx context.Context, cfg *client.Config) (tools.S3Client, error) {
+		created = cfg
+		return &mockClient{name: cfg.Name, config: cfg}, nil
+	}
+	config := &MultiConfig{Connections: []ConnectionConfig{
+		{
+			Name:            "prod",
+			AccessKeyID:     "${env:MCP_S3_TEST_ACCESS_KEY}",
+			SecretAccessKey: "file:" + secretFile,
+			SessionToken:    "keyfile:token",
+		},
+		{Name: "missing", AccessKeyID: "${env:MCP_S3_TEST_UNSET}", SecretAccessKey: "literal"},
+	}}
+	manager := NewManagerWithFactory(config, factory, WithSecretResolvers(keyfileResolver))
+
+	if _, err := manager.GetClient(context.Background(), "prod"); err != nil {
+		t.Fatalf("GetClient: %v", err)
+	}
+	if created.AccessKeyID != "env-key" || created.SecretAccessKey != "file-secret" || created.SessionToken != "keyfile-token" {
+		t.Errorf("secrets not resolved: %+v", created)
+	}
+	if conn := config.GetConnection("prod"); conn.SecretAccessKey != "file:"+secretFile {
+		t.Errorf("configuration should keep the reference, got %q", conn.SecretAccessKey)
+	}
+
+	_, err = manager.GetClient(context.Background(), "missing")
+	if err == nil || !strings.Contains(err.Error(), "MCP_S3_TEST_UNSET") || !strings.Contains(err.Error(), "access_key_id") {
+		t.Errorf("expected unresolved reference error, got %v", err)
+	}
+	if manager.IsClientInitialized("missing") {
+		t.Error("client should not be cached after a resolution error")
+	}
+}
+
+func TestSecretResolvers(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	key := make([]byte, KeyfileKeySize)
+	keyfile := filepath.Join(dir, "secrets.kf")
+	if err := WriteKeyfile(keyfile, key, map[string]string{"a": "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("literal values pass through", func(t *testing.T) {
+		value, err := resolveSecret(ctx, defaultSecretResolvers(), "AKIAEXAMPLE")
+		if err != nil || value != "AKIAEXAMPLE" {
+			t.Errorf("got %q, %v", value, err)
+		}
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		empty := filepath.Join(dir, "empty")
+		if err := os.WriteFile(empty, []byte("\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := (FileSecretResolver{}).Resolve(ctx, "file:"+empty); err == nil {
+			t.Error("expected error for empty secret file")
+		}
+	})
+
+	t.Run("keyfile wrong key", func(t *testing.T) {
+		wrong := make([]byte, KeyfileKeySize)
+		wrong[0] = 1
+		r, err := NewKeyfileSecretResolver(keyfile, wrong)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, handled, err := r.Resolve(ctx, "keyfile:a"); !handled || err == nil {
+			t.Errorf("expected decryption error, got handled=%v err=%v", handled, err)
+		}
+	})
+
+	t.Run("keyfile missing name", func(t *testing.T) {
+		r, err := NewKeyfileSecretResolver(keyfile, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := r.Resolve(ctx, "keyfile:missing"); err == nil {
+			t.Error("expected error for missing secret")
+		}
+	})
+
+	t.Run("keyfile key size", func(t *testing.T) {
+		if _, err := NewKeyfileSecretResolver(keyfile, []byte("short")); err == nil {
+			t.Error("expected key size error")
+		}
+	})
+}
+
+// probeMockClient counts health probes and fails them with err.
+type probeMockClient struct {
+	mockClient
+	err     error
+	buckets []string
+	calls   *int
+	mu      *sync.Mutex
+}
+
+func (p *probeMockClient) record(bucket string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	*p.calls++
+	if bucket != "" {
+		p.buckets = append(p.buckets, bucket)
+	}
+	return p.err
+}
+
+func (p *probeMockClient) ListBuckets(ctx context.Context) ([]client.BucketInfo, error) {
+	return nil, p.record("")
+}
+
+func (p *probeMockClient) ListObjects(ctx context.Context, bucket, prefix, delimiter string, maxKeys int32, continueToken string) (*client.ListObjectsOutput, error) {
+	return &client.ListObjectsOutput{}, p.record(bucket)
+}
+
+func TestManager_HealthCheck(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	var probeErr error
+	scoped := &probeMockClient{calls: &calls, mu: &mu}
+	factory := func(ctx context.Context, cfg *client.Config) (tools.S3Client, error) {
+		switch cfg.Name {
+		case "bad":
+			return nil, errors.New("invalid endpoint")
+		case "scoped":
+			return scoped, nil
+		}
+		return &probeMockClient{mockClient: mockClient{name: cfg.Name}, err: probeErr, calls: &calls, mu: &mu}, nil
+	}
+	manager := NewManagerWithFactory(&MultiConfig{Connections: []ConnectionConfig{
+		{Name: "good"},
+		{Name: "bad"},
+		{Name: "scoped", HealthCheckBucket: "reports"},
+	}}, factory)
+	ctx := context.Background()
+
+	results := manager.HealthCheck(ctx)
+	if len(results) != 3 {
+		t.Fatalf("HealthCheck() returned %d results", len(results))
+	}
+	if r := results[0]; r.Connection != "good" || r.Status != tools.HealthHealthy || r.Initialized || r.CheckedAt.IsZero() {
+		t.Errorf("good = %+v", r)
+	}
+	if r := results[1]; r.Status != tools.HealthUnhealthy || !strings.Contains(r.LastError, "invalid endpoint") {
+		t.Errorf("bad = %+v", r)
+	}
+	if len(scoped.buckets) != 1 || scoped.buckets[0] != "reports" {
+		t.Errorf("scoped probe should list health_check_bucket, got %v", scoped.buckets)
+	}
+
+	// Cached results are reused and report the client as initialized.
+	before := calls
+	if r := manager.CheckHealth(ctx, "good"); !r.Initialized || r.Status != tools.HealthHealthy {
+		t.Errorf("cached good = %+v", r)
+	}
+	if calls != before {
+		t.Errorf("cached result should not probe again")
+	}
+
+	// Named connections are probed alone; CachedHealth never probes.
+	if results := manager.HealthCheck(ctx, "scoped"); len(results) != 1 || results[0].Connection != "scoped" {
+		t.Errorf("HealthCheck(scoped) = %+v", results)
+	}
+	if r, ok := manager.CachedHealth("bad"); !ok || r.Status != tools.HealthUnhealthy || r.Initialized {
+		t.Errorf("CachedHealth(bad) = %+v, %v", r, ok)
+	}
+	if _, ok := manager.CachedHealth("missing"); ok {
+		t.Error("CachedHealth(missing) should report no result")
+	}
+	if calls != before {
+		t.Errorf("cached results should not probe again, got %d probes", calls-before)
+	}
+}
+
+func TestManager_HealthCheck_KeepsLastError(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	probe := &probeMockClient{err: errors.New("timeout"), calls: &calls, mu: &mu}
+	manager := NewManagerWithFactory(&MultiConfig{Connections: []ConnectionConfig{{Name: "flaky"}}},
+		func(ctx context.Context, cfg *client.Config) (tools.S3Client, error) { return probe, nil },
+		WithHealthCacheTTL(-1))
+	ctx := context.Background()
+
+	if r := manager.CheckHealth(ctx, "flaky"); r.Status != tools.HealthUnhealthy {
+		t.Fatalf("first probe = %+v", r)
+	}
+	probe.err = nil
+	r := manager.CheckHealth(ctx, "flaky")
+	if r.Status != tools.HealthHealthy || r.LastError != "timeout" || r.LastErrorAt.IsZero() {
+		t.Errorf("recovered probe = %+v", r)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 probes with caching disabled, got %d", calls)
+	}
+
+	// Removing and re-adding a connection clears its history.
+	manager.forgetHealth("flaky")
+	if r := manager.CheckHealth(ctx, "flaky"); r.LastError != "" {
+		t.Errorf("forgotten connection kept error: %+v", r)
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	factory := func(ctx context.Context, cfg *client.Config) (tools.S3Client, error) {
+		probe := &probeMockClient{mockClient: mockClient{name: cfg.Name, config: cfg}, calls: &calls, mu: &mu}
+		if cfg.Endpoint == "http://unreachable:9000" {
+			probe.err = errors.New("connection refused")
+		}
+		return probe, nil
+	}
+	path := filepath.Join(t.TempDir(), "connections.yaml")
+	config := &MultiConfig{DefaultConnection: "primary", Connections: []ConnectionConfig{{Name: "primary", Region: "us-east-1"}}}
+	manager := NewManagerWithFactory(config, factory)
+	admin := NewAdmin(manager, WithPersistFile(path))
+	ctx := context.Background()
+
+	t.Run("probe failure is not applied", func(t *testing.T) {
+		_, err := admin.AddConnection(ctx, "broken", map[string]any{"endpoint": "http://unreachable:9000", "access_key_id": "key", "secret_access_key": "secret"}, false, false)
+		if err == nil || !strings.Contains(err.Error(), "connection refused") {
+			t.Fatalf("expected probe error, got %v", err)
+		}
+		if manager.HasConnection("broken") {
+			t.Error("connection should not be added after a failed probe")
+		}
+	})
+
+	t.Run("unknown fields are rejected", func(t *testing.T) {
+		if _, err := admin.AddConnection(ctx, "typo", map[string]any{"endpiont": "http://x"}, false, false); err == nil {
+			t.Error("expected error for unknown field")
+		}
+	})
+
+	t.Run("local and secret reference fields are rejected", func(t *testing.T) {
+		before := calls
+		for _, config := range []map[string]any{
+			{"credential_process": "touch /tmp/pwned"},
+			{"profile": "prod"},
+			{"web_identity_token_file": "/var/run/token", "web_identity_role_arn": "arn:aws:iam::1:role/r"},
+			{"ca_bundle": "/etc/ssl/private/key.pem"},
+			{"client_cert": "/etc/cert.pem", "client_key": "/etc/key.pem"},
+			{"sts_endpoint": "https://evil.example"},
+			{"max_get_size": 1 << 40},
+			{"endpoint": "https://evil.example", "access_key_id": "${env:AWS_ACCESS_KEY_ID}", "secret_access_key": "secret"},
+			{"endpoint": "https://evil.example", "access_key_id": "key", "secret_access_key": "file:/etc/shadow"},
+			{"endpoint": "https://evil.example", "access_key_id": "key", "secret_access_key": "secret", "session_token": "keyfile:prod"},
+			{"endpoint": "https://evil.example"},
+			{"provider": "r2", "account_id": "abc"},
+		} {
+			if _, err := admin.TestConnection(ctx, "candidate", config); err == nil {
+				t.Errorf("expected %v to be rejected", config)
+			}
+		}
+		if calls != before {
+			t.Errorf("rejected configs were probed %d times", calls-before)
+		}
+	})
+
+	t.Run("add and persist", func(t *testing.T) {
+		status, err := admin.AddConnection(ctx, "minio",
+			map[string]any{"endpoint": "http://minio:9000", "use_path_style": true, "secret_access_key": "minio-secret", "access_key_id": "minio"},
+			false, true)
+		if err != nil {
+			t.Fatalf("AddConnection: %v", err)
+		}
+		if status.Status != tools.HealthHealthy {
+			t.Errorf("status = %+v", status)
+		}
+		saved, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile: %v", err)
+		}
+		conn := saved.GetConnection("minio")
+		if conn == nil || !conn.UsePathStyle || conn.SecretAccessKey != "minio-secret" {
+			t.Errorf("saved connection = %+v", conn)
+		}
+		if saved.DefaultConnection != "primary" || saved.GetConnection("primary") == nil {
+			t.Errorf("saved config lost the default connection: %+v", saved)
+		}
+	})
+
+	t.Run("existing connection needs replace", func(t *testing.T) {
+		if _, err := admin.AddConnection(ctx, "minio", map[string]any{"endpoint": "http://minio2:9000", "access_key_id": "key", "secret_access_key": "secret"}, false, false); err == nil {
+			t.Error("expected error without replace")
+		}
+		if _, err := admin.AddConnection(ctx, "minio", map[string]any{"endpoint": "http://minio2:9000", "access_key_id": "key", "secret_access_key": "secret"}, true, false); err != nil {
+			t.Errorf("replace: %v", err)
+		}
+		if _, err := admin.AddConnection(ctx, "primary", map[string]any{}, true, false); err == nil {
+			t.Error("expected error replacing the default connection")
+		}
+	})
+
+	t.Run("test connection", func(t *testing.T) {
+		status, err := admin.TestConnection(ctx, "", map[string]any{"endpoint": "http://unreachable:9000", "access_key_id": "key", "secret_access_key": "secret"})
+		if err != nil || status.Status != tools.HealthUnhealthy {
+			t.Errorf("candidate test = %+v, %v", status, err)
+		}
+		if manager.HasConnection("test") {
+			t.Error("testing a candidate should not add it")
+		}
+		status, err = admin.TestConnection(ctx, "primary", nil)
+		if err != nil || status.Status != tools.HealthHealthy {
+			t.Errorf("existing test = %+v, %v", status, err)
+		}
+		if _, err := admin.TestConnection(ctx, "missing", nil); err == nil {
+			t.Error("expected error for unknown connection")
+		}
+	})
+
+	t.Run("remove and persist", func(t *testing.T) {
+		if err := admin.RemoveConnection(ctx, "minio", true); err != nil {
+			t.Fatalf("RemoveConnection: %v", err)
+		}
+		saved, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile: %v", err)
+		}
+		if saved.GetConnection("minio") != nil {
+			t.Error("removed connection still saved")
+		}
+		if err := admin.RemoveConnection(ctx, "primary", false); err == nil {
+			t.Error("expected error removing the default connection")
+		}
+	})
+
+	t.Run("persist without file", func(t *testing.T) {
+		noFile := NewAdmin(manager)
+		if _, err := noFile.AddConnection(ctx, "other", map[string]any{}, false, true); err == nil {
+			t.Error("expected error persisting without a file")
+		}
+		if manager.HasConnection("other") {
+			t.Error("connection should not be added when persisting is impossible")
+		}
+	})
+}
+
+// closeCountingClient is a mock that counts Close calls.
+type closeCountingClient struct {
+	blockingClient
+	closes atomic.Int32
+}
+
+func (c *closeCountingClient) Close() error {
+	c.closes.Add(1)
+	return nil
+}
+
+func TestManager_IdleEviction(t *testing.T) {
+	var mu sync.Mutex
+	var created []string
+	var evicted []EvictReason
+	var clients []*closeCountingClient
+
+	cfg := &MultiConfig{Connections: []ConnectionConfig{{Name: "team-a"}}}
+	manager := NewManagerWithFactory(cfg, func(_ context.Context, cfg *client.Config) (tools.S3Client, error) {
+		c := &closeCountingClient{blockingClient: blockingClient{mockClient: mockClient{name: cfg.Name}}}
+		clients = append(clients, c)
+		return c, nil
+	},
+		WithIdleTTL(10*time.Millisecond),
+		WithOnCreate(func(name string) {
+			mu.Lock()
+			defer mu.Unlock()
+			created = append(created, name)
+		}),
+		WithOnEvict(func(_ string, reason EvictReason) {
+			mu.Lock()
+			defer mu.Unlock()
+			evicted = append(evicted, reason)
+		}),
+	)
+
+	ctx := context.Background()
+	if _, err := manager.GetClient(ctx, "team-a"); err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if n := manager.EvictIdle(); n != 0 {
+		t.Errorf("EvictIdle() on a fresh client = %d, want 0", n)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if n := manager.EvictIdle(); n != 1 {
+		t.Fatalf("EvictIdle() = %d, want 1", n)
+	}
+	if manager.IsClientInitialized("team-a") {
+		t.Error("expected idle client to leave the cache")
+	}
+	if got := clients[0].closes.Load(); got != 1 {
+		t.Errorf("idle client closed %d times, want 1", got)
+	}
+
+	// The next call creates a new client.
+	if _, err := manager.GetClient(ctx, "team-a"); err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if err := manager.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(created) != 2 {
+		t.Errorf("OnCreate called %d times, want 2", len(created))
+	}
+	if want := []EvictReason{EvictIdle, EvictClosed}; !reflect.DeepEqual(evicted, want) {
+		t.Errorf("OnEvict reasons = %v, want %v", evicted, want)
+	}
+}
+
+func TestManager_MaxClients(t *testing.T) {
+	var evicted []string
+	cfg := &MultiConfig{Connections: []ConnectionConfig{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	manager := NewManagerWithFactory(cfg, func(_ context.Context, cfg *client.Config) (tools.S3Client, error) {
+		return &mockClient{name: cfg.Name}, nil
+	},
+		WithMaxClients(2),
+		WithOnEvict(func(name string, reason EvictReason) {
+			if reason != EvictCapacity {
+				t.Errorf("OnEvict(%s) reason = %s, want %s", name, reason, EvictCapacity)
+			}
+			evicted = append(evicted, name)
+		}),
+	)
+
+	ctx := context.Background()
+	for _, name := range []string{"a", "b"} {
+		if _, err := manager.GetClient(ctx, name); err != nil {
+			t.Fatalf("GetClient(%s) error = %v", name, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// Using a again makes b the least recently used.
+	if _, err := manager.GetClient(ctx, "a"); err != nil {
+		t.Fatalf("GetClient(a) error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := manager.GetClient(ctx, "c"); err != nil {
+		t.Fatalf("GetClient(c) error = %v", err)
+	}
+
+	if !reflect.DeepEqual(evicted, []string{"b"}) {
+		t.Errorf("evicted = %v, want [b]", evicted)
+	}
+	for name, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if got := manager.IsClientInitialized(name); got != want {
+			t.Errorf("IsClientInitialized(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestManager_EvictionWaitsForInFlight(t *testing.T) {
+	c := &closeCountingClient{blockingClient: blockingClient{
+		mockClient: mockClient{name: "team-a"},
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}}
+	cfg := &MultiConfig{Connections: []ConnectionConfig{{Name: "primary"}, {Name: "team-a"}}}
+	manager := NewManagerWithFactory(cfg, func(_ context.Context, _ *client.Config) (tools.S3Client, error) {
+		return c, nil
+	}, WithIdleTTL(time.Hour))
+
+	tracked, err := manager.GetClient(context.Background(), "team-a")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := tracked.ListBuckets(context.Background())
+		done <- err
+	}()
+	<-c.started
+
+	if err := manager.RemoveConnection("team-a"); err != nil {
+		t.Fatalf("RemoveConnection() error = %v", err)
+	}
+	if got := c.closes.Load(); got != 0 {
+		t.Errorf("client closed %d times while a call was running, want 0", got)
+	}
+
+	close(c.release)
+	if err := <-done; err != nil {
+		t.Errorf("ListBuckets() error = %v", err)
+	}
+	if got := c.closes.Load(); got != 1 {
+		t.Errorf("client closed %d times after the call finished, want 1", got)
+	}
+}
+
+func TestManager_EvictionWaitsForHold(t *testing.T) {
+	c := &closeCountingClient{blockingClient: blockingClient{mockClient: mockClient{name: "team-a"}}}
+	cfg := &MultiConfig{Connections: []ConnectionConfig{{Name: "primary"}, {Name: "team-a"}}}
+	manager := NewManagerWithFactory(cfg, func(_ context.Context, _ *client.Config) (tools.S3Client, error) {
+		return c, nil
+	}, WithIdleTTL(time.Hour))
+
+	held, err := manager.GetClient(context.Background(), "team-a")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	holder, ok := held.(tools.ClientHolder)
+	if !ok {
+		t.Fatal("expected a tracked client to implement ClientHolder")
+	}
+	release := holder.Hold()
+
+	// Between the S3 calls of a tool call nothing is in flight, but the hold
+	// keeps the evicted client open.
+	if err := manager.RemoveConnection("team-a"); err != nil {
+		t.Fatalf("RemoveConnection() error = %v", err)
+	}
+	if got := c.closes.Load(); got != 0 {
+		t.Errorf("client closed %d times while held, want 0", got)
+	}
+
+	release()
+	release()
+	if got := c.closes.Load(); got != 1 {
+		t.Errorf("client closed %d times after release, want 1", got)
+	}
+}
+
+// replicaClient is a mock that counts calls and fails reads and writes with err.
+type replicaClient struct {
+	mockClient
+	err   error
+	delay time.Duration
+	reads atomic.Int32
+	lists atomic.Int32
+	puts  atomic.Int32
+}
+
+func (r *replicaClient) GetObject(ctx context.Context, bucket, key string) (*client.ObjectContent, error) {
+	r.reads.Add(1)
+	time.Sleep(r.delay)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &client.ObjectContent{Body: []byte(r.name)}, nil
+}
+
+func (r *replicaClient) ListObjects(ctx context.Context, bucket, prefix, delimiter string, maxKeys int32, continueToken string) (*client.ListObjectsOutput, error) {
+	r.lists.Add(1)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &client.ListObjectsOutput{IsTruncated: true, NextContinueToken: r.name + "-page" + continueToken}, nil
+}
+
+func (r *replicaClient) PutObject(ctx context.Context, input *client.PutObjectInput) (*client.PutObjectOutput, error) {
+	r.puts.Add(1)
+	return &client.PutObjectOutput{}, r.err
+}
+
+func TestManager_Groups(t *testing.T) {
+	unavailable := &smithyhttp.RequestSendError{Err: errors.New("connection refused")}
+	ctx := context.Background()
+
+	newGroupManager := func(strategy string, members map[string]*replicaClient) *Manager {
+		cfg := &MultiConfig{
+			Connections: []ConnectionConfig{{Name: "east"}, {Name: "west"}},
+			Groups:      []GroupConfig{{Name: "replicated", Members: []string{"east", "west"}, Strategy: strategy}},
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		return NewManagerWithFactory(cfg, func(_ context.Context, cfg *client.Config) (tools.S3Client, error) {
+			return members[cfg.Name], nil
+		})
+	}
+
+	t.Run("failover", func(t *testing.T) {
+		east := &replicaClient{mockClient: mockClient{name: "east"}, err: unavailable}
+		west := &replicaClient{mockClient: mockClient{name: "west"}}
+		manager := newGroupManager(GroupFailover, map[string]*replicaClient{"east": east, "west": west})
+
+		if !slices.Contains(manager.ListConnections(), "replicated") || !manager.HasConnection("replicated") {
+			t.Error("expected the group to be listed as a connection")
+		}
+		group, err := manager.GetClient(ctx, "replicated")
+		if err != nil {
+			t.Fatalf("GetClient() error = %v", err)
+		}
+		if group.ConnectionName() != "replicated" {
+			t.Errorf("ConnectionName() = %q", group.ConnectionName())
+		}
+
+		content, err := group.GetObject(ctx, "bucket", "key")
+		if err != nil {
+			t.Fatalf("GetObject() error = %v", err)
+		}
+		if string(content.Body) != "west" {
+			t.Errorf("read served by %q, want west", content.Body)
+		}
+
+		// The failed primary is tried last until its cooldown ends.
+		if _, err := group.GetObject(ctx, "bucket", "key"); err != nil {
+			t.Fatalf("GetObject() error = %v", err)
+		}
+		if got := east.reads.Load(); got != 1 {
+			t.Errorf("east reads = %d, want 1", got)
+		}
+
+		// Writes go to the primary even when it is failing.
+		if _, err := group.PutObject(ctx, &client.PutObjectInput{Bucket: "bucket", Key: "key"}); err == nil {
+			t.Error("expected the primary's write error")
+		}
+		if east.puts.Load() != 1 || west.puts.Load() != 0 {
+			t.Errorf("puts east=%d west=%d, want 1 and 0", east.puts.Load(), west.puts.Load())
+		}
+	})
+
+	t.Run("no failover on client errors", func(t *testing.T) {
+		east := &replicaClient{mockClient: mockClient{name: "east"}, err: &smithy.GenericAPIError{Code: "NoSuchKey"}}
+		west := &replicaClient{mockClient: mockClient{name: "west"}}
+		manager := newGroupManager(GroupFailover, map[string]*replicaClient{"east": east, "west": west})
+
+		group, _ := manager.GetClient(ctx, "replicated")
+		if _, err := group.GetObject(ctx, "bucket", "missing"); err == nil {
+			t.Error("expected NoSuchKey from the primary")
+		}
+		if got := west.reads.Load(); got != 0 {
+			t.Errorf("west reads = %d, want 0", got)
+		}
+	})
+
+	t.Run("all members unavailable", func(t *testing.T) {
+		east := &replicaClient{mockClient: mockClient{name: "east"}, err: unavailable}
+		west := &replicaClient{mockClient: mockClient{name: "west"}, err: unavailable}
+		manager := newGroupManager(GroupFailover, map[string]*replicaClient{"east": east, "west": west})
+
+		group, _ := manager.GetClient(ctx, "replicated")
+		_, err := group.GetObject(ctx, "bucket", "key")
+		if err == nil || !strings.Contains(err.Error(), "all members of group replicated") {
+			t.Errorf("GetObject() error = %v", err)
+		}
+	})
+
+	t.Run("continued listing stays on the issuing member", func(t *testing.T) {
+		east := &replicaClient{mockClient: mockClient{name: "east"}}
+		west := &replicaClient{mockClient: mockClient{name: "west"}}
+		manager := newGroupManager(GroupFailover, map[string]*replicaClient{"east": east, "west": west})
+
+		group, _ := manager.GetClient(ctx, "replicated")
+		page, err := group.ListObjects(ctx, "bucket", "", "", 10, "")
+		if err != nil {
+			t.Fatalf("ListObjects() error = %v", err)
+		}
+		page, err = group.ListObjects(ctx, "bucket", "", "", 10, page.NextContinueToken)
+		if err != nil {
+			t.Fatalf("ListObjects() error = %v", err)
+		}
+		if east.lists.Load() != 2 || strings.Contains(page.NextContinueToken, "west") {
+			t.Errorf("east lists = %d, next token = %q", east.lists.Load(), page.NextContinueToken)
+		}
+
+		// The token is not valid on another member, so there is no failover.
+		east.err = unavailable
+		if _, err := group.ListObjects(ctx, "bucket", "", "", 10, page.NextContinueToken); err == nil {
+			t.Error("expected the issuing member's error")
+		}
+		if got := west.lists.Load(); got != 0 {
+			t.Errorf("west lists = %d, want 0", got)
+		}
+		if _, err := group.ListObjects(ctx, "bucket", "", "", 10, "east-page"); err == nil {
+			t.Error("expected an error for a token not issued by the group")
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		east := &replicaClient{mockClient: mockClient{name: "east"}, delay: 20 * time.Millisecond}
+		west := &replicaClient{mockClient: mockClient{name: "west"}}
+		manager := newGroupManager(GroupLatency, map[string]*replicaClient{"east": east, "west": west})
+
+		group, _ := manager.GetClient(ctx, "replicated")
+		// The first two reads measure each member.
+		for range 4 {
+			if _, err := group.GetObject(ctx, "bucket", "key"); err != nil {
+				t.Fatalf("GetObject() error = %v", err)
+			}
+		}
+		if east.reads.Load() != 1 || west.reads.Load() != 3 {
+			t.Errorf("reads east=%d west=%d, want 1 and 3", east.reads.Load(), west.reads.Load())
+		}
+	})
+}
+
+func TestMultiConfig_ValidateGroups(t *testing.T) {
+	base := []ConnectionConfig{{Name: "east"}, {Name: "west"}}
+	tests := []struct {
+		name  string
+		group GroupConfig
+		want  string
+	}{
+		{"missing name", GroupConfig{Members: []string{"east"}}, "name is required"},
+		{"clashes with connection", GroupConfig{Name: "east", Members: []string{"west"}}, "duplicate"},
+		{"no members", GroupConfig{Name: "g"}, "at least one member"},
+		{"unknown member", GroupConfig{Name: "g", Members: []string{"north"}}, "not a configured connection"},
+		{"unknown strategy", GroupConfig{Name: "g", Members: []string{"east"}, Strategy: "random"}, "unknown strategy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &MultiConfig{Connections: base, Groups: []GroupConfig{tt.group}}
+			if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	restricted := []ConnectionConfig{{Name: "east", AllowedBuckets: []string{"data"}}, {Name: "west"}}
+	cfg := &MultiConfig{Connections: restricted, Groups: []GroupConfig{{Name: "g", Members: []string{"east", "west"}}}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "member west must have the same") {
+		t.Errorf("Validate() error = %v, want a guardrail mismatch", err)
+	}
+
+	manager := NewManager(&MultiConfig{
+		Connections: append(base, ConnectionConfig{Name: "north"}),
+		Groups:      []GroupConfig{{Name: "g", Members: []string{"east", "west"}}},
+	})
+	if err := manager.RemoveConnection("west"); err == nil {
+		t.Error("expected error removing a group member")
+	}
+	if err := manager.AddConnection(ConnectionConfig{Name: "g"}, false); err == nil {
+		t.Error("expected error adding a connection named like a group")
+	}
+	if err := manager.AddConnection(ConnectionConfig{Name: "west", ReadOnly: true}, false); err == nil {
+		t.Error("expected error changing the guardrails of a group member")
+	}
+	if conn := manager.connectionConfig("west"); conn == nil || conn.ReadOnly {
+		t.Errorf("west after rejected replace = %+v", conn)
+	}
+}
+
+func TestGroupClient_ConfigWithoutPrimary(t *testing.T) {
+	cfg := &MultiConfig{
+		Connections: []ConnectionConfig{
+			{Name: "east", ReadOnly: true, AllowedBuckets: []string{"data"}},
+			{Name: "west", ReadOnly: true, AllowedBuckets: []string{"data"}},
+		},
+		Groups: []GroupConfig{{Name: "replicated", Members: []string{"east", "west"}}},
+	}
+	manager := NewManagerWithFactory(cfg, func(_ context.Context, cfg *client.Config) (tools.S3Client, error) {
+		if cfg.Name == "east" {
+			return nil, errors.New("secret reference did not resolve")
+		}
+		return &mockClient{name: cfg.Name}, nil
+	})
+	group, err := manager.GetClient(context.Background(), "replicated")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+
+	// The primary's client cannot be created, but its guardrails still apply.
+	got := group.Config()
+	if !got.ReadOnly || !slices.Equal(got.AllowedBuckets, []string{"data"}) {
+		t.Errorf("Config() = %+v, want the primary's guardrails", got)
+	}
+}
+
+func TestProfileDiscovery(t *testing.T) {
+	dir := t.TempDir()
+	awsConfig := filepath.Join(dir, "config")
+	data := `# engineering profiles
+[default]
+region = us-east-1
+
+[profile dev-minio]
+region = us-east-1
+services = local-minio
+s3 =
+  addressing_style = path
+
+[profile dev-r2]
+endpoint_url = https://account.r2.cloudflarestorage.com
+region = auto
+
+[profile prod]
+region = eu-west-1
+
+[services local-minio]
+s3 =
+  endpoint_url = http://localhost:9000
+
+[sso-session corp]
+sso_region = us-east-1
+`
+	if err := os.WriteFile(awsConfig, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("discover all", func(t *testing.T) {
+		conns, err := ProfileDiscovery{ConfigFile: awsConfig}.Discover()
+		if err != nil {
+			t.Fatalf("Discover() error = %v", err)
+		}
+		var names []string
+		for _, c := range conns {
+			names = append(names, c.Name)
+		}
+		if want := []string{"default", "dev-minio", "dev-r2", "prod"}; !reflect.DeepEqual(names, want) {
+			t.Fatalf("names = %v, want %v", names, want)
+		}
+		minio := conns[1]
+		if minio.Profile != "dev-minio" || minio.Endpoint != "http://localhost:9000" || !minio.UsePathStyle {
+			t.Errorf("dev-minio = %+v", minio)
+		}
+		if conns[2].Endpoint != "https://account.r2.cloudflarestorage.com" || conns[3].Region != "eu-west-1" {
+			t.Errorf("unexpected connections %+v", conns)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		conns, err := ProfileDiscovery{ConfigFile: awsConfig, Patterns: []string{"dev-*"}}.Discover()
+		if err != nil || len(conns) != 2 {
+			t.Errorf("Discover() = %v, %v; want the two dev profiles", conns, err)
+		}
+		if _, err := (ProfileDiscovery{ConfigFile: awsConfig, Patterns: []string{"["}}).Discover(); err == nil {
+			t.Error("expected error for an invalid pattern")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		conns, err := ProfileDiscovery{ConfigFile: filepath.Join(dir, "missing")}.Discover()
+		if err != nil || len(conns) != 0 {
+			t.Errorf("Discover() = %v, %v", conns, err)
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		explicit := &MultiConfig{
+			DefaultConnection: "primary",
+			Connections:       []ConnectionConfig{{Name: "primary"}, {Name: "prod", Region: "us-west-2"}},
+		}
+		merged, err := ProfileDiscovery{ConfigFile: awsConfig}.Merge(explicit)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if len(explicit.Connections) != 2 {
+			t.Error("Merge modified its input")
+		}
+		if got := merged.GetConnection("prod"); got.Region != "us-west-2" || got.Profile != "" {
+			t.Errorf("explicit connection should win, got %+v", got)
+		}
+		if merged.GetConnection("dev-r2") == nil || merged.DefaultConnection != "primary" {
+			t.Errorf("merged = %+v", merged)
+		}
+
+		t.Setenv("AWS_PROFILE", "prod")
+		onlyProfiles, err := ProfileDiscovery{ConfigFile: awsConfig}.Merge(nil)
+		if err != nil || onlyProfiles.DefaultConnection != "prod" || len(onlyProfiles.Connections) != 4 {
+			t.Errorf("Merge(nil) = %+v, %v", onlyProfiles, err)
+		}
+
+		// Discovered connections are not written back to the config file.
+		file := filepath.Join(dir, "connections.yaml")
+		admin := NewAdmin(NewManager(merged), WithPersistFile(file))
+		if err := admin.RemoveConnection(context.Background(), "dev-minio", true); err != nil {
+			t.Fatalf("RemoveConnection() error = %v", err)
+		}
+		saved, err := LoadFile(file)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if got := saved.ConnectionNames(); !reflect.DeepEqual(got, []string{"primary", "prod"}) {
+			t.Errorf("saved connections = %v, want [primary prod]", got)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package multiserver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProfileDiscovery turns profiles in the AWS shared config file into
+// connections. Each discovered connection is named after its profile and uses
+// the profile for credentials, with the profile's region, S3 endpoint, and
+// addressing style.
+type ProfileDiscovery struct {
+	// ConfigFile is the shared config file. When empty, AWS_CONFIG_FILE or
+	// ~/.aws/config is used.
+	ConfigFile string
+
+	// Patterns are glob patterns, as in path.Match, that profile names must
+	// match. When empty, every profile is discovered.
+	Patterns []string
+}
+
+// Discover reads the shared config file and returns a connection for each
+// matching profile, sorted by name. A missing file yields no connections.
+func (d ProfileDiscovery) Discover() ([]ConnectionConfig, error) {
+	for _, pattern := range d.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid profile pattern %q: %w", pattern, err)
+		}
+	}
+
+	file, err := d.configFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(file) //#nosec G304 -- Path is the user's AWS config file
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading AWS config: %w", err)
+	}
+
+	sections := parseSharedConfig(data)
+	var conns []ConnectionConfig
+	for section, values := range sections {
+		name, ok := profileName(section)
+		if !ok || !d.matches(name) {
+			continue
+		}
+		conn := ConnectionConfig{
+			Name:       name,
+			Profile:    name,
+			Region:     values["region"],
+			Endpoint:   values["endpoint_url"],
+			discovered: true,
+		}
+		if endpoint := values["s3.endpoint_url"]; endpoint != "" {
+			conn.Endpoint = endpoint
+		}
+		if service, ok := sections["services "+values["services"]]; ok && service["s3.endpoint_url"] != "" {
+			conn.Endpoint = service["s3.endpoint_url"]
+		}
+		conn.UsePathStyle = values["s3.addressing_style"] == "path"
+		conns = append(conns, conn)
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].Name < conns[j].Name })
+	return conns, nil
+}
+
+// Merge returns a copy of cfg with the discovered connections added.
+// Connections configured in cfg take precedence over profiles of the same
+// name. When cfg is nil, the result holds only discovered connections and
+// the AWS_PROFILE profile, or else the default profile, is the default
+// connection.
+func (d ProfileDiscovery) Merge(cfg *MultiConfig) (*MultiConfig, error) {
+	discovered, err := d.Discover()
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *MultiConfig
+	if cfg != nil {
+		merged = cfg.clone()
+	} else {
+		merged = &MultiConfig{}
+	}
+	for _, conn := range discovered {
+		if !merged.hasConnection(conn.Name) && merged.GetGroup(conn.Name) == nil {
+			merged.Connections = append(merged.Connections, conn)
+		}
+	}
+
+	if cfg == nil {
+		for _, name := range []string{os.Getenv("AWS_PROFILE"), "default"} {
+			if name != "" && merged.hasConnection(name) {
+				merged.DefaultConnection = name
+				break
+			}
+		}
+	}
+	return merged, nil
+}
+
+// configFile returns the path of the shared config file.
+func (d ProfileDiscovery) configFile() (string, error) {
+	if d.ConfigFile != "" {
+		return d.ConfigFile, nil
+	}
+	if file := os.Getenv("AWS_CONFIG_FILE"); file != "" {
+		return file, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating AWS config: %w", err)
+	}
+	return filepath.Join(home, ".aws", "config"), nil
+}
+
+// matches reports whether the profile name matches the patterns.
+func (d ProfileDiscovery) matches(name string) bool {
+	if len(d.Patterns) == 0 {
+		return true
+	}
+	for _, pattern := range d.Patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// profileName returns the profile a config file section describes.
+func profileName(section string) (string, bool) {
+	if section == "default" {
+		return section, true
+	}
+	name, ok := strings.CutPrefix(section, "profile ")
+	name = strings.TrimSpace(name)
+	return name, ok && name != ""
+}
+
+// parseSharedConfig parses an AWS shared config file into its sections.
+// Keys are lower case. Indented lines under a key with no value, such as
+// the settings under "s3 =", are stored as "parent.key".
+func parseSharedConfig(data []byte) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	var current map[string]string
+	var parent string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.Join(strings.Fields(line[1:len(line)-1]), " ")
+			current = make(map[string]string)
+			sections[name] = current
+			parent = ""
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		indented := raw[0] == ' ' || raw[0] == '\t'
+		switch {
+		case indented && parent != "":
+			current[parent+"."+key] = value
+		case value == "":
+			parent = key
+		default:
+			parent = ""
+			current[key] = value
+		}
+	}
+	return sections
+}
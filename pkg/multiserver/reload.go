@@ -0,0 +1,222 @@
+package multiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReloadResult describes the changes applied by Manager.Reload.
+type ReloadResult struct {
+	// Added lists connections that were not configured before.
+	Added []string
+
+	// Removed lists connections that are no longer configured.
+	Removed []string
+
+	// Updated lists connections whose settings changed, such as rotated
+	// credentials. Their cached clients are closed and recreated on next use.
+	Updated []string
+}
+
+// IsZero returns true if the reload changed nothing.
+func (r ReloadResult) IsZero() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Updated) == 0
+}
+
+// ConnectionsChanged returns true if connections were added or removed.
+func (r ReloadResult) ConnectionsChanged() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0
+}
+
+// Validate checks that the configuration can be loaded into a Manager:
+// connection names are present and unique, the default connection exists,
+// and every connection has a valid client configuration.
+func (c *MultiConfig) Validate() error {
+	if len(c.Connections) == 0 {
+		return fmt.Errorf("no connections configured")
+	}
+
+	seen := make(map[string]bool, len(c.Connections))
+	for i := range c.Connections {
+		conn := &c.Connections[i]
+		if conn.Name == "" {
+			return fmt.Errorf("connection %d: name is required", i+1)
+		}
+		if seen[conn.Name] {
+			return fmt.Errorf("duplicate connection name %q", conn.Name)
+		}
+		seen[conn.Name] = true
+
+		if err := conn.ToClientConfig().Validate(); err != nil {
+			return fmt.Errorf("connection %s: %w", conn.Name, err)
+		}
+	}
+
+	if c.DefaultConnection != "" && !seen[c.DefaultConnection] {
+		return fmt.Errorf("default connection %q is not configured", c.DefaultConnection)
+	}
+	return c.validateGroups(seen)
+}
+
+// validateGroups checks that group names are unique and distinct from
+// connection names, and that every member is a configured connection with
+// the same guardrails as the group's primary.
+func (c *MultiConfig) validateGroups(connections map[string]bool) error {
+	seen := make(map[string]bool, len(c.Groups))
+	for i, g := range c.Groups {
+		if g.Name == "" {
+			return fmt.Errorf("group %d: name is required", i+1)
+		}
+		if seen[g.Name] || connections[g.Name] {
+			return fmt.Errorf("duplicate connection name %q", g.Name)
+		}
+		seen[g.Name] = true
+
+		if len(g.Members) == 0 {
+			return fmt.Errorf("group %s: at least one member is required", g.Name)
+		}
+		for _, member := range g.Members {
+			if !connections[member] {
+				return fmt.Errorf("group %s: member %q is not a configured connection", g.Name, member)
+			}
+		}
+		switch g.Strategy {
+		case "", GroupFailover, GroupLatency:
+		default:
+			return fmt.Errorf("group %s: unknown strategy %q (want %s or %s)", g.Name, g.Strategy, GroupFailover, GroupLatency)
+		}
+		if err := c.checkGroupGuardrails(&c.Groups[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkGroupGuardrails returns an error unless every member of g has the
+// primary's guardrails. The group applies the primary's guardrails to reads
+// that any member serves.
+func (c *MultiConfig) checkGroupGuardrails(g *GroupConfig) error {
+	primary := c.GetConnection(g.Members[0])
+	for _, member := range g.Members[1:] {
+		conn := c.GetConnection(member)
+		if conn.ReadOnly != primary.ReadOnly ||
+			conn.MaxGetSize != primary.MaxGetSize ||
+			conn.MaxPutSize != primary.MaxPutSize ||
+			!slices.Equal(conn.AllowedBuckets, primary.AllowedBuckets) ||
+			!slices.Equal(conn.AllowedPrefixes, primary.AllowedPrefixes) {
+			return fmt.Errorf("group %s: member %s must have the same read_only, max_get_size, max_put_size, "+
+				"allowed_buckets, and allowed_prefixes as %s", g.Name, member, primary.Name)
+		}
+	}
+	return nil
+}
+
+// clone returns a copy of the configuration that shares no connection slice
+// with the original.
+func (c *MultiConfig) clone() *MultiConfig {
+	return &MultiConfig{
+		DefaultConnection: c.DefaultConnection,
+		Connections:       append([]ConnectionConfig(nil), c.Connections...),
+		Groups:            append([]GroupConfig(nil), c.Groups...),
+	}
+}
+
+// LoadFile loads multi-connection configuration from a file, choosing the
+// format from the extension: .json is read as JSON, anything else as YAML.
+func LoadFile(path string) (*MultiConfig, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- Path is intentionally user-provided config file
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(path, data)
+}
+
+// parseConfig decodes file contents in the format LoadFile picks for path.
+func parseConfig(path string, data []byte) (*MultiConfig, error) {
+	var cfg MultiConfig
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+		return &cfg, nil
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Reload replaces the manager's configuration with cfg. The new
+// configuration is validated first; if it is invalid, or if it would remove
+// or rename the default connection, nothing changes and an error is returned.
+//
+// Connections that were removed or whose settings changed have their cached
+// clients closed, so the next call creates a client with the new settings.
+// Calls already running on an old client finish with it. All changes are
+// applied under a single lock, so callers never see a partial reload.
+func (m *Manager) Reload(cfg *MultiConfig) (ReloadResult, error) {
+	if cfg == nil {
+		return ReloadResult{}, fmt.Errorf("configuration must not be nil")
+	}
+	if err := cfg.Validate(); err != nil {
+		return ReloadResult{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+	next := cfg.clone()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// The toolkit resolves the default connection by name, so it must survive.
+	current := m.defaultConnectionName()
+	if current != "" {
+		if !next.hasConnection(current) {
+			return ReloadResult{}, fmt.Errorf("invalid configuration: default connection %q cannot be removed", current)
+		}
+		if next.DefaultConnection != "" && next.DefaultConnection != current {
+			return ReloadResult{}, fmt.Errorf("invalid configuration: default connection cannot change from %q to %q without a restart",
+				current, next.DefaultConnection)
+		}
+		// Pin the default so reordering the file cannot change it implicitly.
+		next.DefaultConnection = current
+	}
+
+	var result ReloadResult
+	for _, old := range m.config.Connections {
+		updated := next.GetConnection(old.Name)
+		var reason EvictReason
+		switch {
+		case updated == nil:
+			result.Removed = append(result.Removed, old.Name)
+			reason = EvictRemoved
+		case !reflect.DeepEqual(old, *updated):
+			result.Updated = append(result.Updated, old.Name)
+			reason = EvictReplaced
+		default:
+			continue
+		}
+		// Close errors are intentionally ignored, as in RemoveConnection.
+		_ = m.evictLocked(old.Name, reason)
+		m.forgetHealth(old.Name)
+	}
+	for _, conn := range next.Connections {
+		if !m.config.hasConnection(conn.Name) {
+			result.Added = append(result.Added, conn.Name)
+		}
+	}
+
+	m.config = next
+	added, removed, updated := m.syncGroups()
+	result.Added = append(result.Added, added...)
+	result.Removed = append(result.Removed, removed...)
+	result.Updated = append(result.Updated, updated...)
+	m.forgetHealth(removed...)
+	m.forgetHealth(updated...)
+	return result, nil
+}
@@ -0,0 +1,224 @@
+package multiserver
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// Secret reference forms understood by the built-in resolvers.
+const (
+	envRefPrefix     = "${env:"
+	envRefSuffix     = "}"
+	fileRefPrefix    = "file:"
+	keyfileRefPrefix = "keyfile:"
+)
+
+// keyfileMagic identifies an encrypted keyfile and its format version.
+const keyfileMagic = "mcps3kf1"
+
+// KeyfileKeySize is the required length in bytes of a keyfile key (AES-256).
+const KeyfileKeySize = 32
+
+// SecretResolver resolves secret references in connection credentials, such
+// as "${env:PROD_SECRET}". Resolvers are tried in order; a value that no
+// resolver handles is used as-is.
+type SecretResolver interface {
+	// Resolve returns the secret that ref refers to. handled is false when
+	// ref is not in a form this resolver understands. Errors must not
+	// include the secret value.
+	Resolve(ctx context.Context, ref string) (value string, handled bool, err error)
+}
+
+// SecretResolverFunc adapts a function to the SecretResolver interface.
+type SecretResolverFunc func(ctx context.Context, ref string) (string, bool, error)
+
+// Resolve calls f.
+func (f SecretResolverFunc) Resolve(ctx context.Context, ref string) (string, bool, error) {
+	return f(ctx, ref)
+}
+
+// EnvSecretResolver resolves "${env:NAME}" to the value of the environment
+// variable NAME.
+type EnvSecretResolver struct{}
+
+// Resolve implements SecretResolver.
+func (EnvSecretResolver) Resolve(_ context.Context, ref string) (string, bool, error) {
+	if !strings.HasPrefix(ref, envRefPrefix) || !strings.HasSuffix(ref, envRefSuffix) {
+		return "", false, nil
+	}
+	name := ref[len(envRefPrefix) : len(ref)-len(envRefSuffix)]
+	if name == "" {
+		return "", true, fmt.Errorf("empty environment variable name")
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", true, fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, true, nil
+}
+
+// FileSecretResolver resolves "file:/path" to the contents of the file, with
+// trailing newlines removed. This suits Docker and Kubernetes secret mounts.
+type FileSecretResolver struct{}
+
+// Resolve implements SecretResolver.
+func (FileSecretResolver) Resolve(_ context.Context, ref string) (string, bool, error) {
+	path, ok := strings.CutPrefix(ref, fileRefPrefix)
+	if !ok {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(path) //#nosec G304 -- Path is intentionally user-provided secret file
+	if err != nil {
+		return "", true, fmt.Errorf("reading secret file: %w", err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", true, fmt.Errorf("secret file %s is empty", path)
+	}
+	return value, true, nil
+}
+
+// KeyfileSecretResolver resolves "keyfile:NAME" to the secret stored under
+// NAME in an encrypted keyfile. The keyfile holds a JSON object of names to
+// values sealed with AES-256-GCM; create one with WriteKeyfile. The file is
+// read on every resolution, so updating it takes effect for new clients.
+type KeyfileSecretResolver struct {
+	path string
+	key  []byte
+}
+
+// NewKeyfileSecretResolver creates a resolver for the keyfile at path,
+// decrypted with a KeyfileKeySize-byte key.
+func NewKeyfileSecretResolver(path string, key []byte) (*KeyfileSecretResolver, error) {
+	if len(key) != KeyfileKeySize {
+		return nil, fmt.Errorf("keyfile key must be %d bytes, got %d", KeyfileKeySize, len(key))
+	}
+	return &KeyfileSecretResolver{path: path, key: append([]byte(nil), key...)}, nil
+}
+
+// Resolve implements SecretResolver.
+func (r *KeyfileSecretResolver) Resolve(_ context.Context, ref string) (string, bool, error) {
+	name, ok := strings.CutPrefix(ref, keyfileRefPrefix)
+	if !ok {
+		return "", false, nil
+	}
+	secrets, err := readKeyfile(r.path, r.key)
+	if err != nil {
+		return "", true, err
+	}
+	value, ok := secrets[name]
+	if !ok {
+		return "", true, fmt.Errorf("secret %q not found in keyfile", name)
+	}
+	return value, true, nil
+}
+
+// WriteKeyfile encrypts secrets with key and writes them to path with
+// owner-only permissions.
+func WriteKeyfile(path string, key []byte, secrets map[string]string) error {
+	gcm, err := newKeyfileCipher(key)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("encoding keyfile: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+
+	data := append([]byte(keyfileMagic), nonce...)
+	data = gcm.Seal(data, nonce, plaintext, []byte(keyfileMagic))
+	return os.WriteFile(path, data, 0o600)
+}
+
+// readKeyfile decrypts the keyfile at path.
+func readKeyfile(path string, key []byte) (map[string]string, error) {
+	gcm, err := newKeyfileCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //#nosec G304 -- Path is intentionally user-provided keyfile
+	if err != nil {
+		return nil, fmt.Errorf("reading keyfile: %w", err)
+	}
+
+	body, ok := strings.CutPrefix(string(data), keyfileMagic)
+	if !ok || len(body) < gcm.NonceSize() {
+		return nil, fmt.Errorf("%s is not an mcp-s3 keyfile", path)
+	}
+	nonce, ciphertext := []byte(body[:gcm.NonceSize()]), []byte(body[gcm.NonceSize():])
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(keyfileMagic))
+	if err != nil {
+		return nil, errors.New("decrypting keyfile: wrong key or corrupted file")
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("decoding keyfile: %w", err)
+	}
+	return secrets, nil
+}
+
+// newKeyfileCipher returns the AES-256-GCM cipher for key.
+func newKeyfileCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeyfileKeySize {
+		return nil, fmt.Errorf("keyfile key must be %d bytes, got %d", KeyfileKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// defaultSecretResolvers returns the resolvers every Manager starts with.
+func defaultSecretResolvers() []SecretResolver {
+	return []SecretResolver{EnvSecretResolver{}, FileSecretResolver{}}
+}
+
+// resolveSecret resolves ref with the first resolver that handles it.
+func resolveSecret(ctx context.Context, resolvers []SecretResolver, ref string) (string, error) {
+	if ref == "" {
+		return "", nil
+	}
+	for _, r := range resolvers {
+		value, handled, err := r.Resolve(ctx, ref)
+		if handled {
+			return value, err
+		}
+	}
+	return ref, nil
+}
+
+// resolveSecrets replaces secret references in the credential fields of cfg.
+// Errors name the field but never its value.
+func resolveSecrets(ctx context.Context, resolvers []SecretResolver, cfg *client.Config) error {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"access_key_id", &cfg.AccessKeyID},
+		{"secret_access_key", &cfg.SecretAccessKey},
+		{"session_token", &cfg.SessionToken},
+	}
+	for _, f := range fields {
+		value, err := resolveSecret(ctx, resolvers, *f.value)
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", f.name, err)
+		}
+		*f.value = value
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package multiserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultPollInterval is how often a Watcher checks the configuration file
+// when file system notifications are unavailable.
+const DefaultPollInterval = 5 * time.Second
+
+// watchDebounce groups the bursts of events editors and Kubernetes ConfigMap
+// updates produce into a single reload.
+const watchDebounce = 100 * time.Millisecond
+
+// Watcher reloads a Manager when its configuration file changes. It watches
+// the file's directory for notifications, which also catches editors that
+// replace the file and Kubernetes ConfigMap symlink swaps, and falls back to
+// polling when notifications are unavailable. A file that fails to parse or
+// validate is logged and ignored; the previous configuration stays live.
+type Watcher struct {
+	manager  *Manager
+	path     string
+	interval time.Duration
+	logger   *slog.Logger
+	onReload func(ReloadResult)
+	profiles *ProfileDiscovery
+
+	mu   sync.Mutex
+	hash [sha256.Size]byte
+}
+
+// WatcherOption configures a Watcher.
+type WatcherOption func(*Watcher)
+
+// WithPollInterval sets how often the file is polled when notifications are
+// unavailable.
+func WithPollInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.interval = d
+		}
+	}
+}
+
+// WithWatchLogger sets the logger for reload results and rejected files.
+func WithWatchLogger(logger *slog.Logger) WatcherOption {
+	return func(w *Watcher) {
+		if logger != nil {
+			w.logger = logger
+		}
+	}
+}
+
+// WithOnReload sets a function called after each reload that changed the
+// configuration.
+func WithOnReload(fn func(ReloadResult)) WatcherOption {
+	return func(w *Watcher) {
+		w.onReload = fn
+	}
+}
+
+// WithProfileDiscovery merges discovered AWS profiles into each reloaded
+// configuration, as ProfileDiscovery.Merge does, so they survive reloads.
+func WithProfileDiscovery(d ProfileDiscovery) WatcherOption {
+	return func(w *Watcher) {
+		w.profiles = &d
+	}
+}
+
+// NewWatcher creates a watcher that reloads manager from the file at path.
+// The file's current contents are taken as already loaded.
+func NewWatcher(manager *Manager, path string, opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		manager:  manager,
+		path:     path,
+		interval: DefaultPollInterval,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if data, err := os.ReadFile(path); err == nil { //#nosec G304 -- Path is intentionally user-provided config file
+		w.hash = sha256.Sum256(data)
+	}
+	return w
+}
+
+// Run watches the file until ctx is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = fsw.Add(filepath.Dir(w.path)); err == nil {
+			defer func() { _ = fsw.Close() }()
+			// Pick up changes made between NewWatcher and the watch starting.
+			w.check()
+			return w.watch(ctx, fsw)
+		}
+		_ = fsw.Close()
+	}
+	w.logger.Warn("file notifications unavailable, polling config file",
+		"path", w.path, "interval", w.interval, "error", err)
+	w.check()
+	return w.poll(ctx)
+}
+
+// watch reloads on file system events, debounced.
+func (w *Watcher) watch(ctx context.Context, fsw *fsnotify.Watcher) error {
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-fsw.Events:
+			if !ok {
+				return fmt.Errorf("config watcher closed")
+			}
+			// Any event in the directory may replace the file through a
+			// rename or symlink swap; the content hash filters out the rest.
+			debounce.Reset(watchDebounce)
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return fmt.Errorf("config watcher closed")
+			}
+			w.logger.Warn("config watcher error", "path", w.path, "error", err)
+		case <-debounce.C:
+			w.check()
+		}
+	}
+}
+
+// poll reloads when the file contents change between ticks.
+func (w *Watcher) poll(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check reloads the file if its contents changed, logging the outcome.
+func (w *Watcher) check() {
+	result, changed, err := w.reload(false)
+	switch {
+	case err != nil:
+		w.logger.Error("config reload rejected; keeping previous configuration", "path", w.path, "error", err)
+	case changed && !result.IsZero():
+		w.logger.Info("config reloaded", "path", w.path,
+			"added", result.Added, "removed", result.Removed, "updated", result.Updated)
+		if w.onReload != nil {
+			w.onReload(result)
+		}
+	}
+}
+
+// Reload reads the file and applies it to the manager now, even if its
+// contents have not changed since the last reload.
+func (w *Watcher) Reload() (ReloadResult, error) {
+	result, _, err := w.reload(true)
+	if err == nil && !result.IsZero() && w.onReload != nil {
+		w.onReload(result)
+	}
+	return result, err
+}
+
+// reload applies the file if it changed or force is set. It reports whether
+// the file was applied.
+func (w *Watcher) reload(force bool) (ReloadResult, bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data, err := os.ReadFile(w.path) //#nosec G304 -- Path is intentionally user-provided config file
+	if err != nil {
+		return ReloadResult{}, false, err
+	}
+	hash := sha256.Sum256(data)
+	if !force && bytes.Equal(hash[:], w.hash[:]) {
+		return ReloadResult{}, false, nil
+	}
+
+	cfg, err := parseConfig(w.path, data)
+	if err != nil {
+		// Remember the hash so a broken file is reported once, not every tick.
+		w.hash = hash
+		return ReloadResult{}, false, err
+	}
+	if w.profiles != nil {
+		if cfg, err = w.profiles.Merge(cfg); err != nil {
+			w.hash = hash
+			return ReloadResult{}, false, err
+		}
+	}
+	result, err := w.manager.Reload(cfg)
+	w.hash = hash
+	if err != nil {
+		return ReloadResult{}, false, err
+	}
+	return result, true, nil
+}
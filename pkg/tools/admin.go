@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ConnectionAdmin changes the server's connections for the admin tools.
+// multiserver.Admin implements this interface.
+type ConnectionAdmin interface {
+	// AddConnection probes config and, if the probe succeeds, adds it as
+	// connection name. An existing connection is only replaced when replace
+	// is set. With persist, the change is also saved to the configuration file.
+	AddConnection(ctx context.Context, name string, config map[string]any, replace, persist bool) (HealthStatus, error)
+
+	// RemoveConnection removes the named connection.
+	RemoveConnection(ctx context.Context, name string, persist bool) error
+
+	// TestConnection probes config without adding it, or the existing
+	// connection name when config is nil.
+	TestConnection(ctx context.Context, name string, config map[string]any) (HealthStatus, error)
+}
+
+// Actions reported by the connection admin tools.
+const (
+	AdminActionAdded    = "added"
+	AdminActionReplaced = "replaced"
+	AdminActionRemoved  = "removed"
+	AdminActionTested   = "tested"
+)
+
+// ConnectionAdminResult represents the result of a connection admin tool.
+type ConnectionAdminResult struct {
+	Connection string      `json:"connection"`
+	Action     string      `json:"action"`
+	Persisted  bool        `json:"persisted,omitempty"`
+	Health     *HealthInfo `json:"health,omitempty"`
+}
+
+// registerAddConnectionTool registers the s3_add_connection tool.
+func (t *Toolkit) registerAddConnectionTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		addInput, ok := input.(AddConnectionInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleAddConnection(ctx, req, addInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolAddConnection, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolAddConnection),
+		Title:        t.getTitle(ToolAddConnection, cfg),
+		Description:  t.getDescription(ToolAddConnection, cfg),
+		Annotations:  t.getAnnotations(ToolAddConnection, cfg),
+		Icons:        t.getIcons(ToolAddConnection, cfg),
+		OutputSchema: t.getOutputSchema(ToolAddConnection, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input AddConnectionInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[ConnectionAdminResult](out), err
+	})
+}
+
+// handleAddConnection handles the s3_add_connection tool request.
+func (t *Toolkit) handleAddConnection(ctx context.Context, _ *mcp.CallToolRequest, input AddConnectionInput) (*mcp.CallToolResult, any, error) {
+	if t.connectionAdmin == nil {
+		return ErrorResult("connection administration is not enabled"), nil, nil
+	}
+	if t.readOnly {
+		return ErrorResultFor(ErrReadOnly), nil, nil
+	}
+	if input.Name == "" {
+		return ErrorResult("name is required"), nil, nil
+	}
+	if input.Config == nil {
+		return ErrorResult("config is required"), nil, nil
+	}
+
+	action := AdminActionAdded
+	if slices.Contains(t.ListConnections(), input.Name) {
+		if !input.Replace {
+			return ErrorResultf("connection %s already exists; set replace to true to replace it", input.Name), nil, nil
+		}
+		action = AdminActionReplaced
+	}
+
+	health, err := t.connectionAdmin.AddConnection(ctx, input.Name, input.Config, input.Replace, input.Persist)
+	if err != nil {
+		return ErrorResultFor(fmt.Errorf("failed to add connection %s: %w", input.Name, err)), nil, nil
+	}
+
+	result := ConnectionAdminResult{
+		Connection: input.Name,
+		Action:     action,
+		Persisted:  input.Persist,
+		Health:     healthInfo(health),
+	}
+	return adminResult(result)
+}
+
+// registerRemoveConnectionTool registers the s3_remove_connection tool.
+func (t *Toolkit) registerRemoveConnectionTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		removeInput, ok := input.(RemoveConnectionInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleRemoveConnection(ctx, req, removeInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolRemoveConnection, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolRemoveConnection),
+		Title:        t.getTitle(ToolRemoveConnection, cfg),
+		Description:  t.getDescription(ToolRemoveConnection, cfg),
+		Annotations:  t.getAnnotations(ToolRemoveConnection, cfg),
+		Icons:        t.getIcons(ToolRemoveConnection, cfg),
+		OutputSchema: t.getOutputSchema(ToolRemoveConnection, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input RemoveConnectionInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[ConnectionAdminResult](out), err
+	})
+}
+
+// handleRemoveConnection handles the s3_remove_connection tool request.
+func (t *Toolkit) handleRemoveConnection(ctx context.Context, _ *mcp.CallToolRequest, input RemoveConnectionInput) (*mcp.CallToolResult, any, error) {
+	if t.connectionAdmin == nil {
+		return ErrorResult("connection administration is not enabled"), nil, nil
+	}
+	if t.readOnly {
+		return ErrorResultFor(ErrReadOnly), nil, nil
+	}
+	if input.Name == "" {
+		return ErrorResult("name is required"), nil, nil
+	}
+
+	if err := t.connectionAdmin.RemoveConnection(ctx, input.Name, input.Persist); err != nil {
+		return ErrorResultFor(fmt.Errorf("failed to remove connection %s: %w", input.Name, err)), nil, nil
+	}
+
+	result := ConnectionAdminResult{
+		Connection: input.Name,
+		Action:     AdminActionRemoved,
+		Persisted:  input.Persist,
+	}
+	return adminResult(result)
+}
+
+// registerTestConnectionTool registers the s3_test_connection tool.
+func (t *Toolkit) registerTestConnectionTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		testInput, ok := input.(TestConnectionInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleTestConnection(ctx, req, testInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolTestConnection, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolTestConnection),
+		Title:        t.getTitle(ToolTestConnection, cfg),
+		Description:  t.getDescription(ToolTestConnection, cfg),
+		Annotations:  t.getAnnotations(ToolTestConnection, cfg),
+		Icons:        t.getIcons(ToolTestConnection, cfg),
+		OutputSchema: t.getOutputSchema(ToolTestConnection, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input TestConnectionInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[ConnectionAdminResult](out), err
+	})
+}
+
+// handleTestConnection handles the s3_test_connection tool request. A failed
+// probe is a successful call whose health reports the error.
+func (t *Toolkit) handleTestConnection(ctx context.Context, _ *mcp.CallToolRequest, input TestConnectionInput) (*mcp.CallToolResult, any, error) {
+	if t.connectionAdmin == nil {
+		return ErrorResult("connection administration is not enabled"), nil, nil
+	}
+	name := input.Name
+	if name == "" && input.Config == nil {
+		name = t.defaultConnection
+	}
+
+	health, err := t.connectionAdmin.TestConnection(ctx, name, input.Config)
+	if err != nil {
+		return ErrorResultFor(fmt.Errorf("failed to test connection %s: %w", name, err)), nil, nil
+	}
+
+	result := ConnectionAdminResult{
+		Connection: name,
+		Action:     AdminActionTested,
+		Health:     healthInfo(health),
+	}
+	return adminResult(result)
+}
+
+// adminResult formats a connection admin result.
+func adminResult(result ConnectionAdminResult) (*mcp.CallToolResult, any, error) {
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
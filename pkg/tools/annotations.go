@@ -30,6 +30,46 @@ var defaultAnnotations = map[ToolName]*mcp.ToolAnnotations{
 		IdempotentHint: true,
 		OpenWorldHint:  boolPtr(true),
 	},
+	ToolFindObjects: {
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
+	ToolSummarizePrefix: {
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
+	ToolTree: {
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
+	ToolGrep: {
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
+	ToolListArchive: {
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
+	ToolGetArchiveEntry: {
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
+	ToolInspectParquet: {
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
+	ToolPreviewTable: {
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
 	ToolGetObject: {
 		ReadOnlyHint:   true,
 		IdempotentHint: true,
@@ -58,6 +98,23 @@ var defaultAnnotations = map[ToolName]*mcp.ToolAnnotations{
 		IdempotentHint: true,
 		OpenWorldHint:  boolPtr(true),
 	},
+	ToolGetQuota: {
+		ReadOnlyHint:  true,
+		OpenWorldHint: boolPtr(true),
+	},
+	ToolAddConnection: {
+		DestructiveHint: boolPtr(false),
+		IdempotentHint:  true,
+		OpenWorldHint:   boolPtr(true),
+	},
+	ToolRemoveConnection: {
+		IdempotentHint: true,
+		OpenWorldHint:  boolPtr(true),
+	},
+	ToolTestConnection: {
+		ReadOnlyHint:  true,
+		OpenWorldHint: boolPtr(true),
+	},
 }
 
 // DefaultAnnotations returns the default annotations for a tool.
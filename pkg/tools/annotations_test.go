@@ -25,7 +25,7 @@ func TestDefaultAnnotations(t *testing.T) {
 	})
 
 	t.Run("all tools have defaults", func(t *testing.T) {
-		for _, name := range AllTools() {
+		for _, name := range append(AllTools(), AdminTools()...) {
 			ann := DefaultAnnotations(name)
 			if ann == nil {
 				t.Errorf("tool %s has no default annotations", name)
@@ -83,7 +83,7 @@ func TestDefaultAnnotations(t *testing.T) {
 	})
 
 	t.Run("all tools are open-world", func(t *testing.T) {
-		for _, name := range AllTools() {
+		for _, name := range append(AllTools(), AdminTools()...) {
 			ann := DefaultAnnotations(name)
 			if ann == nil {
 				t.Fatalf("tool %s has no default annotations", name)
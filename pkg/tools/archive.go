@@ -0,0 +1,504 @@
+package tools
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+	"github.com/txn2/mcp-s3/pkg/integration"
+)
+
+// Archive formats read by s3_list_archive and s3_get_archive_entry.
+const (
+	ArchiveFormatZip = "zip"
+	ArchiveFormatTar = "tar"
+)
+
+// Limits for s3_list_archive and s3_get_archive_entry.
+const (
+	defaultArchiveMaxEntries = 1000
+	maxArchiveMaxEntries     = 10000
+
+	// defaultArchiveEntryBytes is the entry size limit when the connection
+	// has no retrieval size limit.
+	defaultArchiveEntryBytes = 10 * 1024 * 1024
+
+	// maxArchiveScanBytes caps the bytes read from an archive. A tar archive
+	// has no index, and a compressed one must be decompressed from the start.
+	maxArchiveScanBytes = 1024 * 1024 * 1024
+
+	// archiveSniffLen is the number of leading bytes read to detect the
+	// format of an archive whose key has no recognized extension.
+	archiveSniffLen = 512
+)
+
+// ArchiveEntry describes a file or directory in an archive.
+type ArchiveEntry struct {
+	Name           string `json:"name"`
+	Size           int64  `json:"size"`
+	CompressedSize int64  `json:"compressed_size,omitempty"`
+	Modified       string `json:"modified,omitempty"`
+	IsDir          bool   `json:"is_dir,omitempty"`
+}
+
+// ListArchiveResult represents the result of listing an archive.
+type ListArchiveResult struct {
+	Bucket      string         `json:"bucket"`
+	Key         string         `json:"key"`
+	Format      string         `json:"format"`
+	Compression string         `json:"compression,omitempty"`
+	Size        int64          `json:"size"`
+	Entries     []ArchiveEntry `json:"entries"`
+	Count       int            `json:"count"`
+	TotalSize   int64          `json:"total_size"`
+	BytesRead   int64          `json:"bytes_read"`
+	IsTruncated bool           `json:"is_truncated"`
+	StopReason  string         `json:"stop_reason,omitempty"`
+	ServedBy    string         `json:"served_by,omitempty"`
+}
+
+// GetArchiveEntryResult represents the result of reading one archive entry.
+type GetArchiveEntryResult struct {
+	Bucket       string `json:"bucket"`
+	Key          string `json:"key"`
+	Entry        string `json:"entry"`
+	Format       string `json:"format"`
+	Size         int64  `json:"size"`
+	ContentType  string `json:"content_type,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Content      string `json:"content"`
+	IsBase64     bool   `json:"is_base64"`
+	BytesRead    int64  `json:"bytes_read"`
+	ServedBy     string `json:"served_by,omitempty"`
+}
+
+// registerListArchiveTool registers the s3_list_archive tool.
+func (t *Toolkit) registerListArchiveTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		listInput, ok := input.(ListArchiveInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleListArchive(ctx, req, listInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolListArchive, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolListArchive),
+		Title:        t.getTitle(ToolListArchive, cfg),
+		Description:  t.getDescription(ToolListArchive, cfg),
+		Annotations:  t.getAnnotations(ToolListArchive, cfg),
+		Icons:        t.getIcons(ToolListArchive, cfg),
+		OutputSchema: t.getOutputSchema(ToolListArchive, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListArchiveInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[ListArchiveResult](out), err
+	})
+}
+
+// registerGetArchiveEntryTool registers the s3_get_archive_entry tool.
+func (t *Toolkit) registerGetArchiveEntryTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		getInput, ok := input.(GetArchiveEntryInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleGetArchiveEntry(ctx, req, getInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolGetArchiveEntry, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolGetArchiveEntry),
+		Title:        t.getTitle(ToolGetArchiveEntry, cfg),
+		Description:  t.getDescription(ToolGetArchiveEntry, cfg),
+		Annotations:  t.getAnnotations(ToolGetArchiveEntry, cfg),
+		Icons:        t.getIcons(ToolGetArchiveEntry, cfg),
+		OutputSchema: t.getOutputSchema(ToolGetArchiveEntry, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetArchiveEntryInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[GetArchiveEntryResult](out), err
+	})
+}
+
+// handleListArchive handles the s3_list_archive tool request.
+func (t *Toolkit) handleListArchive(ctx context.Context, _ *mcp.CallToolRequest, input ListArchiveInput) (*mcp.CallToolResult, any, error) {
+	if input.Bucket == "" {
+		return ErrorResult("bucket parameter is required"), nil, nil
+	}
+	if input.Key == "" {
+		return ErrorResult("key parameter is required"), nil, nil
+	}
+	if err := validArchiveFormat(input.Format); err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	maxEntries := clampInt(input.MaxEntries, defaultArchiveMaxEntries, maxArchiveMaxEntries)
+
+	s3Client, release, err := t.holdClient(input.Connection)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	defer release()
+	guard := t.guardFor(s3Client)
+	if err := guard.checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	ctx, servedBy := withServedBy(ctx)
+	archive, errResult := t.openArchive(ctx, s3Client, guard, input.Bucket, input.Key, input.Format)
+	if errResult != nil {
+		return errResult, nil, nil
+	}
+	defer func() { _ = archive.Close() }()
+
+	result := ListArchiveResult{
+		Bucket:      input.Bucket,
+		Key:         input.Key,
+		Format:      archive.format,
+		Compression: archive.compression,
+		Size:        archive.obj.Size(),
+		Entries:     make([]ArchiveEntry, 0),
+	}
+	err = archive.walk(func(entry ArchiveEntry, _ func() (io.ReadCloser, error)) bool {
+		if len(result.Entries) >= maxEntries {
+			result.IsTruncated = true
+			result.StopReason = StopReasonMaxEntries
+			return false
+		}
+		result.Entries = append(result.Entries, entry)
+		result.TotalSize += entry.Size
+		return true
+	})
+	switch {
+	case errors.Is(err, errReadBudget):
+		result.IsTruncated = true
+		result.StopReason = StopReasonByteBudget
+	case err != nil:
+		return ErrorResultFor(fmt.Errorf("failed to read archive: %w", err)), nil, nil
+	}
+	result.Count = len(result.Entries)
+	result.BytesRead = archive.obj.bytesRead
+	result.ServedBy = servedBy()
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
+
+// handleGetArchiveEntry handles the s3_get_archive_entry tool request.
+func (t *Toolkit) handleGetArchiveEntry(ctx context.Context, _ *mcp.CallToolRequest, input GetArchiveEntryInput) (*mcp.CallToolResult, any, error) {
+	if input.Bucket == "" {
+		return ErrorResult("bucket parameter is required"), nil, nil
+	}
+	if input.Key == "" {
+		return ErrorResult("key parameter is required"), nil, nil
+	}
+	if input.Entry == "" {
+		return ErrorResult("entry parameter is required"), nil, nil
+	}
+	if err := validArchiveFormat(input.Format); err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+
+	s3Client, release, err := t.holdClient(input.Connection)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	defer release()
+	guard := t.guardFor(s3Client)
+	if err := guard.checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+	limit := int64(defaultArchiveEntryBytes)
+	if guard.maxGetSize > 0 {
+		limit = guard.maxGetSize
+	}
+
+	ctx, servedBy := withServedBy(ctx)
+	archive, errResult := t.openArchive(ctx, s3Client, guard, input.Bucket, input.Key, input.Format)
+	if errResult != nil {
+		return errResult, nil, nil
+	}
+	defer func() { _ = archive.Close() }()
+
+	var (
+		found   *ArchiveEntry
+		data    []byte
+		readErr error
+	)
+	err = archive.walk(func(entry ArchiveEntry, open func() (io.ReadCloser, error)) bool {
+		if entry.Name != input.Entry && strings.TrimPrefix(entry.Name, "./") != input.Entry {
+			return true
+		}
+		found = &entry
+		if entry.IsDir {
+			return false
+		}
+		data, readErr = readArchiveEntry(entry, open, limit)
+		return false
+	})
+	if err == nil && readErr != nil {
+		err = archive.readErr(readErr)
+	}
+	switch {
+	case errors.Is(err, errReadBudget):
+		return ErrorResultf("byte budget reached after reading %d bytes of the archive without reading entry %q", archive.obj.bytesRead, input.Entry), nil, nil
+	case err != nil:
+		return ErrorResultFor(fmt.Errorf("failed to read archive entry: %w", err)), nil, nil
+	case found == nil:
+		return ErrorResultf("entry %q not found in archive", input.Entry), nil, nil
+	case found.IsDir:
+		return ErrorResultf("entry %q is a directory", input.Entry), nil, nil
+	}
+
+	content := &client.ObjectContent{
+		Key:         found.Name,
+		Body:        data,
+		ContentType: mime.TypeByExtension(path.Ext(found.Name)),
+		Size:        int64(len(data)),
+	}
+	if found.Modified != "" {
+		content.LastModified, _ = time.Parse(time.RFC3339, found.Modified)
+	}
+	entryResult := buildGetResult(input.Bucket, input.Key, content)
+	result := GetArchiveEntryResult{
+		Bucket:       input.Bucket,
+		Key:          input.Key,
+		Entry:        found.Name,
+		Format:       archive.format,
+		Size:         entryResult.Size,
+		ContentType:  entryResult.ContentType,
+		LastModified: entryResult.LastModified,
+		Content:      entryResult.Content,
+		IsBase64:     entryResult.IsBase64,
+		BytesRead:    archive.obj.bytesRead,
+		ServedBy:     servedBy(),
+	}
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
+
+// validArchiveFormat returns an error unless format is empty or a supported
+// archive format.
+func validArchiveFormat(format string) error {
+	switch format {
+	case "", ArchiveFormatZip, ArchiveFormatTar:
+		return nil
+	}
+	return fmt.Errorf("invalid format %q: must be %q or %q", format, ArchiveFormatZip, ArchiveFormatTar)
+}
+
+// readArchiveEntry reads the content of entry, failing if it is larger than
+// limit bytes.
+func readArchiveEntry(entry ArchiveEntry, open func() (io.ReadCloser, error), limit int64) ([]byte, error) {
+	if entry.Size > limit {
+		return nil, fmt.Errorf("%w: entry size %d bytes exceeds limit of %d bytes", ErrSizeLimitExceeded, entry.Size, limit)
+	}
+	rc, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: entry expands past limit of %d bytes", ErrSizeLimitExceeded, limit)
+	}
+	return data, nil
+}
+
+// archiveReader reads the entries of a zip or tar archive stored in S3.
+type archiveReader struct {
+	format      string
+	compression string
+	obj         *objectReader
+	zip         *zip.Reader
+	tar         *tar.Reader
+	closer      io.Closer
+
+	// openErr is returned by walk when the archive index could not be read
+	// within the byte budget.
+	openErr error
+}
+
+// openArchive opens the archive at bucket/key. The format is taken from
+// format when set, then from the key's extension, then from the leading bytes
+// of the object. Zip archives are read through their central directory; tar
+// archives are streamed from the start, decompressing them if needed.
+func (t *Toolkit) openArchive(
+	ctx context.Context, s3Client S3Client, guard connectionGuard, bucket, key, format string,
+) (*archiveReader, *mcp.CallToolResult) {
+	meta, err := s3Client.GetObjectMetadata(ctx, bucket, key)
+	if err != nil {
+		return nil, S3ErrorResult("get object metadata", err)
+	}
+	obj := newObjectReader(ctx, s3Client, bucket, key, meta.Size)
+
+	if format == "" {
+		format = archiveFormatFromKey(key)
+	}
+	var head []byte
+	if format != ArchiveFormatZip {
+		head, err = obj.head(archiveSniffLen)
+		if err != nil {
+			return nil, S3ErrorResult("get object", err)
+		}
+	}
+	if format == "" {
+		format = sniffArchiveFormat(head)
+		if format == "" {
+			return nil, ErrorResultf("cannot detect the archive format of %q: set format to %q or %q", key, ArchiveFormatZip, ArchiveFormatTar)
+		}
+	}
+
+	budget := int64(maxArchiveScanBytes)
+	if guard.maxGetSize > 0 {
+		budget = min(budget, guard.maxGetSize)
+	}
+
+	archive := &archiveReader{format: format, obj: obj}
+	if format == ArchiveFormatZip {
+		obj.budget = budget
+		archive.zip, err = zip.NewReader(obj, obj.Size())
+		if err != nil && archive.readErr(err) == errReadBudget {
+			archive.openErr = errReadBudget
+			return archive, nil
+		}
+		if err != nil && !errors.Is(err, zip.ErrInsecurePath) {
+			return nil, ErrorResultFor(fmt.Errorf("failed to read zip archive: %w", err))
+		}
+		return archive, nil
+	}
+
+	archive.compression = integration.DetectCompression("", key, head)
+	obj.budget = budget
+	if archive.compression == integration.CompressionNone {
+		// Reading an uncompressed tar archive seeks past entry content, so
+		// only the blocks holding headers count toward the budget.
+		archive.tar = tar.NewReader(obj)
+		return archive, nil
+	}
+	zr, err := integration.NewDecompressReader(obj, archive.compression)
+	if err != nil {
+		return nil, ErrorResultFor(fmt.Errorf("failed to read tar archive: %w", err))
+	}
+	archive.tar = tar.NewReader(zr)
+	archive.closer = zr
+	return archive, nil
+}
+
+// walk calls visit for each entry in archive order until visit returns
+// false. open returns a reader of the entry's content and is only valid
+// during the call to visit.
+func (a *archiveReader) walk(visit func(entry ArchiveEntry, open func() (io.ReadCloser, error)) bool) error {
+	if a.openErr != nil {
+		return a.openErr
+	}
+	if a.zip != nil {
+		for _, f := range a.zip.File {
+			entry := ArchiveEntry{
+				Name:           f.Name,
+				Size:           int64(f.UncompressedSize64),
+				CompressedSize: int64(f.CompressedSize64),
+				IsDir:          f.FileInfo().IsDir(),
+			}
+			if !f.Modified.IsZero() {
+				entry.Modified = f.Modified.UTC().Format(time.RFC3339)
+			}
+			if !visit(entry, f.Open) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	for {
+		hdr, err := a.tar.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && !errors.Is(err, tar.ErrInsecurePath) {
+			return a.readErr(err)
+		}
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		entry := ArchiveEntry{
+			Name:  hdr.Name,
+			Size:  hdr.Size,
+			IsDir: hdr.Typeflag == tar.TypeDir,
+		}
+		if !hdr.ModTime.IsZero() {
+			entry.Modified = hdr.ModTime.UTC().Format(time.RFC3339)
+		}
+		open := func() (io.ReadCloser, error) { return io.NopCloser(a.tar), nil }
+		if !visit(entry, open) {
+			return nil
+		}
+	}
+}
+
+// readErr returns errReadBudget for a read that failed because the byte
+// budget ran out, which a decompressor may report as a different error.
+func (a *archiveReader) readErr(err error) error {
+	if a.obj.budgetSpent() {
+		return errReadBudget
+	}
+	return err
+}
+
+// Close releases the decompressor of a compressed tar archive.
+func (a *archiveReader) Close() error {
+	if a.closer != nil {
+		return a.closer.Close()
+	}
+	return nil
+}
+
+// archiveFormatFromKey returns the archive format named by the key's
+// extension, ignoring a compression extension, or an empty string.
+func archiveFormatFromKey(key string) string {
+	switch strings.ToLower(path.Ext(integration.StripCompressionExt(key))) {
+	case ".zip", ".jar", ".war", ".whl":
+		return ArchiveFormatZip
+	case ".tar":
+		return ArchiveFormatTar
+	}
+	return ""
+}
+
+// sniffArchiveFormat returns the archive format identified by the leading
+// bytes of an object, or an empty string. Compressed content is assumed to
+// be a tar archive.
+func sniffArchiveFormat(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return ArchiveFormatZip
+	case integration.DetectCompression("", "", head) != integration.CompressionNone:
+		return ArchiveFormatTar
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return ArchiveFormatTar
+	}
+	return ""
+}
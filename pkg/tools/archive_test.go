@@ -0,0 +1,311 @@
+package tools
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// archiveFile is a file written into a test archive. A name ending in "/" is
+// a directory.
+type archiveFile struct {
+	name string
+	body []byte
+}
+
+// bigArchiveFile is an entry large enough that reading it in full would show
+// up in bytes_read.
+var bigArchiveFile = archiveFile{"data/big.bin", bytes.Repeat([]byte{0xff, 0x00}, 1024*1024)}
+
+func zipBytes(t *testing.T, files ...archiveFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Store,
+			Modified: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(f.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarBytes(t *testing.T, files ...archiveFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:     f.name,
+			Mode:     0o644,
+			Size:     int64(len(f.body)),
+			ModTime:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			Typeflag: tar.TypeReg,
+		}
+		if strings.HasSuffix(f.name, "/") {
+			hdr.Typeflag, hdr.Mode = tar.TypeDir, 0o755
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(f.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestListArchive(t *testing.T) {
+	files := []archiveFile{
+		{"docs/", nil},
+		{"docs/README.md", []byte("# Hello\n")},
+		bigArchiveFile,
+	}
+	tarball := tarBytes(t, files...)
+	mock := newContentMock(map[string][]byte{
+		"app.zip":     zipBytes(t, files...),
+		"site.tar":    tarball,
+		"site.tgz":    gzipBytes(t, string(tarball)),
+		"backup.bin":  tarball,
+		"notes.txt":   []byte("not an archive"),
+		"renamed.dat": zipBytes(t, files...),
+	})
+	toolkit := NewToolkit(mock)
+
+	list := func(input ListArchiveInput) *ListArchiveResult {
+		t.Helper()
+		input.Bucket = "bucket"
+		result, out, _ := toolkit.handleListArchive(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error for %s: %v", input.Key, result.Content)
+		}
+		return out.(*ListArchiveResult)
+	}
+	names := func(r *ListArchiveResult) []string {
+		var n []string
+		for _, e := range r.Entries {
+			n = append(n, e.Name)
+		}
+		return n
+	}
+	want := []string{"docs/", "docs/README.md", "data/big.bin"}
+
+	for _, tc := range []struct {
+		key         string
+		format      string
+		compression string
+		ranged      bool
+	}{
+		{key: "app.zip", format: ArchiveFormatZip, ranged: true},
+		{key: "renamed.dat", format: ArchiveFormatZip, ranged: true},
+		{key: "site.tar", format: ArchiveFormatTar, ranged: true},
+		{key: "backup.bin", format: ArchiveFormatTar, ranged: true},
+		{key: "site.tgz", format: ArchiveFormatTar, compression: "gzip"},
+	} {
+		got := list(ListArchiveInput{Key: tc.key})
+		if got.Format != tc.format || got.Compression != tc.compression {
+			t.Errorf("%s: format = %q compression = %q", tc.key, got.Format, got.Compression)
+		}
+		if !slices.Equal(names(got), want) || got.Count != 3 || got.IsTruncated {
+			t.Errorf("%s: entries = %v count = %d truncated = %v", tc.key, names(got), got.Count, got.IsTruncated)
+		}
+		if e := got.Entries[2]; e.Size != int64(len(bigArchiveFile.body)) || e.Modified != "2024-05-01T12:00:00Z" || e.IsDir {
+			t.Errorf("%s: big entry = %+v", tc.key, e)
+		}
+		if !got.Entries[0].IsDir {
+			t.Errorf("%s: directory entry = %+v", tc.key, got.Entries[0])
+		}
+		if tc.ranged && got.BytesRead >= int64(len(bigArchiveFile.body)) {
+			t.Errorf("%s: read %d of %d bytes, want the entry content skipped", tc.key, got.BytesRead, got.Size)
+		}
+	}
+
+	got := list(ListArchiveInput{Key: "site.tar", MaxEntries: 2})
+	if got.Count != 2 || !got.IsTruncated || got.StopReason != StopReasonMaxEntries {
+		t.Errorf("max entries: count = %d truncated = %v reason = %q", got.Count, got.IsTruncated, got.StopReason)
+	}
+
+	for _, input := range []ListArchiveInput{
+		{Bucket: "bucket"},
+		{Bucket: "bucket", Key: "app.zip", Format: "rar"},
+		{Bucket: "bucket", Key: "notes.txt"},
+		{Bucket: "bucket", Key: "notes.txt", Format: ArchiveFormatZip},
+		{Bucket: "bucket", Key: "missing.zip"},
+	} {
+		if result, _, _ := toolkit.handleListArchive(context.Background(), nil, input); !result.IsError {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
+
+func TestListArchive_ByteBudget(t *testing.T) {
+	tarball := tarBytes(t, bigArchiveFile, archiveFile{"after.txt", []byte("late")})
+	mock := newContentMock(map[string][]byte{"site.tar.gz": gzipBytes(t, string(tarball))})
+	toolkit := NewToolkit(mock, WithMaxGetSize(1024))
+
+	result, out, _ := toolkit.handleListArchive(context.Background(), nil, ListArchiveInput{Bucket: "bucket", Key: "site.tar.gz"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	got := out.(*ListArchiveResult)
+	if !got.IsTruncated || got.StopReason != StopReasonByteBudget || got.BytesRead > 1024+objectReaderBlockSize {
+		t.Errorf("truncated = %v reason = %q read = %d", got.IsTruncated, got.StopReason, got.BytesRead)
+	}
+}
+
+func TestListArchive_TarByteBudget(t *testing.T) {
+	// Each header read fetches a block, and the entries are larger than a
+	// block, so every header costs a separate GET.
+	var files []archiveFile
+	for i := range 8 {
+		files = append(files, archiveFile{fmt.Sprintf("part-%d.bin", i), bytes.Repeat([]byte{'x'}, objectReaderBlockSize+1024)})
+	}
+	mock := newContentMock(map[string][]byte{"site.tar": tarBytes(t, files...)})
+	toolkit := NewToolkit(mock, WithMaxGetSize(2*objectReaderBlockSize))
+
+	result, out, _ := toolkit.handleListArchive(context.Background(), nil, ListArchiveInput{Bucket: "bucket", Key: "site.tar"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	got := out.(*ListArchiveResult)
+	if !got.IsTruncated || got.StopReason != StopReasonByteBudget || got.BytesRead > 2*objectReaderBlockSize {
+		t.Errorf("truncated = %v reason = %q read = %d", got.IsTruncated, got.StopReason, got.BytesRead)
+	}
+	if got.Count == 0 || got.Count == len(files) {
+		t.Errorf("count = %d, want some but not all of %d entries", got.Count, len(files))
+	}
+}
+
+func TestListArchive_ZipByteBudget(t *testing.T) {
+	mock := newContentMock(map[string][]byte{"app.zip": zipBytes(t, bigArchiveFile, archiveFile{"after.txt", []byte("late")})})
+	toolkit := NewToolkit(mock, WithMaxGetSize(64))
+
+	result, out, _ := toolkit.handleListArchive(context.Background(), nil, ListArchiveInput{Bucket: "bucket", Key: "app.zip"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	got := out.(*ListArchiveResult)
+	if !got.IsTruncated || got.StopReason != StopReasonByteBudget || got.BytesRead > 64 {
+		t.Errorf("truncated = %v reason = %q read = %d", got.IsTruncated, got.StopReason, got.BytesRead)
+	}
+}
+
+func TestGetArchiveEntry(t *testing.T) {
+	binary := []byte{0x89, 'P', 'N', 'G', 0x00, 0x01}
+	files := []archiveFile{
+		{"docs/", nil},
+		{"docs/README.md", []byte("# Hello\n")},
+		{"img/logo.png", binary},
+		bigArchiveFile,
+	}
+	tarball := tarBytes(t, files...)
+	mock := newContentMock(map[string][]byte{
+		"app.zip":     zipBytes(t, files...),
+		"site.tar":    tarball,
+		"site.tar.gz": gzipBytes(t, string(tarball)),
+	})
+	toolkit := NewToolkit(mock, WithMaxGetSize(64*1024))
+
+	get := func(input GetArchiveEntryInput) *GetArchiveEntryResult {
+		t.Helper()
+		input.Bucket = "bucket"
+		result, out, _ := toolkit.handleGetArchiveEntry(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error for %s %s: %v", input.Key, input.Entry, result.Content)
+		}
+		return out.(*GetArchiveEntryResult)
+	}
+
+	for _, key := range []string{"app.zip", "site.tar", "site.tar.gz"} {
+		got := get(GetArchiveEntryInput{Key: key, Entry: "docs/README.md"})
+		if got.Content != "# Hello\n" || got.IsBase64 || got.Size != 8 || got.LastModified != "2024-05-01T12:00:00Z" {
+			t.Errorf("%s: text entry = %+v", key, got)
+		}
+		got = get(GetArchiveEntryInput{Key: key, Entry: "img/logo.png"})
+		if !got.IsBase64 || got.Content != base64.StdEncoding.EncodeToString(binary) || got.ContentType != "image/png" {
+			t.Errorf("%s: binary entry = %+v", key, got)
+		}
+	}
+
+	for _, tc := range []struct {
+		input GetArchiveEntryInput
+		want  string
+	}{
+		{GetArchiveEntryInput{Key: "app.zip"}, "entry parameter is required"},
+		{GetArchiveEntryInput{Key: "app.zip", Entry: "nope.txt"}, "not found"},
+		{GetArchiveEntryInput{Key: "site.tar", Entry: "docs/"}, "is a directory"},
+		{GetArchiveEntryInput{Key: "app.zip", Entry: "data/big.bin"}, "exceeds limit"},
+		{GetArchiveEntryInput{Key: "site.tar.gz", Entry: "data/big.bin"}, "exceeds limit"},
+	} {
+		tc.input.Bucket = "bucket"
+		result, _, _ := toolkit.handleGetArchiveEntry(context.Background(), nil, tc.input)
+		if !result.IsError || !strings.Contains(resultText(result), tc.want) {
+			t.Errorf("%s %q: got %q, want an error containing %q", tc.input.Key, tc.input.Entry, resultText(result), tc.want)
+		}
+	}
+}
+
+func TestObjectReader(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), objectReaderBlockSize/5)
+	mock := newContentMock(map[string][]byte{"obj": data})
+	requests := 0
+	mock.GetObjectRangeFunc = func(_ context.Context, _, _ string, offset, length int64) (*client.ObjectContent, error) {
+		requests++
+		end := min(offset+length, int64(len(data)))
+		return &client.ObjectContent{Body: data[offset:end], Size: int64(len(data))}, nil
+	}
+	r := newObjectReader(context.Background(), mock, "bucket", "obj", int64(len(data)))
+
+	p := make([]byte, 10)
+	for _, off := range []int64{5, 20, 100} {
+		if n, err := r.ReadAt(p, off); n != 10 || err != nil || !bytes.Equal(p, data[off:off+10]) {
+			t.Fatalf("ReadAt(%d) = %d, %v, %q", off, n, err, p)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("small reads in one block made %d requests, want 1", requests)
+	}
+
+	if n, err := r.ReadAt(p, int64(len(data))-4); n != 4 || err != io.EOF {
+		t.Errorf("ReadAt at the end = %d, %v", n, err)
+	}
+
+	big := make([]byte, objectReaderBlockSize+1)
+	if n, err := r.ReadAt(big, 1); n != len(big) || err != nil || !bytes.Equal(big, data[1:len(big)+1]) {
+		t.Errorf("large ReadAt = %d, %v", n, err)
+	}
+
+	if _, err := r.Seek(-3, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	rest := make([]byte, 10)
+	if n, _ := r.Read(rest); n != 3 || string(rest[:n]) != "789" {
+		t.Errorf("Read after Seek = %q", rest[:n])
+	}
+	if n, err := r.Read(rest); n != 0 || err != io.EOF {
+		t.Errorf("Read at the end = %d, %v", n, err)
+	}
+}
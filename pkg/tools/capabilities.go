@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// toolFeatures maps tools to the optional S3 feature they need.
+var toolFeatures = map[ToolName]string{
+	ToolPresignURL: client.FeaturePresign,
+}
+
+// requireFeature returns an error wrapping ErrNotSupported when the
+// connection behind c does not support feature. The first call on a
+// connection probes it through CapabilityReporter, which caches the result
+// on the client; clients that cannot probe fall back to their provider
+// preset.
+func (t *Toolkit) requireFeature(ctx context.Context, c S3Client, feature string) error {
+	supported := !slices.Contains(unsupportedFeatures(c), feature)
+	if reporter, ok := clientAs[CapabilityReporter](c); ok {
+		_, probed := reporter.CachedCapabilities()
+		supported = reporter.Capabilities(ctx).Supports(feature)
+		if !probed {
+			t.refreshFeatureTools()
+		}
+	}
+	if supported {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is not supported by connection %s", ErrNotSupported, feature, c.ConnectionName())
+}
+
+// probeCapabilities runs the capability probe of each client concurrently,
+// caching the results on the clients. Clients that cannot probe are skipped.
+func (t *Toolkit) probeCapabilities(ctx context.Context, clients []S3Client) {
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		reporter, ok := clientAs[CapabilityReporter](c)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reporter.Capabilities(ctx)
+		}()
+	}
+	wg.Wait()
+	t.refreshFeatureTools()
+}
+
+// unsupportedConnections returns the connections known not to support
+// feature, sorted. It never creates clients or probes: a connection counts
+// when its client exists and either a cached probe or its provider preset
+// rules the feature out.
+func (t *Toolkit) unsupportedConnections(feature string) []string {
+	initialized, canCheck := t.manager.(interface{ IsClientInitialized(name string) bool })
+
+	var names []string
+	for _, name := range t.ListConnections() {
+		if t.manager != nil && (!canCheck || !initialized.IsClientInitialized(name)) {
+			continue
+		}
+		c, err := t.GetClient(name)
+		if err == nil && slices.Contains(unsupportedFeatures(c), feature) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// capabilityNote returns a sentence naming the connections that do not
+// support the feature a tool needs, or an empty string.
+func (t *Toolkit) capabilityNote(name ToolName) string {
+	feature, ok := toolFeatures[name]
+	if !ok {
+		return ""
+	}
+	unsupported := t.unsupportedConnections(feature)
+	if len(unsupported) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" Not supported by connections: %s.", strings.Join(unsupported, ", "))
+}
+
+// featureTool records the registration of a tool that needs an optional
+// feature, so it can be registered again when its description changes.
+type featureTool struct {
+	server      *mcp.Server
+	cfg         *toolConfig
+	description string
+}
+
+// trackFeatureTool records the registration of name on server when the tool
+// needs an optional feature.
+func (t *Toolkit) trackFeatureTool(server *mcp.Server, name ToolName, cfg *toolConfig) {
+	if _, ok := toolFeatures[name]; !ok {
+		return
+	}
+	t.featureToolsMu.Lock()
+	defer t.featureToolsMu.Unlock()
+	t.featureTools[name] = &featureTool{server: server, cfg: cfg, description: t.getDescription(name, cfg)}
+}
+
+// refreshFeatureTools registers again the tools whose description changed
+// because a capability probe found connections lacking their feature. The
+// MCP server then sends notifications/tools/list_changed; tools whose
+// description is unchanged are left alone.
+func (t *Toolkit) refreshFeatureTools() {
+	t.featureToolsMu.Lock()
+	defer t.featureToolsMu.Unlock()
+	for name, tool := range t.featureTools {
+		description := t.getDescription(name, tool.cfg)
+		if description == tool.description {
+			continue
+		}
+		tool.description = description
+		t.dispatchToolRegistration(tool.server, name, tool.cfg)
+	}
+}
@@ -0,0 +1,248 @@
+package tools
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	"github.com/aws/smithy-go"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// Error codes reported in classified error results.
+const (
+	ErrorCodeNotFound            = "not_found"
+	ErrorCodeAccessDenied        = "access_denied"
+	ErrorCodeThrottled           = "throttled"
+	ErrorCodePreconditionFailed  = "precondition_failed"
+	ErrorCodeInvalidRequest      = "invalid_request"
+	ErrorCodeEndpointUnreachable = "endpoint_unreachable"
+	ErrorCodeSizeLimitExceeded   = "size_limit_exceeded"
+	ErrorCodeReadOnly            = "read_only"
+	ErrorCodeNotSupported        = "not_supported"
+)
+
+// errorClass describes how an error code is reported.
+type errorClass struct {
+	sentinel  error
+	retryable bool
+	hint      string
+}
+
+// errorClasses holds the sentinel, retry advice, and remediation hint for
+// each error code.
+var errorClasses = map[string]errorClass{
+	ErrorCodeNotFound: {
+		sentinel: ErrNotFound,
+		hint:     "Check the bucket and key; use s3_list_objects to find existing keys.",
+	},
+	ErrorCodeAccessDenied: {
+		sentinel: ErrAccessDenied,
+		hint:     "The connection's credentials are not allowed to do this or have expired; check its policy or use another connection.",
+	},
+	ErrorCodeThrottled: {
+		sentinel:  ErrThrottled,
+		retryable: true,
+		hint:      "The service is rate limiting requests; wait before retrying and make fewer concurrent calls.",
+	},
+	ErrorCodePreconditionFailed: {
+		sentinel: ErrPreconditionFailed,
+		hint:     "The object changed since it was read; read it again before retrying.",
+	},
+	ErrorCodeInvalidRequest: {
+		sentinel: ErrInvalidParameter,
+		hint:     "The service rejected the request; check the bucket name, key, and other parameters.",
+	},
+	ErrorCodeEndpointUnreachable: {
+		sentinel:  ErrEndpointUnreachable,
+		retryable: true,
+		hint:      "The endpoint could not be reached or failed; retry later or use another connection.",
+	},
+	ErrorCodeSizeLimitExceeded: {
+		sentinel: ErrSizeLimitExceeded,
+		hint:     "The object is larger than the connection's size limit; use s3_presign_url to transfer it directly.",
+	},
+	ErrorCodeReadOnly: {
+		sentinel: ErrReadOnly,
+		hint:     "The server or connection is read-only; use a writable connection.",
+	},
+	ErrorCodeNotSupported: {
+		sentinel: ErrNotSupported,
+		hint:     "The connection's service does not support this feature; use s3_list_connections to find a connection that does.",
+	},
+}
+
+// guardrailCodes maps the sentinels of errors raised by the server's own
+// checks, before any request is sent, to error codes, with a hint that
+// replaces the code's default hint when set.
+var guardrailCodes = []struct {
+	sentinel error
+	code     string
+	hint     string
+}{
+	{ErrAccessDenied, ErrorCodeAccessDenied,
+		"The connection's allowed buckets and prefixes do not include this path; use s3_list_connections to see which connection may serve it."},
+	{ErrSizeLimitExceeded, ErrorCodeSizeLimitExceeded, ""},
+	{ErrReadOnly, ErrorCodeReadOnly, ""},
+	{ErrNotSupported, ErrorCodeNotSupported, ""},
+}
+
+// s3ErrorCodes maps S3 error codes to error codes.
+var s3ErrorCodes = map[string]string{
+	"NoSuchKey":                  ErrorCodeNotFound,
+	"NoSuchBucket":               ErrorCodeNotFound,
+	"NoSuchVersion":              ErrorCodeNotFound,
+	"NoSuchUpload":               ErrorCodeNotFound,
+	"NotFound":                   ErrorCodeNotFound,
+	"AccessDenied":               ErrorCodeAccessDenied,
+	"AllAccessDisabled":          ErrorCodeAccessDenied,
+	"AccountProblem":             ErrorCodeAccessDenied,
+	"ExpiredToken":               ErrorCodeAccessDenied,
+	"Forbidden":                  ErrorCodeAccessDenied,
+	"InvalidAccessKeyId":         ErrorCodeAccessDenied,
+	"InvalidToken":               ErrorCodeAccessDenied,
+	"SignatureDoesNotMatch":      ErrorCodeAccessDenied,
+	"SlowDown":                   ErrorCodeThrottled,
+	"Throttling":                 ErrorCodeThrottled,
+	"ThrottlingException":        ErrorCodeThrottled,
+	"RequestLimitExceeded":       ErrorCodeThrottled,
+	"RequestThrottled":           ErrorCodeThrottled,
+	"TooManyRequests":            ErrorCodeThrottled,
+	"PreconditionFailed":         ErrorCodePreconditionFailed,
+	"ConditionalRequestConflict": ErrorCodePreconditionFailed,
+	"BadDigest":                  ErrorCodeInvalidRequest,
+	"EntityTooLarge":             ErrorCodeInvalidRequest,
+	"EntityTooSmall":             ErrorCodeInvalidRequest,
+	"InvalidArgument":            ErrorCodeInvalidRequest,
+	"InvalidBucketName":          ErrorCodeInvalidRequest,
+	"InvalidObjectState":         ErrorCodeInvalidRequest,
+	"InvalidRange":               ErrorCodeInvalidRequest,
+	"InvalidRequest":             ErrorCodeInvalidRequest,
+	"KeyTooLongError":            ErrorCodeInvalidRequest,
+	"MalformedXML":               ErrorCodeInvalidRequest,
+	"MethodNotAllowed":           ErrorCodeInvalidRequest,
+	"NotImplemented":             ErrorCodeInvalidRequest,
+}
+
+// ClassifiedError is an S3 failure mapped to a stable error code. It wraps
+// both the original error and the sentinel for its code, such as ErrNotFound,
+// so errors.Is matches either.
+type ClassifiedError struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Retryable  bool   `json:"retryable"`
+	Hint       string `json:"hint,omitempty"`
+	S3Code     string `json:"s3_code,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+
+	err      error
+	sentinel error
+}
+
+// Error returns the original error message.
+func (e *ClassifiedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the sentinel for the error code and the original error.
+func (e *ClassifiedError) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+// ClassifyError maps an S3 error to an error code. S3 error codes are
+// matched first, then the HTTP status; errors that never reached the service,
+// timeouts, and 5xx responses are endpoint_unreachable. Errors from the
+// connection's guardrails, such as ErrAccessDenied and ErrSizeLimitExceeded,
+// are classified by their sentinel. It returns nil for nil errors and errors
+// it cannot classify.
+func ClassifyError(err error) *ClassifiedError {
+	if err == nil {
+		return nil
+	}
+
+	classified := &ClassifiedError{Message: err.Error(), err: err}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		classified.S3Code = apiErr.ErrorCode()
+		if msg := apiErr.ErrorMessage(); msg != "" {
+			classified.Message = msg
+		}
+	}
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) {
+		classified.StatusCode = respErr.HTTPStatusCode()
+	}
+
+	code, ok := s3ErrorCodes[classified.S3Code]
+	if !ok {
+		code = statusErrorCode(classified.StatusCode)
+	}
+	if code == "" && client.IsUnavailable(err) {
+		code = ErrorCodeEndpointUnreachable
+	}
+	hint := ""
+	if code == "" {
+		code, hint = guardrailCode(err)
+	}
+	if code == "" {
+		return nil
+	}
+
+	class := errorClasses[code]
+	classified.Code = code
+	classified.Retryable = class.retryable
+	classified.Hint = cmp.Or(hint, class.hint)
+	classified.sentinel = class.sentinel
+	return classified
+}
+
+// guardrailCode returns the error code and hint of a guardrail error, or
+// empty strings.
+func guardrailCode(err error) (code, hint string) {
+	for _, g := range guardrailCodes {
+		if errors.Is(err, g.sentinel) {
+			return g.code, g.hint
+		}
+	}
+	return "", ""
+}
+
+// statusErrorCode maps an HTTP status to an error code, or returns an empty
+// string.
+func statusErrorCode(status int) string {
+	switch {
+	case status == 404:
+		return ErrorCodeNotFound
+	case status == 401 || status == 403:
+		return ErrorCodeAccessDenied
+	case status == 429:
+		return ErrorCodeThrottled
+	case status == 412:
+		return ErrorCodePreconditionFailed
+	case status >= 500:
+		return ErrorCodeEndpointUnreachable
+	case status >= 400:
+		return ErrorCodeInvalidRequest
+	default:
+		return ""
+	}
+}
+
+// S3ErrorResult creates an error result for a failed S3 operation, described
+// by op as in "failed to <op>". When the error can be classified, the
+// result's structured content holds the classification under "error".
+func S3ErrorResult(op string, err error) *mcp.CallToolResult {
+	return ErrorResultFor(fmt.Errorf("failed to %s: %w", op, err))
+}
+
+// ErrorResultFor creates an error result for err, setting its structured
+// content to {"error": classification} when ClassifyError recognizes it.
+func ErrorResultFor(err error) *mcp.CallToolResult {
+	result := ErrorResult(err.Error())
+	if classified := ClassifyError(err); classified != nil {
+		result.StructuredContent = map[string]any{"error": classified}
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// responseError builds an SDK-style error with an HTTP status and S3 code.
+func responseError(status int, code string) error {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      &smithy.GenericAPIError{Code: code, Message: code + " message"},
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      string
+		sentinel  error
+		retryable bool
+	}{
+		{"no such key", responseError(404, "NoSuchKey"), ErrorCodeNotFound, ErrNotFound, false},
+		{"head not found", responseError(404, ""), ErrorCodeNotFound, ErrNotFound, false},
+		{"access denied", responseError(403, "AccessDenied"), ErrorCodeAccessDenied, ErrAccessDenied, false},
+		{"slow down", responseError(503, "SlowDown"), ErrorCodeThrottled, ErrThrottled, true},
+		{"too many requests", responseError(429, ""), ErrorCodeThrottled, ErrThrottled, true},
+		{"precondition", responseError(412, "PreconditionFailed"), ErrorCodePreconditionFailed, ErrPreconditionFailed, false},
+		{"invalid range", responseError(416, "InvalidRange"), ErrorCodeInvalidRequest, ErrInvalidParameter, false},
+		{"internal error", responseError(500, "InternalError"), ErrorCodeEndpointUnreachable, ErrEndpointUnreachable, true},
+		{"circuit open", fmt.Errorf("failed to list buckets: %w", client.ErrCircuitOpen), ErrorCodeEndpointUnreachable, ErrEndpointUnreachable, true},
+		{"timeout", context.DeadlineExceeded, ErrorCodeEndpointUnreachable, ErrEndpointUnreachable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classified := ClassifyError(fmt.Errorf("failed to get object: %w", tt.err))
+			if classified == nil {
+				t.Fatal("expected the error to be classified")
+			}
+			if classified.Code != tt.code || classified.Retryable != tt.retryable {
+				t.Errorf("code = %s retryable = %v, want %s %v", classified.Code, classified.Retryable, tt.code, tt.retryable)
+			}
+			if classified.Hint == "" {
+				t.Error("expected a remediation hint")
+			}
+			if !errors.Is(classified, tt.sentinel) || !errors.Is(classified, tt.err) {
+				t.Error("classified error should wrap both the sentinel and the original error")
+			}
+		})
+	}
+
+	if ClassifyError(nil) != nil || ClassifyError(errors.New("boom")) != nil {
+		t.Error("nil and unrecognized errors should not be classified")
+	}
+}
+
+func TestS3ErrorResult(t *testing.T) {
+	mock := NewMockS3Client("test")
+	mock.GetObjectMetadataFunc = func(context.Context, string, string) (*client.ObjectMetadata, error) {
+		return nil, fmt.Errorf("failed to head object: %w", responseError(404, "NotFound"))
+	}
+	toolkit := NewToolkit(mock)
+
+	result, _, _ := toolkit.handleGetObjectMetadata(context.Background(), nil, GetObjectMetadataInput{Bucket: "b", Key: "missing"})
+	if !result.IsError || len(result.Content) != 1 {
+		t.Fatalf("expected an error result with one content block, got %+v", result)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.HasPrefix(text, "Error: failed to get object metadata:") {
+		t.Errorf("text = %q", text)
+	}
+	if c := structuredError(t, result); c.Code != ErrorCodeNotFound || c.S3Code != "NotFound" || c.StatusCode != 404 {
+		t.Errorf("classification = %+v", c)
+	}
+
+	// The classification reaches clients of a typed tool unchanged.
+	session := connectToolkit(t, toolkit)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      string(ToolGetObjectMetadata),
+		Arguments: map[string]any{"bucket": "b", "key": "missing"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if c := structuredError(t, result); !result.IsError || c.Code != ErrorCodeNotFound {
+		t.Errorf("classification over MCP = %+v", c)
+	}
+
+	if plain := S3ErrorResult("list buckets", errors.New("boom")); plain.StructuredContent != nil {
+		t.Errorf("unclassified errors should have no structured content, got %v", plain.StructuredContent)
+	}
+}
+
+// structuredError returns the classification in the structured content of
+// an error result, failing the test when there is none.
+func structuredError(t *testing.T, result *mcp.CallToolResult) ClassifiedError {
+	t.Helper()
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("structured content is not JSON: %v", err)
+	}
+	var body struct {
+		Error *ClassifiedError `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.Error == nil {
+		t.Fatalf("structured content %s has no classification", data)
+	}
+	return *body.Error
+}
+
+func TestGuardrailErrorResult(t *testing.T) {
+	mock := NewMockS3Client("prod")
+	mock.config.ReadOnly = true
+	mock.config.AllowedBuckets = []string{"reports"}
+	mock.config.MaxGetSize = 4
+	mock.AddObject("reports", "big.txt", []byte("too large"), "text/plain")
+	toolkit := NewToolkit(mock)
+	ctx := context.Background()
+	noPresign := NewToolkit(&capabilityMockClient{MockS3Client: NewMockS3Client("public")})
+
+	tests := []struct {
+		name   string
+		result *mcp.CallToolResult
+		code   string
+	}{
+		{"bucket denied", first(toolkit.handleGetObjectMetadata(ctx, nil, GetObjectMetadataInput{Bucket: "secrets", Key: "k"})),
+			ErrorCodeAccessDenied},
+		{"too large", first(toolkit.handleGetObject(ctx, nil, GetObjectInput{Bucket: "reports", Key: "big.txt"})),
+			ErrorCodeSizeLimitExceeded},
+		{"read only", first(toolkit.handleDeleteObject(ctx, nil, DeleteObjectInput{Bucket: "reports", Key: "big.txt"})),
+			ErrorCodeReadOnly},
+		{"not supported", first(noPresign.handlePresignURL(ctx, nil, PresignURLInput{Bucket: "b", Key: "k"})),
+			ErrorCodeNotSupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.result.IsError {
+				t.Fatal("expected an error result")
+			}
+			if c := structuredError(t, tt.result); c.Code != tt.code || c.Hint == "" || c.S3Code != "" {
+				t.Errorf("classification = %+v, want code %s", c, tt.code)
+			}
+		})
+	}
+
+	classified := ClassifyError(fmt.Errorf("%w: bucket secrets is not allowed", ErrAccessDenied))
+	if classified == nil || !strings.Contains(classified.Hint, "allowed buckets") || !errors.Is(classified, ErrAccessDenied) {
+		t.Errorf("guardrail classification = %+v", classified)
+	}
+}
+
+// first returns the result of a tool handler call.
+func first(result *mcp.CallToolResult, _ any, _ error) *mcp.CallToolResult {
+	return result
+}
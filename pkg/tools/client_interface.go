@@ -25,6 +25,10 @@ type S3Client interface {
 	// GetObject retrieves an object's content from S3.
 	GetObject(ctx context.Context, bucket, key string) (*client.ObjectContent, error)
 
+	// GetObjectRange retrieves length bytes of an object's content starting at
+	// offset. Size in the result is the size of the whole object.
+	GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (*client.ObjectContent, error)
+
 	// GetObjectMetadata retrieves an object's metadata without downloading the content.
 	GetObjectMetadata(ctx context.Context, bucket, key string) (*client.ObjectMetadata, error)
 
@@ -47,5 +51,64 @@ type S3Client interface {
 	Close() error
 }
 
-// Ensure client.Client implements S3Client.
-var _ S3Client = (*client.Client)(nil)
+// CircuitStateReporter is implemented by clients that guard their connection
+// with a circuit breaker. The second result is false when no breaker is
+// configured. client.Client implements this interface.
+type CircuitStateReporter interface {
+	CircuitState() (client.CircuitState, bool)
+}
+
+// CredentialStateReporter is implemented by clients that can report the
+// source and expiry of their credentials without refreshing them. The second
+// result is false when the client signs requests anonymously. client.Client
+// implements this interface.
+type CredentialStateReporter interface {
+	CredentialState() (client.CredentialState, bool)
+}
+
+// CapabilityReporter is implemented by clients that can probe which optional
+// S3 features their connection supports. Capabilities probes on first use and
+// caches the result; CachedCapabilities never probes and returns false until
+// a probe has run. client.Client implements this interface.
+type CapabilityReporter interface {
+	Capabilities(ctx context.Context) client.Capabilities
+	CachedCapabilities() (client.Capabilities, bool)
+}
+
+// ClientUnwrapper is implemented by clients that wrap another S3Client, such as
+// the concurrency limiter used by multiserver.Manager. It lets the toolkit
+// find optional interfaces implemented by the underlying client.
+type ClientUnwrapper interface {
+	Unwrap() S3Client
+}
+
+// ClientHolder is implemented by clients that their owner may close while a
+// tool call is still using them, such as clients cached by multiserver.Manager
+// with eviction enabled. Hold keeps the client open until release is called.
+type ClientHolder interface {
+	Hold() (release func())
+}
+
+// clientAs returns the first client in the Unwrap chain of c that implements T.
+func clientAs[T any](c S3Client) (T, bool) {
+	for c != nil {
+		if v, ok := c.(T); ok {
+			return v, true
+		}
+		u, ok := c.(ClientUnwrapper)
+		if !ok {
+			break
+		}
+		c = u.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+// Ensure client.Client implements S3Client and the optional reporter interfaces.
+var (
+	_ S3Client                = (*client.Client)(nil)
+	_ CircuitStateReporter    = (*client.Client)(nil)
+	_ CredentialStateReporter = (*client.Client)(nil)
+	_ CapabilityReporter      = (*client.Client)(nil)
+)
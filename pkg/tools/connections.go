@@ -2,6 +2,9 @@ package tools
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -15,19 +18,165 @@ type ListConnectionsResult struct {
 
 // ConnectionInfo represents information about an S3 connection.
 type ConnectionInfo struct {
-	Name     string `json:"name"`
-	Region   string `json:"region,omitempty"`
-	Endpoint string `json:"endpoint,omitempty"`
+	Name     string       `json:"name"`
+	Region   string       `json:"region,omitempty"`
+	Endpoint string       `json:"endpoint,omitempty"`
+	ReadOnly bool         `json:"read_only,omitempty"`
+	Circuit  *CircuitInfo `json:"circuit,omitempty"`
+
+	Credentials *CredentialInfo `json:"credentials,omitempty"`
+	Health      *HealthInfo     `json:"health,omitempty"`
+
+	// Unsupported lists optional features the connection is known not to
+	// support, from a cached capability probe or the provider preset.
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// Health statuses reported by a HealthChecker.
+const (
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// HealthStatus is the result of probing a connection.
+type HealthStatus struct {
+	Connection string
+	Status     string
+
+	// Initialized reports whether a client existed before the probe.
+	Initialized bool
+
+	Latency   time.Duration
+	CheckedAt time.Time
+
+	// LastError is the most recent probe error, kept after the connection
+	// recovers so intermittent failures stay visible.
+	LastError   string
+	LastErrorAt time.Time
+}
+
+// HealthChecker probes connections for s3_list_connections.
+// multiserver.Manager implements this interface.
+type HealthChecker interface {
+	// HealthCheck probes the named connections concurrently and returns the
+	// results in the same order. Fresh cached results may be reused.
+	HealthCheck(ctx context.Context, connections ...string) []HealthStatus
+
+	// CachedHealth returns the last result for a connection without probing.
+	CachedHealth(connection string) (HealthStatus, bool)
+}
+
+// HealthInfo reports the result of a connection's last health check.
+type HealthInfo struct {
+	Status      string `json:"status"`
+	Initialized bool   `json:"initialized"`
+	LatencyMs   int64  `json:"latency_ms"`
+	CheckedAt   string `json:"checked_at,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+	LastErrorAt string `json:"last_error_at,omitempty"`
+}
+
+// healthInfo converts a HealthStatus for tool output.
+func healthInfo(h HealthStatus) *HealthInfo {
+	info := &HealthInfo{
+		Status:      h.Status,
+		Initialized: h.Initialized,
+		LatencyMs:   h.Latency.Milliseconds(),
+		LastError:   h.LastError,
+	}
+	if !h.CheckedAt.IsZero() {
+		info.CheckedAt = h.CheckedAt.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	if !h.LastErrorAt.IsZero() {
+		info.LastErrorAt = h.LastErrorAt.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return info
+}
+
+// CredentialInfo reports the credentials a connection last used. Fields are
+// empty until the connection has made a signed request.
+type CredentialInfo struct {
+	Source    string `json:"source,omitempty"`
+	Temporary bool   `json:"temporary"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Expired   bool   `json:"expired,omitempty"`
+}
+
+// CircuitInfo reports the state of a connection's circuit breaker.
+type CircuitInfo struct {
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	RetryAt             string `json:"retry_at,omitempty"`
+}
+
+// credentialInfo returns the credential state of c, or nil when the client
+// does not report one.
+func credentialInfo(c S3Client) *CredentialInfo {
+	reporter, ok := clientAs[CredentialStateReporter](c)
+	if !ok {
+		return nil
+	}
+	state, ok := reporter.CredentialState()
+	if !ok {
+		return nil
+	}
+	info := &CredentialInfo{
+		Source:    state.Source,
+		Temporary: state.CanExpire,
+	}
+	if state.CanExpire && !state.Expires.IsZero() {
+		info.ExpiresAt = state.Expires.UTC().Format("2006-01-02T15:04:05Z")
+		info.Expired = !time.Now().Before(state.Expires)
+	}
+	return info
+}
+
+// circuitInfo returns the circuit breaker state of c, or nil when the
+// client has no circuit breaker.
+func circuitInfo(c S3Client) *CircuitInfo {
+	reporter, ok := clientAs[CircuitStateReporter](c)
+	if !ok {
+		return nil
+	}
+	state, ok := reporter.CircuitState()
+	if !ok {
+		return nil
+	}
+	info := &CircuitInfo{
+		State:               state.State,
+		ConsecutiveFailures: state.ConsecutiveFailures,
+	}
+	if !state.RetryAt.IsZero() {
+		info.RetryAt = state.RetryAt.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return info
+}
+
+// unsupportedFeatures returns the features c is known not to support without
+// probing: the cached probe result when there is one, or else the features
+// its provider preset rules out.
+func unsupportedFeatures(c S3Client) []string {
+	if reporter, ok := clientAs[CapabilityReporter](c); ok {
+		if caps, probed := reporter.CachedCapabilities(); probed {
+			return caps.Unsupported()
+		}
+	}
+	if cfg := c.Config(); cfg != nil {
+		if preset, ok := cfg.Preset(); ok {
+			return preset.Unsupported
+		}
+	}
+	return nil
 }
 
 // registerListConnectionsTool registers the s3_list_connections tool.
 func (t *Toolkit) registerListConnectionsTool(server *mcp.Server, cfg *toolConfig) {
 	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
-		_, ok := input.(ListConnectionsInput)
+		listInput, ok := input.(ListConnectionsInput)
 		if !ok {
 			return ErrorResult("internal error: invalid input type"), nil, nil
 		}
-		return t.handleListConnections(ctx, req)
+		return t.handleListConnections(ctx, req, listInput)
 	}
 
 	wrappedHandler := t.wrapHandler(ToolListConnections, baseHandler, cfg)
@@ -39,17 +188,18 @@ func (t *Toolkit) registerListConnectionsTool(server *mcp.Server, cfg *toolConfi
 		Annotations:  t.getAnnotations(ToolListConnections, cfg),
 		Icons:        t.getIcons(ToolListConnections, cfg),
 		OutputSchema: t.getOutputSchema(ToolListConnections, cfg),
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListConnectionsInput) (*mcp.CallToolResult, *ListConnectionsResult, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListConnectionsInput) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*ListConnectionsResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[ListConnectionsResult](out), err
 	})
 }
 
 // handleListConnections handles the s3_list_connections tool request.
-func (t *Toolkit) handleListConnections(_ context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, any, error) {
+// Connections whose client has not been created yet are neither probed nor
+// created unless input.Probe is set; they report their cached health only.
+func (t *Toolkit) handleListConnections(
+	ctx context.Context, _ *mcp.CallToolRequest, input ListConnectionsInput,
+) (*mcp.CallToolResult, any, error) {
 	connections := t.ListConnections()
 
 	result := ListConnectionsResult{
@@ -58,9 +208,37 @@ func (t *Toolkit) handleListConnections(_ context.Context, _ *mcp.CallToolReques
 		Count:             len(connections),
 	}
 
+	// Probe before GetClient so the initialized flag reflects prior use.
+	live := make(map[string]bool, len(connections))
+	for _, name := range connections {
+		live[name] = input.Probe || t.isClientInitialized(name)
+	}
+	health := t.connectionHealth(ctx, connections, live)
+
+	clients := make(map[string]S3Client, len(connections))
+	for _, name := range connections {
+		if live[name] {
+			if client, err := t.GetClient(name); err == nil {
+				clients[name] = client
+			}
+		}
+	}
+	if input.Probe {
+		t.probeCapabilities(ctx, slices.Collect(maps.Values(clients)))
+	}
+
 	for _, name := range connections {
-		client, err := t.GetClient(name)
-		if err != nil {
+		if !live[name] {
+			result.Connections = append(result.Connections, ConnectionInfo{Name: name, Health: health[name]})
+			continue
+		}
+
+		client, ok := clients[name]
+		if !ok {
+			// Report broken connections when a health checker can say why.
+			if health[name] != nil {
+				result.Connections = append(result.Connections, ConnectionInfo{Name: name, Health: health[name]})
+			}
 			continue
 		}
 
@@ -73,6 +251,11 @@ func (t *Toolkit) handleListConnections(_ context.Context, _ *mcp.CallToolReques
 		if cfg.Endpoint != "" {
 			info.Endpoint = cfg.Endpoint
 		}
+		info.ReadOnly = t.guardFor(client).readOnly
+		info.Circuit = circuitInfo(client)
+		info.Credentials = credentialInfo(client)
+		info.Health = health[name]
+		info.Unsupported = unsupportedFeatures(client)
 
 		result.Connections = append(result.Connections, info)
 	}
@@ -83,3 +266,36 @@ func (t *Toolkit) handleListConnections(_ context.Context, _ *mcp.CallToolReques
 	}
 	return jsonResult, &result, nil
 }
+
+// isClientInitialized reports whether the named connection's client exists.
+// Without a ConnectionManager that can tell, every connection counts as
+// initialized.
+func (t *Toolkit) isClientInitialized(name string) bool {
+	initialized, ok := t.manager.(interface{ IsClientInitialized(name string) bool })
+	return !ok || initialized.IsClientInitialized(name)
+}
+
+// connectionHealth returns the health of each connection: probed
+// concurrently for the live ones and cached for the rest. It is empty
+// without a health checker.
+func (t *Toolkit) connectionHealth(ctx context.Context, connections []string, live map[string]bool) map[string]*HealthInfo {
+	health := make(map[string]*HealthInfo, len(connections))
+	if t.healthChecker == nil {
+		return health
+	}
+
+	var probe []string
+	for _, name := range connections {
+		if live[name] {
+			probe = append(probe, name)
+		} else if cached, ok := t.healthChecker.CachedHealth(name); ok {
+			health[name] = healthInfo(cached)
+		}
+	}
+	if len(probe) > 0 {
+		for _, status := range t.healthChecker.HealthCheck(ctx, probe...) {
+			health[status.Connection] = healthInfo(status)
+		}
+	}
+	return health
+}
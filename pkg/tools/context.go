@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,6 +13,9 @@ type contextKey string
 const (
 	// ToolContextKey is the context key for ToolContext.
 	toolContextKey contextKey = "tool_context"
+
+	// servedByKey is the context key for the connection that served a call.
+	servedByKey contextKey = "served_by"
 )
 
 // ToolContext provides contextual information and state for tool execution.
@@ -26,12 +30,25 @@ type ToolContext struct {
 	// RequestID is a unique identifier for this request.
 	RequestID string
 
+	// SessionID identifies the MCP session that issued the request.
+	// Empty when the request did not arrive through a server session.
+	SessionID string
+
+	// Principal identifies the authenticated caller (for example the OAuth
+	// user ID). Empty when the transport does not authenticate callers.
+	Principal string
+
 	// StartTime is when the tool execution started.
 	StartTime time.Time
 
 	// values stores arbitrary key-value pairs for middleware communication.
 	values map[string]any
 	mu     sync.RWMutex
+
+	// bytesRead and bytesWritten count object payload bytes transferred by
+	// the tool handler. Quota and metrics middleware read them in After.
+	bytesRead    atomic.Int64
+	bytesWritten atomic.Int64
 }
 
 // NewToolContext creates a new ToolContext with the given tool and connection names.
@@ -49,6 +66,26 @@ func (tc *ToolContext) Duration() time.Duration {
 	return time.Since(tc.StartTime)
 }
 
+// AddBytesRead records n bytes of object content read from S3.
+func (tc *ToolContext) AddBytesRead(n int64) {
+	tc.bytesRead.Add(n)
+}
+
+// BytesRead returns the number of object content bytes read from S3.
+func (tc *ToolContext) BytesRead() int64 {
+	return tc.bytesRead.Load()
+}
+
+// AddBytesWritten records n bytes of object content written to S3.
+func (tc *ToolContext) AddBytesWritten(n int64) {
+	tc.bytesWritten.Add(n)
+}
+
+// BytesWritten returns the number of object content bytes written to S3.
+func (tc *ToolContext) BytesWritten() int64 {
+	return tc.bytesWritten.Load()
+}
+
 // Set stores a value in the context with the given key.
 func (tc *ToolContext) Set(key string, value any) {
 	tc.mu.Lock()
@@ -118,8 +155,13 @@ func (tc *ToolContext) Clone() *ToolContext {
 		ToolName:       tc.ToolName,
 		ConnectionName: tc.ConnectionName,
 		RequestID:      tc.RequestID,
+		SessionID:      tc.SessionID,
+		Principal:      tc.Principal,
+		StartTime:      tc.StartTime,
 		values:         make(map[string]any, len(tc.values)),
 	}
+	newTC.bytesRead.Store(tc.bytesRead.Load())
+	newTC.bytesWritten.Store(tc.bytesWritten.Load())
 
 	for k, v := range tc.values {
 		newTC.values[k] = v
@@ -142,3 +184,45 @@ func GetToolContext(ctx context.Context) *ToolContext {
 	}
 	return nil
 }
+
+// recordBytesRead adds n to the read counter of the ToolContext in ctx, if any.
+func recordBytesRead(ctx context.Context, n int64) {
+	if tc := GetToolContext(ctx); tc != nil {
+		tc.AddBytesRead(n)
+	}
+}
+
+// recordBytesWritten adds n to the write counter of the ToolContext in ctx, if any.
+func recordBytesWritten(ctx context.Context, n int64) {
+	if tc := GetToolContext(ctx); tc != nil {
+		tc.AddBytesWritten(n)
+	}
+}
+
+// servedBy holds the connection recorded by RecordServedBy.
+type servedBy struct {
+	mu         sync.Mutex
+	connection string
+}
+
+// withServedBy returns a context that records the connection serving calls
+// made with it, and a function that returns the last connection recorded.
+func withServedBy(ctx context.Context) (context.Context, func() string) {
+	s := &servedBy{}
+	return context.WithValue(ctx, servedByKey, s), func() string {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.connection
+	}
+}
+
+// RecordServedBy records that connection served a call made with ctx.
+// Clients that route calls between connections, such as multiserver
+// connection groups, call it so tool results can report the connection used.
+func RecordServedBy(ctx context.Context, connection string) {
+	if s, ok := ctx.Value(servedByKey).(*servedBy); ok {
+		s.mu.Lock()
+		s.connection = connection
+		s.mu.Unlock()
+	}
+}
@@ -17,6 +17,7 @@ type CopyObjectResult struct {
 	ETag         string `json:"etag,omitempty"`
 	LastModified string `json:"last_modified,omitempty"`
 	VersionID    string `json:"version_id,omitempty"`
+	ServedBy     string `json:"served_by,omitempty"`
 }
 
 // registerCopyObjectTool registers the s3_copy_object tool.
@@ -38,12 +39,9 @@ func (t *Toolkit) registerCopyObjectTool(server *mcp.Server, cfg *toolConfig) {
 		Annotations:  t.getAnnotations(ToolCopyObject, cfg),
 		Icons:        t.getIcons(ToolCopyObject, cfg),
 		OutputSchema: t.getOutputSchema(ToolCopyObject, cfg),
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input CopyObjectInput) (*mcp.CallToolResult, *CopyObjectResult, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input CopyObjectInput) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*CopyObjectResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[CopyObjectResult](out), err
 	})
 }
 
@@ -51,7 +49,7 @@ func (t *Toolkit) registerCopyObjectTool(server *mcp.Server, cfg *toolConfig) {
 func (t *Toolkit) handleCopyObject(ctx context.Context, _ *mcp.CallToolRequest, input CopyObjectInput) (*mcp.CallToolResult, any, error) {
 	// Check read-only mode
 	if t.readOnly {
-		return ErrorResult(ErrReadOnly.Error()), nil, nil
+		return ErrorResultFor(ErrReadOnly), nil, nil
 	}
 
 	// Validate required parameters
@@ -69,12 +67,26 @@ func (t *Toolkit) handleCopyObject(ctx context.Context, _ *mcp.CallToolRequest,
 	}
 
 	// Get client
-	s3Client, err := t.GetClient(input.Connection)
+	s3Client, release, err := t.holdClient(input.Connection)
 	if err != nil {
 		return ErrorResult(err.Error()), nil, nil
 	}
+	defer release()
+
+	// Check the connection's guardrails for both ends of the copy
+	guard := t.guardFor(s3Client)
+	if err := guard.checkWrite(); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+	if err := guard.checkKey(input.SourceBucket, input.SourceKey); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+	if err := guard.checkKey(input.DestBucket, input.DestKey); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
 
 	// Copy object
+	ctx, servedBy := withServedBy(ctx)
 	output, err := s3Client.CopyObject(ctx, &client.CopyObjectInput{
 		SourceBucket: input.SourceBucket,
 		SourceKey:    input.SourceKey,
@@ -83,7 +95,7 @@ func (t *Toolkit) handleCopyObject(ctx context.Context, _ *mcp.CallToolRequest,
 		Metadata:     input.Metadata,
 	})
 	if err != nil {
-		return ErrorResultf("failed to copy object: %v", err), nil, nil
+		return S3ErrorResult("copy object", err), nil, nil
 	}
 
 	// Build result
@@ -94,6 +106,7 @@ func (t *Toolkit) handleCopyObject(ctx context.Context, _ *mcp.CallToolRequest,
 		DestKey:      input.DestKey,
 		ETag:         output.ETag,
 		VersionID:    output.VersionID,
+		ServedBy:     servedBy(),
 	}
 	if !output.LastModified.IsZero() {
 		result.LastModified = output.LastModified.Format("2006-01-02T15:04:05Z")
@@ -8,9 +8,10 @@ import (
 
 // DeleteObjectResult represents the result of deleting an object.
 type DeleteObjectResult struct {
-	Bucket  string `json:"bucket"`
-	Key     string `json:"key"`
-	Deleted bool   `json:"deleted"`
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	Deleted  bool   `json:"deleted"`
+	ServedBy string `json:"served_by,omitempty"`
 }
 
 // registerDeleteObjectTool registers the s3_delete_object tool.
@@ -32,12 +33,9 @@ func (t *Toolkit) registerDeleteObjectTool(server *mcp.Server, cfg *toolConfig)
 		Annotations:  t.getAnnotations(ToolDeleteObject, cfg),
 		Icons:        t.getIcons(ToolDeleteObject, cfg),
 		OutputSchema: t.getOutputSchema(ToolDeleteObject, cfg),
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input DeleteObjectInput) (*mcp.CallToolResult, *DeleteObjectResult, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input DeleteObjectInput) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*DeleteObjectResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[DeleteObjectResult](out), err
 	})
 }
 
@@ -47,7 +45,7 @@ func (t *Toolkit) handleDeleteObject(
 ) (*mcp.CallToolResult, any, error) {
 	// Check read-only mode
 	if t.readOnly {
-		return ErrorResult(ErrReadOnly.Error()), nil, nil
+		return ErrorResultFor(ErrReadOnly), nil, nil
 	}
 
 	// Validate required parameters
@@ -59,22 +57,34 @@ func (t *Toolkit) handleDeleteObject(
 	}
 
 	// Get client
-	client, err := t.GetClient(input.Connection)
+	client, release, err := t.holdClient(input.Connection)
 	if err != nil {
 		return ErrorResult(err.Error()), nil, nil
 	}
+	defer release()
+
+	// Check the connection's guardrails
+	guard := t.guardFor(client)
+	if err := guard.checkWrite(); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+	if err := guard.checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
 
 	// Delete object
+	ctx, servedBy := withServedBy(ctx)
 	err = client.DeleteObject(ctx, input.Bucket, input.Key)
 	if err != nil {
-		return ErrorResultf("failed to delete object: %v", err), nil, nil
+		return S3ErrorResult("delete object", err), nil, nil
 	}
 
 	// Build result
 	result := DeleteObjectResult{
-		Bucket:  input.Bucket,
-		Key:     input.Key,
-		Deleted: true,
+		Bucket:   input.Bucket,
+		Key:      input.Key,
+		Deleted:  true,
+		ServedBy: servedBy(),
 	}
 
 	jsonResult, err := JSONResult(result)
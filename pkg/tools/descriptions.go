@@ -6,14 +6,55 @@ var defaultDescriptions = map[ToolName]string{
 	ToolListBuckets: "List all accessible S3 buckets. Returns bucket names and creation dates.",
 
 	ToolListConnections: "List all configured S3 connections. Returns connection names, regions, " +
-		"and endpoints (if custom endpoints are configured).",
+		"and endpoints (if custom endpoints are configured), plus health status, probe latency, " +
+		"and the last error when health checks are enabled. Unused connections are only probed " +
+		"when probe is set. Use it to find a broken connection.",
 
 	ToolListObjects: "List objects in an S3 bucket. Supports prefix filtering, delimiter for " +
-		"folder simulation, and pagination.",
+		"folder simulation, and pagination. Set sort_by to last_modified, size, or key to get " +
+		"the top max_keys objects in that order, e.g. the latest files under a prefix.",
+
+	ToolFindObjects: "Search a bucket prefix for objects matching filters: glob or regex on the key, " +
+		"size range, modified after/before, storage class, and extension. Pages through the " +
+		"listing internally up to a key budget and returns matches plus a cursor to resume the " +
+		"search. Use it instead of repeated s3_list_objects calls.",
+
+	ToolSummarizePrefix: "Summarize how much data a prefix holds and what it is made of, like du: " +
+		"total bytes and object count, broken down by storage class, extension, and immediate " +
+		"sub-prefix, with the oldest, newest, and largest objects. The walk is bounded by a key " +
+		"and time budget; is_partial reports when the budget stopped it early.",
+
+	ToolTree: "Show the folder hierarchy under a prefix as a tree, several levels at once, with " +
+		"the object count and total size below each prefix. Returns structured nodes and an " +
+		"indented text rendering. Depth, node count, and keys examined are bounded.",
+
+	ToolGrep: "Search the contents of text objects under a prefix for a literal string or regular " +
+		"expression, like grep. Gzip, zstd, and bzip2 objects are decompressed. Returns the key, line " +
+		"number, and optional context lines of each match. Objects are searched in parallel " +
+		"within per-object and total byte budgets; binary and oversized objects are skipped.",
+
+	ToolListArchive: "List the files in a zip or tar archive without extracting it. Zip archives " +
+		"are read through their central directory with ranged requests, so only a small part of " +
+		"the archive is downloaded. Tar archives, including .tar.gz, .tar.zst, and .tar.bz2, are " +
+		"streamed. Returns each entry's name, size, and modification time.",
+
+	ToolGetArchiveEntry: "Retrieve the content of one file inside a zip or tar archive without " +
+		"downloading the whole archive. For text content, returns the content directly. For " +
+		"binary content, returns base64-encoded data. Entries larger than the retrieval size " +
+		"limit are rejected.",
+
+	ToolInspectParquet: "Inspect a Parquet file without downloading it: reads the footer with ranged " +
+		"requests and returns the schema, row count, row groups, and per-column compression, " +
+		"sizes, null counts, and min/max statistics. Optionally decodes the first rows as JSON.",
+
+	ToolPreviewTable: "Preview a CSV, TSV, or other delimited file by reading only its first chunk. " +
+		"Detects the delimiter, quote character, and header row, infers each column's type " +
+		"(int, float, bool, timestamp, or string), and returns a JSON schema with sample rows. " +
+		"Gzip, zstd, and bzip2 files are decompressed first.",
 
 	ToolGetObject: "Retrieve the content of an S3 object. For text content, returns the content " +
-		"directly. For binary content, returns base64-encoded data. Large objects may be " +
-		"truncated based on size limits.",
+		"directly. For binary content, returns base64-encoded data. Gzip, zstd, and bzip2 " +
+		"objects are decompressed first. Large objects may be truncated based on size limits.",
 
 	ToolGetObjectMetadata: "Get metadata for an S3 object without downloading its content. Returns " +
 		"size, content type, last modified date, ETag, and custom metadata.",
@@ -32,6 +73,21 @@ var defaultDescriptions = map[ToolName]string{
 
 	ToolDeleteObject: "Delete an object from S3. This operation is irreversible unless versioning " +
 		"is enabled on the bucket. This operation may be blocked in read-only mode.",
+
+	ToolGetQuota: "Report current quota usage for this session, principal, and connection. Returns " +
+		"requests, bytes read, and bytes written within each sliding window, the configured " +
+		"limits (0 means unlimited), and a retry-after hint when a quota is exhausted.",
+
+	ToolAddConnection: "Add or replace an S3 connection at runtime. The configuration takes the " +
+		"endpoint, region, literal keys, and bucket and prefix settings of the connections file, " +
+		"and is probed before it is saved, so a connection that cannot reach its endpoint is " +
+		"rejected. Secret references, profiles, and file paths are not accepted. Requires admin access.",
+
+	ToolRemoveConnection: "Remove an S3 connection at runtime. The default connection cannot be " +
+		"removed. Requires admin access.",
+
+	ToolTestConnection: "Test an existing S3 connection, or a candidate configuration without adding " +
+		"it. Returns the health status, probe latency, and error. Requires admin access.",
 }
 
 // DefaultDescription returns the default description for a tool.
@@ -55,6 +111,7 @@ func (t *Toolkit) getDescription(name ToolName, cfg *toolConfig) string {
 		return desc
 	}
 
-	// Default description (lowest priority)
-	return defaultDescriptions[name]
+	// Default description (lowest priority), noting connections that lack
+	// the feature the tool needs
+	return defaultDescriptions[name] + t.capabilityNote(name)
 }
@@ -20,7 +20,7 @@ func TestDefaultDescription(t *testing.T) {
 	})
 
 	t.Run("all tools have defaults", func(t *testing.T) {
-		for _, name := range AllTools() {
+		for _, name := range append(AllTools(), AdminTools()...) {
 			desc := DefaultDescription(name)
 			if desc == "" {
 				t.Errorf("tool %s has no default description", name)
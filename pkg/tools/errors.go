@@ -25,8 +25,27 @@ var (
 	// ErrConnectionNotFound is returned when a requested connection doesn't exist.
 	ErrConnectionNotFound = errors.New("connection not found")
 
+	// ErrAccessDenied is returned when a connection's guardrails do not permit
+	// the requested bucket or key.
+	ErrAccessDenied = errors.New("access denied")
+
 	// ErrNotFound is returned when a requested resource doesn't exist.
 	ErrNotFound = errors.New("resource not found")
+
+	// ErrThrottled is returned when the service rate limits a request.
+	ErrThrottled = errors.New("request throttled")
+
+	// ErrPreconditionFailed is returned when a conditional request's
+	// precondition does not hold.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrEndpointUnreachable is returned when the service cannot be reached
+	// or fails to handle a request.
+	ErrEndpointUnreachable = errors.New("endpoint unreachable")
+
+	// ErrNotSupported is returned when a connection's service does not
+	// support the feature a tool needs.
+	ErrNotSupported = errors.New("feature not supported")
 )
 
 // ErrorResult creates an MCP CallToolResult with an error message.
@@ -0,0 +1,266 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// Limits for s3_find_objects.
+const (
+	defaultFindMaxResults = 100
+	maxFindMaxResults     = 1000
+	defaultFindScanLimit  = 10000
+	maxFindScanLimit      = 100000
+)
+
+// FindObjectsResult represents the result of finding objects.
+type FindObjectsResult struct {
+	Bucket      string         `json:"bucket"`
+	Prefix      string         `json:"prefix,omitempty"`
+	Objects     []ObjectResult `json:"objects"`
+	Count       int            `json:"count"`
+	Scanned     int            `json:"scanned"`
+	IsTruncated bool           `json:"is_truncated"`
+	Cursor      string         `json:"cursor,omitempty"`
+	ServedBy    string         `json:"served_by,omitempty"`
+}
+
+// registerFindObjectsTool registers the s3_find_objects tool.
+func (t *Toolkit) registerFindObjectsTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		findInput, ok := input.(FindObjectsInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleFindObjects(ctx, req, findInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolFindObjects, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolFindObjects),
+		Title:        t.getTitle(ToolFindObjects, cfg),
+		Description:  t.getDescription(ToolFindObjects, cfg),
+		Annotations:  t.getAnnotations(ToolFindObjects, cfg),
+		Icons:        t.getIcons(ToolFindObjects, cfg),
+		OutputSchema: t.getOutputSchema(ToolFindObjects, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input FindObjectsInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[FindObjectsResult](out), err
+	})
+}
+
+// handleFindObjects handles the s3_find_objects tool request.
+func (t *Toolkit) handleFindObjects(ctx context.Context, _ *mcp.CallToolRequest, input FindObjectsInput) (*mcp.CallToolResult, any, error) {
+	if input.Bucket == "" {
+		return ErrorResult("bucket parameter is required"), nil, nil
+	}
+	filter, err := newObjectFilter(input)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	maxResults := clampInt(input.MaxResults, defaultFindMaxResults, maxFindMaxResults)
+	scanLimit := clampInt(input.ScanLimit, defaultFindScanLimit, maxFindScanLimit)
+
+	s3Client, release, err := t.holdClient(input.Connection)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	defer release()
+	if err := t.guardFor(s3Client).checkListPrefix(input.Bucket, input.Prefix); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	result := FindObjectsResult{
+		Bucket:  input.Bucket,
+		Prefix:  input.Prefix,
+		Objects: make([]ObjectResult, 0),
+	}
+	ctx, servedBy := withServedBy(ctx)
+	walk, err := walkObjects(ctx, s3Client, input.Bucket, input.Prefix, input.Cursor, scanLimit, func(obj client.ObjectInfo) bool {
+		if filter.match(obj) {
+			result.Objects = append(result.Objects, objectResult(obj))
+		}
+		return len(result.Objects) < maxResults
+	})
+	if err != nil {
+		return ErrorResultFor(fmt.Errorf("failed to find objects: %w", err)), nil, nil
+	}
+
+	result.Count = len(result.Objects)
+	result.Scanned = walk.Scanned
+	result.Cursor = walk.Cursor
+	result.IsTruncated = walk.Cursor != ""
+	result.ServedBy = servedBy()
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
+
+// clampInt returns v, or def when v is not positive, capped at limit.
+func clampInt(v, def, limit int) int {
+	if v <= 0 {
+		return def
+	}
+	return min(v, limit)
+}
+
+// objectFilter selects listed objects for s3_find_objects.
+type objectFilter struct {
+	glob         *regexp.Regexp
+	globBase     bool // match glob against the base name
+	regex        *regexp.Regexp
+	minSize      int64
+	maxSize      int64 // 0 = no maximum
+	after        time.Time
+	before       time.Time
+	storageClass string
+	extensions   []string
+}
+
+// newObjectFilter builds the filter described by input.
+func newObjectFilter(input FindObjectsInput) (objectFilter, error) {
+	var f objectFilter
+	var err error
+	if input.Pattern != "" {
+		if f.glob, err = globRegexp(input.Pattern); err != nil {
+			return f, fmt.Errorf("%w: pattern: %v", ErrInvalidParameter, err)
+		}
+		f.globBase = !strings.Contains(input.Pattern, "/")
+	}
+	if input.Regex != "" {
+		if f.regex, err = regexp.Compile(input.Regex); err != nil {
+			return f, fmt.Errorf("%w: regex: %v", ErrInvalidParameter, err)
+		}
+	}
+	if input.MinSize < 0 || input.MaxSize < 0 || (input.MaxSize > 0 && input.MinSize > input.MaxSize) {
+		return f, fmt.Errorf("%w: min_size and max_size must be non-negative with min_size <= max_size", ErrInvalidParameter)
+	}
+	f.minSize, f.maxSize = input.MinSize, input.MaxSize
+	if f.after, err = parseFilterTime(input.ModifiedAfter); err != nil {
+		return f, fmt.Errorf("%w: modified_after: %v", ErrInvalidParameter, err)
+	}
+	if f.before, err = parseFilterTime(input.ModifiedBefore); err != nil {
+		return f, fmt.Errorf("%w: modified_before: %v", ErrInvalidParameter, err)
+	}
+	f.storageClass = strings.ToUpper(input.StorageClass)
+	for _, ext := range input.Extensions {
+		if ext = strings.ToLower(strings.TrimPrefix(ext, ".")); ext != "" {
+			f.extensions = append(f.extensions, "."+ext)
+		}
+	}
+	return f, nil
+}
+
+// match reports whether obj passes every filter.
+func (f objectFilter) match(obj client.ObjectInfo) bool {
+	if f.glob != nil {
+		name := obj.Key
+		if f.globBase {
+			name = path.Base(name)
+		}
+		if !f.glob.MatchString(name) {
+			return false
+		}
+	}
+	if f.regex != nil && !f.regex.MatchString(obj.Key) {
+		return false
+	}
+	if obj.Size < f.minSize || (f.maxSize > 0 && obj.Size > f.maxSize) {
+		return false
+	}
+	if !f.after.IsZero() && obj.LastModified.Before(f.after) {
+		return false
+	}
+	if !f.before.IsZero() && !obj.LastModified.Before(f.before) {
+		return false
+	}
+	if f.storageClass != "" && f.storageClass != storageClassOf(obj) {
+		return false
+	}
+	if len(f.extensions) > 0 {
+		key := strings.ToLower(obj.Key)
+		for _, ext := range f.extensions {
+			if strings.HasSuffix(key, ext) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// storageClassOf returns the object's storage class, treating an empty class
+// as STANDARD as S3 does.
+func storageClassOf(obj client.ObjectInfo) string {
+	if obj.StorageClass == "" {
+		return "STANDARD"
+	}
+	return strings.ToUpper(obj.StorageClass)
+}
+
+// parseFilterTime parses an RFC 3339 timestamp or a YYYY-MM-DD date. An empty
+// string yields the zero time.
+func parseFilterTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("want RFC 3339 or YYYY-MM-DD, got %q", s)
+	}
+	return t, nil
+}
+
+// globRegexp compiles a glob into an anchored regular expression. "*" and "?"
+// do not match "/", "**" matches across "/", and "[...]" is a character
+// class.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**/") {
+				b.WriteString("(?:.*/)?")
+				i += 2
+			} else if strings.HasPrefix(glob[i:], "**") {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class in %q", glob)
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
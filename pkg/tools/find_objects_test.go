@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// pagedListing returns a ListObjectsFunc that lists objects, sorted by key,
+// in pages of pageSize using the index of the next key as the continuation
+// token. It counts the calls in *calls when calls is not nil.
+func pagedListing(objects []client.ObjectInfo, pageSize int, calls *int) func(
+	context.Context, string, string, string, int32, string,
+) (*client.ListObjectsOutput, error) {
+	sorted := slices.Clone(objects)
+	slices.SortFunc(sorted, func(a, b client.ObjectInfo) int {
+		switch {
+		case a.Key < b.Key:
+			return -1
+		case a.Key > b.Key:
+			return 1
+		}
+		return 0
+	})
+	return func(_ context.Context, _, prefix, _ string, _ int32, token string) (*client.ListObjectsOutput, error) {
+		if calls != nil {
+			*calls++
+		}
+		var matching []client.ObjectInfo
+		for _, obj := range sorted {
+			if len(obj.Key) >= len(prefix) && obj.Key[:len(prefix)] == prefix {
+				matching = append(matching, obj)
+			}
+		}
+		start := 0
+		if token != "" {
+			start, _ = strconv.Atoi(token)
+		}
+		end := min(start+pageSize, len(matching))
+		out := &client.ListObjectsOutput{Objects: matching[start:end]}
+		if end < len(matching) {
+			out.IsTruncated = true
+			out.NextContinueToken = strconv.Itoa(end)
+		}
+		return out, nil
+	}
+}
+
+func TestFindObjects(t *testing.T) {
+	week := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	objects := []client.ObjectInfo{
+		{Key: "data/a.parquet", Size: 200 << 20, LastModified: week.Add(24 * time.Hour)},
+		{Key: "data/b.parquet", Size: 10 << 20, LastModified: week.Add(24 * time.Hour)},
+		{Key: "data/old.parquet", Size: 300 << 20, LastModified: week.Add(-24 * time.Hour)},
+		{Key: "data/c.csv", Size: 500 << 20, LastModified: week.Add(48 * time.Hour)},
+		{Key: "data/nested/d.PARQUET", Size: 150 << 20, LastModified: week.Add(72 * time.Hour), StorageClass: "GLACIER"},
+		{Key: "logs/e.parquet", Size: 400 << 20, LastModified: week.Add(24 * time.Hour)},
+	}
+	mock := NewMockS3Client("test")
+	mock.ListObjectsFunc = pagedListing(objects, 2, nil)
+	toolkit := NewToolkit(mock)
+
+	find := func(input FindObjectsInput) *FindObjectsResult {
+		t.Helper()
+		input.Bucket = "bucket"
+		result, out, _ := toolkit.handleFindObjects(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error: %v", result.Content)
+		}
+		return out.(*FindObjectsResult)
+	}
+	keys := func(r *FindObjectsResult) []string {
+		var keys []string
+		for _, obj := range r.Objects {
+			keys = append(keys, obj.Key)
+		}
+		return keys
+	}
+
+	got := find(FindObjectsInput{Prefix: "data/", Extensions: []string{"parquet"}, MinSize: 100 << 20, ModifiedAfter: "2026-10-12"})
+	if want := []string{"data/a.parquet", "data/nested/d.PARQUET"}; !slices.Equal(keys(got), want) {
+		t.Errorf("matches = %v, want %v", keys(got), want)
+	}
+	if got.Scanned != 5 || got.IsTruncated || got.Cursor != "" {
+		t.Errorf("scanned = %d truncated = %v cursor = %q", got.Scanned, got.IsTruncated, got.Cursor)
+	}
+
+	if got := find(FindObjectsInput{Pattern: "data/*.parquet"}); len(got.Objects) != 3 {
+		t.Errorf("glob with '/' matched %v, want the three top-level parquet files", keys(got))
+	}
+	if got := find(FindObjectsInput{Pattern: "data/**/*.PARQUET"}); !slices.Equal(keys(got), []string{"data/nested/d.PARQUET"}) {
+		t.Errorf("'**' glob matched %v", keys(got))
+	}
+	if got := find(FindObjectsInput{Pattern: "*.csv"}); !slices.Equal(keys(got), []string{"data/c.csv"}) {
+		t.Errorf("base name glob matched %v", keys(got))
+	}
+	if got := find(FindObjectsInput{Regex: `^logs/`, StorageClass: "standard"}); !slices.Equal(keys(got), []string{"logs/e.parquet"}) {
+		t.Errorf("regex and storage class matched %v", keys(got))
+	}
+	if got := find(FindObjectsInput{StorageClass: "GLACIER", ModifiedBefore: "2026-10-20T00:00:00Z"}); len(got.Objects) != 1 {
+		t.Errorf("storage class matched %v", keys(got))
+	}
+
+	// Resuming with the cursor visits every key exactly once.
+	var all []string
+	cursor := ""
+	for range 10 {
+		page := find(FindObjectsInput{MaxResults: 2, Cursor: cursor})
+		all = append(all, keys(page)...)
+		if cursor = page.Cursor; cursor == "" {
+			break
+		}
+	}
+	if len(all) != len(objects) {
+		t.Errorf("resumed search returned %d keys, want %d: %v", len(all), len(objects), all)
+	}
+
+	// The key budget stops the walk with a cursor.
+	budget := find(FindObjectsInput{ScanLimit: 3})
+	if budget.Scanned != 3 || !budget.IsTruncated || budget.Cursor == "" {
+		t.Errorf("budgeted search = %+v", budget)
+	}
+}
+
+func TestFindObjects_InvalidInput(t *testing.T) {
+	toolkit := NewToolkit(NewMockS3Client("test"))
+	for _, input := range []FindObjectsInput{
+		{},
+		{Bucket: "b", Regex: "("},
+		{Bucket: "b", Pattern: "[abc"},
+		{Bucket: "b", MinSize: 10, MaxSize: 5},
+		{Bucket: "b", ModifiedAfter: "last week"},
+		{Bucket: "b", Cursor: "not-a-cursor"},
+		{Bucket: "b", Cursor: listCursor{Bucket: "other"}.encode()},
+	} {
+		result, _, _ := toolkit.handleFindObjects(context.Background(), nil, input)
+		if !result.IsError {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+
+	if _, err := decodeCursor("%%%", "b", ""); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("decodeCursor error = %v, want ErrInvalidParameter", err)
+	}
+}
@@ -10,20 +10,24 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/txn2/mcp-s3/pkg/client"
+	"github.com/txn2/mcp-s3/pkg/integration"
 )
 
 // GetObjectResult represents the result of getting an object.
 type GetObjectResult struct {
-	Bucket       string            `json:"bucket"`
-	Key          string            `json:"key"`
-	Size         int64             `json:"size"`
-	ContentType  string            `json:"content_type,omitempty"`
-	LastModified string            `json:"last_modified,omitempty"`
-	ETag         string            `json:"etag,omitempty"`
-	Metadata     map[string]string `json:"metadata,omitempty"`
-	Content      string            `json:"content"`
-	IsBase64     bool              `json:"is_base64"`
-	Truncated    bool              `json:"truncated"`
+	Bucket           string            `json:"bucket"`
+	Key              string            `json:"key"`
+	Size             int64             `json:"size"`
+	ContentType      string            `json:"content_type,omitempty"`
+	LastModified     string            `json:"last_modified,omitempty"`
+	ETag             string            `json:"etag,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	Content          string            `json:"content"`
+	IsBase64         bool              `json:"is_base64"`
+	Truncated        bool              `json:"truncated"`
+	Compression      string            `json:"compression,omitempty"`
+	DecompressedSize int64             `json:"decompressed_size,omitempty"`
+	ServedBy         string            `json:"served_by,omitempty"`
 }
 
 // registerGetObjectTool registers the s3_get_object tool.
@@ -45,12 +49,9 @@ func (t *Toolkit) registerGetObjectTool(server *mcp.Server, cfg *toolConfig) {
 		Annotations:  t.getAnnotations(ToolGetObject, cfg),
 		Icons:        t.getIcons(ToolGetObject, cfg),
 		OutputSchema: t.getOutputSchema(ToolGetObject, cfg),
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetObjectInput) (*mcp.CallToolResult, *GetObjectResult, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetObjectInput) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*GetObjectResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[GetObjectResult](out), err
 	})
 }
 
@@ -65,24 +66,56 @@ func (t *Toolkit) handleGetObject(ctx context.Context, _ *mcp.CallToolRequest, i
 	}
 
 	// Get client
-	s3Client, err := t.GetClient(input.Connection)
+	s3Client, release, err := t.holdClient(input.Connection)
 	if err != nil {
 		return ErrorResult(err.Error()), nil, nil
 	}
+	defer release()
+
+	ctx, servedBy := withServedBy(ctx)
 
-	// Check size limit
+	// Check the connection's guardrails and size limit
+	guard := t.guardFor(s3Client)
+	if err = guard.checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
 	if err = t.checkGetSizeLimit(ctx, s3Client, input.Bucket, input.Key); err != nil {
-		return ErrorResult(err.Error()), nil, nil
+		return ErrorResultFor(err), nil, nil
 	}
 
 	// Get object
 	content, err := s3Client.GetObject(ctx, input.Bucket, input.Key)
 	if err != nil {
-		return ErrorResultf("failed to get object: %v", err), nil, nil
+		return S3ErrorResult("get object", err), nil, nil
+	}
+	recordBytesRead(ctx, int64(len(content.Body)))
+
+	// Decompress gzip, zstd, and bzip2 content unless raw bytes were asked for
+	compression := integration.CompressionNone
+	truncated := false
+	if !input.Raw {
+		compression = integration.DetectCompression(content.ContentEncoding, input.Key, content.Body)
+	}
+	if compression != integration.CompressionNone {
+		decompressed := *content
+		decompressed.Body, truncated, err = integration.Decompress(content.Body, compression, guard.maxGetSize)
+		if err != nil {
+			return ErrorResultf("failed to decompress object: %v", err), nil, nil
+		}
+		if truncated {
+			decompressed.Body = trimPartialRune(decompressed.Body)
+		}
+		content = &decompressed
 	}
 
 	// Build result
 	result := buildGetResult(input.Bucket, input.Key, content)
+	if compression != integration.CompressionNone {
+		result.Compression = compression
+		result.DecompressedSize = int64(len(content.Body))
+		result.Truncated = truncated
+	}
+	result.ServedBy = servedBy()
 	jsonResult, err := JSONResult(result)
 	if err != nil {
 		return ErrorResultf("failed to format result: %v", err), nil, nil
@@ -90,16 +123,19 @@ func (t *Toolkit) handleGetObject(ctx context.Context, _ *mcp.CallToolRequest, i
 	return jsonResult, &result, nil
 }
 
+// checkGetSizeLimit returns an error if the object is larger than the
+// retrieval limit of the connection behind s3Client.
 func (t *Toolkit) checkGetSizeLimit(ctx context.Context, s3Client S3Client, bucket, key string) error {
-	if t.maxGetSize <= 0 {
+	maxGetSize := t.guardFor(s3Client).maxGetSize
+	if maxGetSize <= 0 {
 		return nil
 	}
 	meta, err := s3Client.GetObjectMetadata(ctx, bucket, key)
 	if err != nil {
 		return fmt.Errorf("failed to get object metadata: %w", err)
 	}
-	if meta.Size > t.maxGetSize {
-		return fmt.Errorf("%w: object size %d bytes exceeds limit of %d bytes", ErrSizeLimitExceeded, meta.Size, t.maxGetSize)
+	if meta.Size > maxGetSize {
+		return fmt.Errorf("%w: object size %d bytes exceeds limit of %d bytes", ErrSizeLimitExceeded, meta.Size, maxGetSize)
 	}
 	return nil
 }
@@ -121,6 +157,20 @@ func buildGetResult(bucket, key string, content *client.ObjectContent) GetObject
 	return result
 }
 
+// trimPartialRune drops an incomplete UTF-8 sequence from the end of b, left
+// when decompressed text is cut at the size limit.
+func trimPartialRune(b []byte) []byte {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i]
+			}
+			break
+		}
+	}
+	return b
+}
+
 func encodeContent(contentType string, body []byte) (string, bool) {
 	if isTextContent(contentType, body) {
 		return string(body), false
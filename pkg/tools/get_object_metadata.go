@@ -16,6 +16,7 @@ type GetObjectMetadataResult struct {
 	LastModified  string            `json:"last_modified,omitempty"`
 	ETag          string            `json:"etag,omitempty"`
 	Metadata      map[string]string `json:"metadata,omitempty"`
+	ServedBy      string            `json:"served_by,omitempty"`
 }
 
 // registerGetObjectMetadataTool registers the s3_get_object_metadata tool.
@@ -39,12 +40,9 @@ func (t *Toolkit) registerGetObjectMetadataTool(server *mcp.Server, cfg *toolCon
 		OutputSchema: t.getOutputSchema(ToolGetObjectMetadata, cfg),
 	}, func(
 		ctx context.Context, req *mcp.CallToolRequest, input GetObjectMetadataInput,
-	) (*mcp.CallToolResult, *GetObjectMetadataResult, error) {
+	) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*GetObjectMetadataResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[GetObjectMetadataResult](out), err
 	})
 }
 
@@ -61,15 +59,20 @@ func (t *Toolkit) handleGetObjectMetadata(
 	}
 
 	// Get client
-	client, err := t.GetClient(input.Connection)
+	client, release, err := t.holdClient(input.Connection)
 	if err != nil {
 		return ErrorResult(err.Error()), nil, nil
 	}
+	defer release()
+	if err := t.guardFor(client).checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
 
 	// Get metadata
+	ctx, servedBy := withServedBy(ctx)
 	meta, err := client.GetObjectMetadata(ctx, input.Bucket, input.Key)
 	if err != nil {
-		return ErrorResultf("failed to get object metadata: %v", err), nil, nil
+		return S3ErrorResult("get object metadata", err), nil, nil
 	}
 
 	// Build result
@@ -81,6 +84,7 @@ func (t *Toolkit) handleGetObjectMetadata(
 		ContentLength: meta.ContentLength,
 		ETag:          meta.ETag,
 		Metadata:      meta.Metadata,
+		ServedBy:      servedBy(),
 	}
 
 	if !meta.LastModified.IsZero() {
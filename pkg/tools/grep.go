@@ -0,0 +1,466 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+	"github.com/txn2/mcp-s3/pkg/integration"
+)
+
+// Limits for s3_grep.
+const (
+	defaultGrepMaxMatches  = 100
+	maxGrepMaxMatches      = 1000
+	defaultGrepScanLimit   = 10000
+	maxGrepScanLimit       = 100000
+	defaultGrepConcurrency = 4
+	maxGrepConcurrency     = 16
+	maxGrepContextLines    = 10
+
+	// defaultGrepObjectBytes is the per-object limit when the connection
+	// has no retrieval size limit.
+	defaultGrepObjectBytes = 10 * 1024 * 1024
+	defaultGrepTotalBytes  = 100 * 1024 * 1024
+	maxGrepTotalBytes      = 1024 * 1024 * 1024
+
+	// maxGrepLineBytes is the longest line that is searched in full. The rest
+	// of a longer line is skipped.
+	maxGrepLineBytes = 64 * 1024
+
+	// maxGrepLineOutput is the longest line returned in a match.
+	maxGrepLineOutput = 1000
+
+	// grepSniffBytes is how much of an object is checked for binary content.
+	grepSniffBytes = 8000
+
+	// maxGrepSkipped caps the skipped objects listed in a result.
+	maxGrepSkipped = 100
+)
+
+// Reasons an object was skipped, or only partly searched, by s3_grep.
+const (
+	GrepSkipTooLarge  = "too_large"
+	GrepSkipBinary    = "binary"
+	GrepSkipTruncated = "truncated"
+	GrepSkipError     = "error"
+)
+
+// GrepMatch is a line that matched in an s3_grep search.
+type GrepMatch struct {
+	Key    string   `json:"key"`
+	Line   int      `json:"line"`
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// GrepSkip is an object s3_grep did not search, or searched only in part.
+type GrepSkip struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// GrepResult represents the result of searching object contents.
+type GrepResult struct {
+	Bucket          string      `json:"bucket"`
+	Prefix          string      `json:"prefix,omitempty"`
+	Pattern         string      `json:"pattern"`
+	Matches         []GrepMatch `json:"matches"`
+	Count           int         `json:"count"`
+	ObjectsScanned  int         `json:"objects_scanned"`
+	ObjectsSearched int         `json:"objects_searched"`
+	ObjectsMatched  int         `json:"objects_matched"`
+	BytesRead       int64       `json:"bytes_read"`
+	Skipped         []GrepSkip  `json:"skipped,omitempty"`
+	SkippedCount    int         `json:"skipped_count,omitempty"`
+	IsTruncated     bool        `json:"is_truncated"`
+	StopReason      string      `json:"stop_reason,omitempty"`
+	ServedBy        string      `json:"served_by,omitempty"`
+}
+
+// registerGrepTool registers the s3_grep tool.
+func (t *Toolkit) registerGrepTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		grepInput, ok := input.(GrepInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleGrep(ctx, req, grepInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolGrep, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolGrep),
+		Title:        t.getTitle(ToolGrep, cfg),
+		Description:  t.getDescription(ToolGrep, cfg),
+		Annotations:  t.getAnnotations(ToolGrep, cfg),
+		Icons:        t.getIcons(ToolGrep, cfg),
+		OutputSchema: t.getOutputSchema(ToolGrep, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GrepInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[GrepResult](out), err
+	})
+}
+
+// handleGrep handles the s3_grep tool request.
+func (t *Toolkit) handleGrep(ctx context.Context, _ *mcp.CallToolRequest, input GrepInput) (*mcp.CallToolResult, any, error) {
+	if input.Bucket == "" {
+		return ErrorResult("bucket parameter is required"), nil, nil
+	}
+	if input.Pattern == "" {
+		return ErrorResult("pattern parameter is required"), nil, nil
+	}
+	re, err := grepRegexp(input.Pattern, input.Regex, input.IgnoreCase)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	var include objectFilter
+	if input.Include != "" {
+		if include, err = newObjectFilter(FindObjectsInput{Pattern: input.Include}); err != nil {
+			return ErrorResult(strings.Replace(err.Error(), "pattern:", "include:", 1)), nil, nil
+		}
+	}
+
+	s3Client, release, err := t.holdClient(input.Connection)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	defer release()
+	guard := t.guardFor(s3Client)
+	if err := guard.checkListPrefix(input.Bucket, input.Prefix); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	objectLimit := int64(defaultGrepObjectBytes)
+	if guard.maxGetSize > 0 {
+		objectLimit = guard.maxGetSize
+	}
+	if input.MaxObjectBytes > 0 {
+		objectLimit = min(objectLimit, input.MaxObjectBytes)
+	}
+	totalLimit := int64(defaultGrepTotalBytes)
+	if input.MaxTotalBytes > 0 {
+		totalLimit = min(input.MaxTotalBytes, maxGrepTotalBytes)
+	}
+
+	g := &grepSearch{
+		s3Client:     s3Client,
+		bucket:       input.Bucket,
+		re:           re,
+		contextLines: min(max(input.ContextLines, 0), maxGrepContextLines),
+		maxMatches:   clampInt(input.MaxMatches, defaultGrepMaxMatches, maxGrepMaxMatches),
+		objectLimit:  objectLimit,
+		totalLimit:   totalLimit,
+	}
+	concurrency := clampInt(input.Concurrency, defaultGrepConcurrency, maxGrepConcurrency)
+	scanLimit := clampInt(input.ScanLimit, defaultGrepScanLimit, maxGrepScanLimit)
+
+	ctx, servedBy := withServedBy(ctx)
+	jobs := make(chan client.ObjectInfo)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range jobs {
+				g.search(ctx, obj)
+			}
+		}()
+	}
+
+	stopReason := ""
+	walk, err := walkObjects(ctx, s3Client, input.Bucket, input.Prefix, "", scanLimit, func(obj client.ObjectInfo) bool {
+		if g.full() {
+			stopReason = StopReasonMaxMatches
+			return false
+		}
+		if input.Include != "" && !include.match(obj) {
+			return true
+		}
+		if obj.Size > objectLimit {
+			g.skip(obj.Key, GrepSkipTooLarge, fmt.Sprintf("%d bytes exceeds the per-object limit of %d bytes", obj.Size, objectLimit))
+			return true
+		}
+		if !g.reserve(obj.Size) {
+			stopReason = StopReasonByteBudget
+			return false
+		}
+		select {
+		case jobs <- obj:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+	close(jobs)
+	wg.Wait()
+	if err != nil {
+		return ErrorResultFor(fmt.Errorf("failed to search objects: %w", err)), nil, nil
+	}
+
+	result := g.result(input)
+	if stopReason == "" && walk.Cursor != "" {
+		stopReason = StopReasonScanLimit
+	}
+	if stopReason == "" && (g.capped || result.Count < g.matchCount) {
+		stopReason = StopReasonMaxMatches
+	}
+	result.IsTruncated = stopReason != ""
+	result.StopReason = stopReason
+	result.ObjectsScanned = walk.Scanned
+	result.ServedBy = servedBy()
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
+
+// grepRegexp compiles the s3_grep pattern, quoting it unless isRegex is set.
+func grepRegexp(pattern string, isRegex, ignoreCase bool) (*regexp.Regexp, error) {
+	if !isRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: pattern: %v", ErrInvalidParameter, err)
+	}
+	return re, nil
+}
+
+// grepSearch holds the state of one s3_grep call. Its methods are safe for
+// concurrent use by the search workers.
+type grepSearch struct {
+	s3Client     S3Client
+	bucket       string
+	re           *regexp.Regexp
+	contextLines int
+	maxMatches   int
+	objectLimit  int64
+	totalLimit   int64
+
+	mu         sync.Mutex
+	reserved   int64 // bytes of totalLimit held by objects read or queued
+	matches    []GrepMatch
+	matchCount int
+	capped     bool // an object had more matches than were kept
+	searched   int
+	matched    int
+	bytesRead  int64
+	skipped    []GrepSkip
+}
+
+// full reports whether max_matches matches have been found.
+func (g *grepSearch) full() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.matchCount >= g.maxMatches
+}
+
+// skip records that key was skipped or only partly searched.
+func (g *grepSearch) skip(key, reason, detail string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.skipped = append(g.skipped, GrepSkip{Key: key, Reason: reason, Detail: detail})
+}
+
+// reserve holds n bytes of the total byte budget for an object about to be
+// searched, or reports false when they do not fit.
+func (g *grepSearch) reserve(n int64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.reserved+n > g.totalLimit {
+		return false
+	}
+	g.reserved += n
+	return true
+}
+
+// settle charges the bytes obj fetched to the budget and releases the rest
+// of its reservation.
+func (g *grepSearch) settle(obj client.ObjectInfo, fetched int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.bytesRead += fetched
+	g.reserved -= obj.Size - fetched
+}
+
+// search streams obj through ranged reads and records the lines that match.
+// Reading stops at the first match past max_matches, at the per-object limit,
+// or at binary content, so only part of the object may be fetched.
+func (g *grepSearch) search(ctx context.Context, obj client.ObjectInfo) {
+	obr := newObjectReader(ctx, g.s3Client, g.bucket, obj.Key, obj.Size)
+	obr.budget = obj.Size
+	defer func() { g.settle(obj, obr.bytesRead) }()
+
+	head, err := obr.head(objectReaderBlockSize)
+	if err != nil {
+		g.skip(obj.Key, GrepSkipError, err.Error())
+		return
+	}
+	compression := integration.DetectCompression("", obj.Key, head)
+	r, err := integration.NewDecompressReader(obr, compression)
+	if err != nil {
+		g.skip(obj.Key, GrepSkipError, err.Error())
+		return
+	}
+	defer func() { _ = r.Close() }()
+	limited := &io.LimitedReader{R: r, N: g.objectLimit + 1}
+	br := bufio.NewReaderSize(limited, grepSniffBytes)
+	if sniff, _ := br.Peek(grepSniffBytes); bytes.IndexByte(sniff, 0) >= 0 {
+		g.skip(obj.Key, GrepSkipBinary, "")
+		return
+	}
+
+	matches, capped, err := g.scan(obj.Key, br)
+	switch {
+	case err != nil:
+		g.skip(obj.Key, GrepSkipError, err.Error())
+	case limited.N == 0:
+		g.skip(obj.Key, GrepSkipTruncated, fmt.Sprintf("searched the first %d bytes after decompression", g.objectLimit))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.searched++
+	g.capped = g.capped || capped
+	if len(matches) > 0 {
+		g.matched++
+		g.matchCount += len(matches)
+		g.matches = append(g.matches, matches...)
+	}
+}
+
+// scan returns the lines of r that match, with context. It stops after
+// maxMatches matches and reports whether more lines would have matched.
+func (g *grepSearch) scan(key string, r *bufio.Reader) ([]GrepMatch, bool, error) {
+	var matches []GrepMatch
+	var before []string
+	var pending []int // matches still collecting after-context lines
+
+	for lineNo := 1; ; lineNo++ {
+		line, err := readGrepLine(r)
+		if err == io.EOF {
+			return matches, false, nil
+		}
+		if err != nil {
+			return matches, false, err
+		}
+		text := truncateLine(line)
+
+		open := pending[:0]
+		for _, i := range pending {
+			matches[i].After = append(matches[i].After, text)
+			if len(matches[i].After) < g.contextLines {
+				open = append(open, i)
+			}
+		}
+		pending = open
+
+		if g.re.Match(line) {
+			if len(matches) >= g.maxMatches {
+				return matches, true, nil
+			}
+			matches = append(matches, GrepMatch{
+				Key:    key,
+				Line:   lineNo,
+				Text:   text,
+				Before: slices.Clone(before),
+			})
+			if g.contextLines > 0 {
+				pending = append(pending, len(matches)-1)
+			}
+		}
+		if g.contextLines > 0 {
+			if len(before) == g.contextLines {
+				before = before[1:]
+			}
+			before = append(before, text)
+		}
+	}
+}
+
+// result returns the matches sorted by key and line, trimmed to max_matches.
+func (g *grepSearch) result(input GrepInput) GrepResult {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	slices.SortFunc(g.matches, func(a, b GrepMatch) int {
+		if c := strings.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		return a.Line - b.Line
+	})
+	matches := g.matches
+	if len(matches) > g.maxMatches {
+		matches = matches[:g.maxMatches]
+	}
+	if matches == nil {
+		matches = make([]GrepMatch, 0)
+	}
+
+	slices.SortFunc(g.skipped, func(a, b GrepSkip) int { return strings.Compare(a.Key, b.Key) })
+	skipped := g.skipped
+	if len(skipped) > maxGrepSkipped {
+		skipped = skipped[:maxGrepSkipped]
+	}
+
+	return GrepResult{
+		Bucket:          input.Bucket,
+		Prefix:          input.Prefix,
+		Pattern:         input.Pattern,
+		Matches:         matches,
+		Count:           len(matches),
+		ObjectsSearched: g.searched,
+		ObjectsMatched:  g.matched,
+		BytesRead:       g.bytesRead,
+		Skipped:         skipped,
+		SkippedCount:    len(g.skipped),
+	}
+}
+
+// readGrepLine reads the next line from r without its line ending. Lines
+// longer than maxGrepLineBytes are cut short and the rest is discarded.
+func readGrepLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			if err == io.EOF && line != nil {
+				return line, nil
+			}
+			return nil, err
+		}
+		if room := maxGrepLineBytes - len(line); room > 0 {
+			line = append(line, chunk[:min(len(chunk), room)]...)
+		}
+		if line == nil {
+			line = []byte{}
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// truncateLine returns line as a string of at most maxGrepLineOutput bytes,
+// cut on a rune boundary.
+func truncateLine(line []byte) string {
+	return truncateString(string(line), maxGrepLineOutput)
+}
@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// newContentMock returns a mock serving contents under "bucket" with a sorted,
+// paginated listing.
+func newContentMock(contents map[string][]byte) *MockS3Client {
+	mock := NewMockS3Client("test")
+	var objects []client.ObjectInfo
+	for key, body := range contents {
+		mock.AddObject("bucket", key, body, "")
+		objects = append(objects, client.ObjectInfo{Key: key, Size: int64(len(body))})
+	}
+	mock.ListObjectsFunc = pagedListing(objects, 2, nil)
+	return mock
+}
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGrep(t *testing.T) {
+	mock := newContentMock(map[string][]byte{
+		"logs/a.log":     []byte("start\nok\nERROR disk full\nretry\nok\n"),
+		"logs/b.log.gz":  gzipBytes(t, "line one\nerror: timeout\n"),
+		"logs/c.log":     []byte("nothing here\n"),
+		"logs/image.png": {0x89, 'P', 'N', 'G', 0, 0, 'E', 'R', 'R', 'O', 'R'},
+		"logs/notes.txt": []byte("ERROR in notes\n"),
+	})
+	toolkit := NewToolkit(mock)
+
+	grep := func(input GrepInput) *GrepResult {
+		t.Helper()
+		input.Bucket = "bucket"
+		result, out, _ := toolkit.handleGrep(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error: %v", result.Content)
+		}
+		return out.(*GrepResult)
+	}
+	locations := func(r *GrepResult) []string {
+		var locs []string
+		for _, m := range r.Matches {
+			locs = append(locs, fmt.Sprintf("%s:%d", m.Key, m.Line))
+		}
+		return locs
+	}
+
+	got := grep(GrepInput{Prefix: "logs/", Pattern: "error", IgnoreCase: true, ContextLines: 1})
+	if want := []string{"logs/a.log:3", "logs/b.log.gz:2", "logs/notes.txt:1"}; !slices.Equal(locations(got), want) {
+		t.Errorf("matches = %v, want %v", locations(got), want)
+	}
+	if m := got.Matches[0]; m.Text != "ERROR disk full" || !slices.Equal(m.Before, []string{"ok"}) || !slices.Equal(m.After, []string{"retry"}) {
+		t.Errorf("match with context = %+v", m)
+	}
+	if got.ObjectsSearched != 4 || got.ObjectsMatched != 3 || got.IsTruncated {
+		t.Errorf("searched = %d matched = %d truncated = %v", got.ObjectsSearched, got.ObjectsMatched, got.IsTruncated)
+	}
+	if len(got.Skipped) != 1 || got.Skipped[0].Key != "logs/image.png" || got.Skipped[0].Reason != GrepSkipBinary {
+		t.Errorf("skipped = %+v", got.Skipped)
+	}
+
+	if got := grep(GrepInput{Pattern: "ERROR"}); len(got.Matches) != 2 {
+		t.Errorf("case-sensitive literal matched %v", locations(got))
+	}
+	if got := grep(GrepInput{Pattern: `^ERROR \w+ full$`, Regex: true}); !slices.Equal(locations(got), []string{"logs/a.log:3"}) {
+		t.Errorf("regex matched %v", locations(got))
+	}
+	if got := grep(GrepInput{Pattern: "error", IgnoreCase: true, Include: "*.txt"}); !slices.Equal(locations(got), []string{"logs/notes.txt:1"}) {
+		t.Errorf("include matched %v", locations(got))
+	}
+	if got := grep(GrepInput{Pattern: "a.b", Regex: false}); len(got.Matches) != 0 {
+		t.Errorf("literal pattern was treated as a regex: %v", locations(got))
+	}
+}
+
+func TestGrep_Budgets(t *testing.T) {
+	contents := make(map[string][]byte)
+	for i := range 6 {
+		contents[fmt.Sprintf("k%d", i)] = []byte(strings.Repeat("match\n", 10))
+	}
+	toolkit := NewToolkit(newContentMock(contents))
+
+	grep := func(input GrepInput) *GrepResult {
+		t.Helper()
+		input.Bucket, input.Pattern = "bucket", "match"
+		result, out, _ := toolkit.handleGrep(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error: %v", result.Content)
+		}
+		return out.(*GrepResult)
+	}
+
+	got := grep(GrepInput{MaxMatches: 15, Concurrency: 1})
+	if got.Count != 15 || !got.IsTruncated || got.StopReason != StopReasonMaxMatches {
+		t.Errorf("max matches: count = %d truncated = %v reason = %q", got.Count, got.IsTruncated, got.StopReason)
+	}
+
+	got = grep(GrepInput{MaxTotalBytes: 150})
+	if got.ObjectsSearched != 2 || got.BytesRead != 120 || got.StopReason != StopReasonByteBudget {
+		t.Errorf("byte budget: searched = %d read = %d reason = %q", got.ObjectsSearched, got.BytesRead, got.StopReason)
+	}
+
+	// Only the bytes fetched are charged: a binary object is read up to its
+	// first block, and the rest of its reservation goes to later objects.
+	bin := append([]byte{0}, bytes.Repeat([]byte("x"), 4*objectReaderBlockSize)...)
+	binToolkit := NewToolkit(newContentMock(map[string][]byte{"a.bin": bin, "b.log": []byte("match\n")}))
+	result, out, _ := binToolkit.handleGrep(context.Background(), nil, GrepInput{Bucket: "bucket", Pattern: "match", Concurrency: 1})
+	if result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	if got := out.(*GrepResult); got.BytesRead != objectReaderBlockSize+6 || got.Count != 1 || got.IsTruncated {
+		t.Errorf("binary object: read = %d count = %d truncated = %v", got.BytesRead, got.Count, got.IsTruncated)
+	}
+
+	got = grep(GrepInput{MaxObjectBytes: 50})
+	if got.ObjectsSearched != 0 || got.SkippedCount != 6 || got.Skipped[0].Reason != GrepSkipTooLarge {
+		t.Errorf("object limit: searched = %d skipped = %+v", got.ObjectsSearched, got.Skipped)
+	}
+
+	got = grep(GrepInput{ScanLimit: 3, MaxMatches: 1000})
+	if got.Count != 30 || got.StopReason != StopReasonScanLimit {
+		t.Errorf("scan limit: count = %d reason = %q", got.Count, got.StopReason)
+	}
+
+	for _, input := range []GrepInput{
+		{Bucket: "bucket"},
+		{Pattern: "x"},
+		{Bucket: "bucket", Pattern: "(", Regex: true},
+		{Bucket: "bucket", Pattern: "x", Include: "[a"},
+	} {
+		if result, _, _ := toolkit.handleGrep(context.Background(), nil, input); !result.IsError {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
+
+func TestReadGrepLine_LongLines(t *testing.T) {
+	long := strings.Repeat("x", maxGrepLineBytes+100)
+	mock := newContentMock(map[string][]byte{"big": []byte(long + "needle\nnext needle\n")})
+	_, out, _ := NewToolkit(mock).handleGrep(context.Background(), nil, GrepInput{Bucket: "bucket", Pattern: "needle"})
+	got := out.(*GrepResult)
+	if got.Count != 1 || got.Matches[0].Line != 2 {
+		t.Errorf("matches = %+v", got.Matches)
+	}
+
+	if text := truncateLine([]byte(long)); len(text) != maxGrepLineOutput+3 {
+		t.Errorf("truncated line length = %d", len(text))
+	}
+}
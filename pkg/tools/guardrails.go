@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// connectionGuard holds the guardrails for a single call: the toolkit-wide
+// settings combined with those of the connection the call uses. A connection
+// can make itself read-only and restrict buckets and prefixes, and its size
+// limits replace the toolkit-wide limits when set.
+type connectionGuard struct {
+	connection      string
+	readOnly        bool
+	maxGetSize      int64
+	maxPutSize      int64
+	allowedBuckets  []string
+	allowedPrefixes []string
+}
+
+// guardFor returns the guardrails that apply to calls made through c.
+func (t *Toolkit) guardFor(c S3Client) connectionGuard {
+	g := connectionGuard{
+		connection: c.ConnectionName(),
+		readOnly:   t.readOnly,
+		maxGetSize: t.maxGetSize,
+		maxPutSize: t.maxPutSize,
+	}
+
+	cfg := c.Config()
+	if cfg == nil {
+		return g
+	}
+	g.readOnly = g.readOnly || cfg.ReadOnly
+	if cfg.MaxGetSize > 0 {
+		g.maxGetSize = cfg.MaxGetSize
+	}
+	if cfg.MaxPutSize > 0 {
+		g.maxPutSize = cfg.MaxPutSize
+	}
+	g.allowedBuckets = cfg.AllowedBuckets
+	g.allowedPrefixes = cfg.AllowedPrefixes
+	return g
+}
+
+// checkWrite returns an error if writes are not permitted.
+func (g connectionGuard) checkWrite() error {
+	if !g.readOnly {
+		return nil
+	}
+	if g.connection == "" {
+		return ErrReadOnly
+	}
+	return fmt.Errorf("%w: connection %s is read-only", ErrReadOnly, g.connection)
+}
+
+// bucketAllowed reports whether the connection permits access to bucket.
+func (g connectionGuard) bucketAllowed(bucket string) bool {
+	return len(g.allowedBuckets) == 0 || slices.Contains(g.allowedBuckets, bucket)
+}
+
+// checkBucket returns an error if the connection does not permit bucket.
+func (g connectionGuard) checkBucket(bucket string) error {
+	if g.bucketAllowed(bucket) {
+		return nil
+	}
+	return fmt.Errorf("%w: bucket %s is not allowed on connection %s", ErrAccessDenied, bucket, g.connection)
+}
+
+// keyAllowed reports whether key is under one of the allowed prefixes.
+func (g connectionGuard) keyAllowed(key string) bool {
+	if len(g.allowedPrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range g.allowedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkKey returns an error if the connection does not permit the bucket or
+// the key is outside the allowed prefixes.
+func (g connectionGuard) checkKey(bucket, key string) error {
+	if err := g.checkBucket(bucket); err != nil {
+		return err
+	}
+	if g.keyAllowed(key) {
+		return nil
+	}
+	return fmt.Errorf("%w: key %s is outside the allowed prefixes (%s) on connection %s",
+		ErrAccessDenied, key, strings.Join(g.allowedPrefixes, ", "), g.connection)
+}
+
+// checkListPrefix returns an error if listing under prefix could reveal keys
+// outside the allowed prefixes. The listing prefix must itself start with an
+// allowed prefix.
+func (g connectionGuard) checkListPrefix(bucket, prefix string) error {
+	if err := g.checkBucket(bucket); err != nil {
+		return err
+	}
+	if g.keyAllowed(prefix) {
+		return nil
+	}
+	return fmt.Errorf("%w: listing prefix %q is outside the allowed prefixes (%s) on connection %s",
+		ErrAccessDenied, prefix, strings.Join(g.allowedPrefixes, ", "), g.connection)
+}
+
+// filterBuckets drops the buckets the connection does not permit.
+func (g connectionGuard) filterBuckets(buckets []client.BucketInfo) []client.BucketInfo {
+	if len(g.allowedBuckets) == 0 {
+		return buckets
+	}
+	allowed := make([]client.BucketInfo, 0, len(buckets))
+	for _, b := range buckets {
+		if g.bucketAllowed(b.Name) {
+			allowed = append(allowed, b)
+		}
+	}
+	return allowed
+}
@@ -37,28 +37,27 @@ func (t *Toolkit) registerListBucketsTool(server *mcp.Server, cfg *toolConfig) {
 		Annotations:  t.getAnnotations(ToolListBuckets, cfg),
 		Icons:        t.getIcons(ToolListBuckets, cfg),
 		OutputSchema: t.getOutputSchema(ToolListBuckets, cfg),
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListBucketsInput) (*mcp.CallToolResult, *ListBucketsResult, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListBucketsInput) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*ListBucketsResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[ListBucketsResult](out), err
 	})
 }
 
 // handleListBuckets handles the s3_list_buckets tool request.
 func (t *Toolkit) handleListBuckets(ctx context.Context, _ *mcp.CallToolRequest, input ListBucketsInput) (*mcp.CallToolResult, any, error) {
 	// Get client
-	client, err := t.GetClient(input.Connection)
+	client, release, err := t.holdClient(input.Connection)
 	if err != nil {
 		return ErrorResult(err.Error()), nil, nil
 	}
+	defer release()
 
 	// List buckets
 	buckets, err := client.ListBuckets(ctx)
 	if err != nil {
-		return ErrorResultf("failed to list buckets: %v", err), nil, nil
+		return S3ErrorResult("list buckets", err), nil, nil
 	}
+	buckets = t.guardFor(client).filterBuckets(buckets)
 
 	// Build result
 	result := ListBucketsResult{
@@ -1,9 +1,30 @@
 package tools
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// Limits for sorted listings.
+const (
+	defaultListScanLimit = 10000
+	maxListScanLimit     = 100000
+)
+
+// Fields and directions accepted by s3_list_objects sort_by and order.
+const (
+	SortByKey          = "key"
+	SortByLastModified = "last_modified"
+	SortBySize         = "size"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
 )
 
 // ListObjectsResult represents the result of listing objects.
@@ -16,6 +37,11 @@ type ListObjectsResult struct {
 	Count             int            `json:"count"`
 	IsTruncated       bool           `json:"is_truncated"`
 	NextContinueToken string         `json:"next_continuation_token,omitempty"`
+	SortBy            string         `json:"sort_by,omitempty"`
+	Order             string         `json:"order,omitempty"`
+	Scanned           int            `json:"scanned,omitempty"`
+	Exhaustive        *bool          `json:"exhaustive,omitempty"`
+	ServedBy          string         `json:"served_by,omitempty"`
 }
 
 // ObjectResult represents an object in the list results.
@@ -46,12 +72,9 @@ func (t *Toolkit) registerListObjectsTool(server *mcp.Server, cfg *toolConfig) {
 		Annotations:  t.getAnnotations(ToolListObjects, cfg),
 		Icons:        t.getIcons(ToolListObjects, cfg),
 		OutputSchema: t.getOutputSchema(ToolListObjects, cfg),
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListObjectsInput) (*mcp.CallToolResult, *ListObjectsResult, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListObjectsInput) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*ListObjectsResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[ListObjectsResult](out), err
 	})
 }
 
@@ -72,15 +95,24 @@ func (t *Toolkit) handleListObjects(ctx context.Context, _ *mcp.CallToolRequest,
 	}
 
 	// Get client
-	client, err := t.GetClient(input.Connection)
+	client, release, err := t.holdClient(input.Connection)
 	if err != nil {
 		return ErrorResult(err.Error()), nil, nil
 	}
+	defer release()
+	if err := t.guardFor(client).checkListPrefix(input.Bucket, input.Prefix); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	if input.SortBy != "" {
+		return t.handleSortedListObjects(ctx, client, input, int(maxKeys))
+	}
 
 	// List objects
+	ctx, servedBy := withServedBy(ctx)
 	output, err := client.ListObjects(ctx, input.Bucket, input.Prefix, input.Delimiter, maxKeys, input.ContinuationToken)
 	if err != nil {
-		return ErrorResultf("failed to list objects: %v", err), nil, nil
+		return S3ErrorResult("list objects", err), nil, nil
 	}
 
 	// Build result
@@ -93,20 +125,62 @@ func (t *Toolkit) handleListObjects(ctx context.Context, _ *mcp.CallToolRequest,
 		Count:             len(output.Objects),
 		IsTruncated:       output.IsTruncated,
 		NextContinueToken: output.NextContinueToken,
+		ServedBy:          servedBy(),
 	}
 
 	for _, obj := range output.Objects {
-		or := ObjectResult{
-			Key:          obj.Key,
-			Size:         obj.Size,
-			ETag:         obj.ETag,
-			StorageClass: obj.StorageClass,
-		}
-		if !obj.LastModified.IsZero() {
-			or.LastModified = obj.LastModified.Format("2006-01-02T15:04:05Z")
-		}
-		result.Objects = append(result.Objects, or)
+		result.Objects = append(result.Objects, objectResult(obj))
+	}
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
+
+// handleSortedListObjects scans up to scan_limit keys and returns the first
+// maxKeys of them in the requested order.
+func (t *Toolkit) handleSortedListObjects(ctx context.Context, s3Client S3Client, input ListObjectsInput, maxKeys int) (*mcp.CallToolResult, any, error) {
+	if input.Delimiter != "" || input.ContinuationToken != "" {
+		return ErrorResult("sort_by cannot be combined with delimiter or continuation_token"), nil, nil
+	}
+	order, err := listOrder(input.SortBy, input.Order)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	scanLimit := clampInt(input.ScanLimit, defaultListScanLimit, maxListScanLimit)
+
+	// topN keeps the greatest items, so an item ranks higher the earlier it
+	// should appear.
+	top := newTopN(maxKeys, func(a, b client.ObjectInfo) bool {
+		return compareObjects(a, b, input.SortBy, order) > 0
+	})
+	ctx, servedBy := withServedBy(ctx)
+	walk, err := walkObjects(ctx, s3Client, input.Bucket, input.Prefix, "", scanLimit, func(obj client.ObjectInfo) bool {
+		top.Push(obj)
+		return true
+	})
+	if err != nil {
+		return S3ErrorResult("list objects", err), nil, nil
+	}
+
+	exhaustive := walk.Cursor == ""
+	result := ListObjectsResult{
+		Bucket:      input.Bucket,
+		Prefix:      input.Prefix,
+		Objects:     make([]ObjectResult, 0, top.Len()),
+		IsTruncated: !exhaustive || walk.Scanned > top.Len(),
+		SortBy:      input.SortBy,
+		Order:       order,
+		Scanned:     walk.Scanned,
+		Exhaustive:  &exhaustive,
+		ServedBy:    servedBy(),
+	}
+	for _, obj := range top.Sorted() {
+		result.Objects = append(result.Objects, objectResult(obj))
 	}
+	result.Count = len(result.Objects)
 
 	jsonResult, err := JSONResult(result)
 	if err != nil {
@@ -114,3 +188,45 @@ func (t *Toolkit) handleListObjects(ctx context.Context, _ *mcp.CallToolRequest,
 	}
 	return jsonResult, &result, nil
 }
+
+// listOrder validates sortBy and returns the order, defaulting to descending
+// for last_modified and size and ascending for key.
+func listOrder(sortBy, order string) (string, error) {
+	switch sortBy {
+	case SortByKey, SortByLastModified, SortBySize:
+	default:
+		return "", fmt.Errorf("%w: sort_by must be %s, %s, or %s", ErrInvalidParameter, SortByKey, SortByLastModified, SortBySize)
+	}
+	switch order = strings.ToLower(order); order {
+	case OrderAsc, OrderDesc:
+		return order, nil
+	case "":
+		if sortBy == SortByKey {
+			return OrderAsc, nil
+		}
+		return OrderDesc, nil
+	}
+	return "", fmt.Errorf("%w: order must be %s or %s", ErrInvalidParameter, OrderAsc, OrderDesc)
+}
+
+// compareObjects returns a negative number when a comes before b in a listing
+// sorted by sortBy in order. Ties are broken by key, ascending.
+func compareObjects(a, b client.ObjectInfo, sortBy, order string) int {
+	c := 0
+	switch sortBy {
+	case SortByLastModified:
+		c = a.LastModified.Compare(b.LastModified)
+	case SortBySize:
+		c = cmp.Compare(a.Size, b.Size)
+	}
+	if order == OrderDesc {
+		c = -c
+	}
+	if c == 0 {
+		c = strings.Compare(a.Key, b.Key)
+		if sortBy == SortByKey && order == OrderDesc {
+			c = -c
+		}
+	}
+	return c
+}
@@ -21,6 +21,7 @@ type MockS3Client struct {
 	ListBucketsFunc       func(ctx context.Context) ([]client.BucketInfo, error)
 	ListObjectsFunc       func(ctx context.Context, bucket, prefix, delimiter string, maxKeys int32, continueToken string) (*client.ListObjectsOutput, error)
 	GetObjectFunc         func(ctx context.Context, bucket, key string) (*client.ObjectContent, error)
+	GetObjectRangeFunc    func(ctx context.Context, bucket, key string, offset, length int64) (*client.ObjectContent, error)
 	GetObjectMetadataFunc func(ctx context.Context, bucket, key string) (*client.ObjectMetadata, error)
 	PutObjectFunc         func(ctx context.Context, input *client.PutObjectInput) (*client.PutObjectOutput, error)
 	DeleteObjectFunc      func(ctx context.Context, bucket, key string) error
@@ -117,6 +118,26 @@ func (m *MockS3Client) GetObject(ctx context.Context, bucket, key string) (*clie
 	return content, nil
 }
 
+// GetObjectRange retrieves part of an object's content from S3.
+func (m *MockS3Client) GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (*client.ObjectContent, error) {
+	if m.GetObjectRangeFunc != nil {
+		return m.GetObjectRangeFunc(ctx, bucket, key, offset, length)
+	}
+
+	content, err := m.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	body := content.Body
+	start := min(offset, int64(len(body)))
+	end := min(offset+length, int64(len(body)))
+
+	part := *content
+	part.Body = body[start:end]
+	part.Size = int64(len(body))
+	return &part, nil
+}
+
 // GetObjectMetadata retrieves an object's metadata without downloading the content.
 func (m *MockS3Client) GetObjectMetadata(ctx context.Context, bucket, key string) (*client.ObjectMetadata, error) {
 	if m.GetObjectMetadataFunc != nil {
@@ -12,6 +12,30 @@ const (
 	// ToolListObjects lists objects in a bucket with optional prefix/delimiter.
 	ToolListObjects ToolName = "s3_list_objects"
 
+	// ToolFindObjects walks a prefix and returns keys matching filters.
+	ToolFindObjects ToolName = "s3_find_objects"
+
+	// ToolSummarizePrefix reports the size and makeup of a prefix.
+	ToolSummarizePrefix ToolName = "s3_summarize_prefix"
+
+	// ToolTree renders the prefix hierarchy under a prefix.
+	ToolTree ToolName = "s3_tree"
+
+	// ToolGrep searches the content of objects under a prefix.
+	ToolGrep ToolName = "s3_grep"
+
+	// ToolListArchive lists the entries of a zip or tar archive.
+	ToolListArchive ToolName = "s3_list_archive"
+
+	// ToolGetArchiveEntry retrieves one entry of a zip or tar archive.
+	ToolGetArchiveEntry ToolName = "s3_get_archive_entry"
+
+	// ToolInspectParquet reads the schema, statistics, and rows of a Parquet file.
+	ToolInspectParquet ToolName = "s3_inspect_parquet"
+
+	// ToolPreviewTable infers the schema of a delimited file and samples its rows.
+	ToolPreviewTable ToolName = "s3_preview_table"
+
 	// ToolGetObject retrieves object content from S3.
 	ToolGetObject ToolName = "s3_get_object"
 
@@ -32,6 +56,18 @@ const (
 
 	// ToolListConnections lists configured S3 connections.
 	ToolListConnections ToolName = "s3_list_connections"
+
+	// ToolGetQuota reports current quota usage for the calling session.
+	ToolGetQuota ToolName = "s3_get_quota"
+
+	// ToolAddConnection adds or replaces a connection at runtime (admin).
+	ToolAddConnection ToolName = "s3_add_connection"
+
+	// ToolRemoveConnection removes a connection at runtime (admin).
+	ToolRemoveConnection ToolName = "s3_remove_connection"
+
+	// ToolTestConnection probes a connection or candidate configuration (admin).
+	ToolTestConnection ToolName = "s3_test_connection"
 )
 
 // String returns the string representation of the tool name.
@@ -44,6 +80,14 @@ func AllTools() []ToolName {
 	return []ToolName{
 		ToolListBuckets,
 		ToolListObjects,
+		ToolFindObjects,
+		ToolSummarizePrefix,
+		ToolTree,
+		ToolGrep,
+		ToolListArchive,
+		ToolGetArchiveEntry,
+		ToolInspectParquet,
+		ToolPreviewTable,
 		ToolGetObject,
 		ToolGetObjectMetadata,
 		ToolPutObject,
@@ -51,15 +95,40 @@ func AllTools() []ToolName {
 		ToolCopyObject,
 		ToolPresignURL,
 		ToolListConnections,
+		ToolGetQuota,
+	}
+}
+
+// AdminTools returns a list of tool names that change or probe the server's
+// connections. They are not part of AllTools and are only registered when a
+// ConnectionAdmin is configured.
+func AdminTools() []ToolName {
+	return []ToolName{
+		ToolAddConnection,
+		ToolRemoveConnection,
+		ToolTestConnection,
+	}
+}
+
+// IsAdminTool returns true if the tool name is a connection administration tool.
+func IsAdminTool(name ToolName) bool {
+	switch name {
+	case ToolAddConnection, ToolRemoveConnection, ToolTestConnection:
+		return true
+	default:
+		return false
 	}
 }
 
 // WriteTools returns a list of tool names that perform write operations.
+// Adding and removing connections change the server, so they count as writes.
 func WriteTools() []ToolName {
 	return []ToolName{
 		ToolPutObject,
 		ToolDeleteObject,
 		ToolCopyObject,
+		ToolAddConnection,
+		ToolRemoveConnection,
 	}
 }
 
@@ -68,17 +137,26 @@ func ReadTools() []ToolName {
 	return []ToolName{
 		ToolListBuckets,
 		ToolListObjects,
+		ToolFindObjects,
+		ToolSummarizePrefix,
+		ToolTree,
+		ToolGrep,
+		ToolListArchive,
+		ToolGetArchiveEntry,
+		ToolInspectParquet,
+		ToolPreviewTable,
 		ToolGetObject,
 		ToolGetObjectMetadata,
 		ToolPresignURL,
 		ToolListConnections,
+		ToolGetQuota,
 	}
 }
 
 // IsWriteTool returns true if the tool name is a write operation.
 func IsWriteTool(name ToolName) bool {
 	switch name {
-	case ToolPutObject, ToolDeleteObject, ToolCopyObject:
+	case ToolPutObject, ToolDeleteObject, ToolCopyObject, ToolAddConnection, ToolRemoveConnection:
 		return true
 	default:
 		return false
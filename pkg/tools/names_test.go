@@ -72,6 +72,9 @@ func TestIsWriteTool(t *testing.T) {
 		{"get object is not write", ToolGetObject, false},
 		{"list objects is not write", ToolListObjects, false},
 		{"copy object is write", ToolCopyObject, true},
+		{"add connection is write", ToolAddConnection, true},
+		{"remove connection is write", ToolRemoveConnection, true},
+		{"test connection is not write", ToolTestConnection, false},
 		{"unknown tool is not write", ToolName("unknown_tool"), false},
 	}
 
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// objectReaderBlockSize is the number of bytes fetched by each ranged GET
+// that serves a read smaller than a block.
+const objectReaderBlockSize = 256 * 1024
+
+// errReadBudget is returned by an objectReader that has fetched its budget.
+var errReadBudget = errors.New("read byte budget exhausted")
+
+// objectReader reads an object of a known size through ranged GETs, so
+// formats that keep an index at a fixed offset, such as zip central
+// directories, can be read without downloading the whole object. It
+// implements io.ReaderAt for random access and io.ReadSeeker for sequential
+// reads, buffering the last block fetched. It is not safe for concurrent use.
+type objectReader struct {
+	ctx      context.Context
+	s3Client S3Client
+	bucket   string
+	key      string
+	size     int64
+
+	pos    int64
+	buf    []byte
+	bufOff int64
+
+	// bytesRead is the number of bytes fetched from S3.
+	bytesRead int64
+
+	// budget, when positive, caps bytesRead. Reads past it fail with
+	// errReadBudget.
+	budget int64
+}
+
+// newObjectReader returns a reader of the size-byte object at bucket/key.
+func newObjectReader(ctx context.Context, s3Client S3Client, bucket, key string, size int64) *objectReader {
+	return &objectReader{
+		ctx:      ctx,
+		s3Client: s3Client,
+		bucket:   bucket,
+		key:      key,
+		size:     size,
+	}
+}
+
+// Size returns the size of the object.
+func (r *objectReader) Size() int64 {
+	return r.size
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *objectReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	n := 0
+	for n < len(p) {
+		if off >= r.size {
+			return n, io.EOF
+		}
+		if off >= r.bufOff && off < r.bufOff+int64(len(r.buf)) {
+			c := copy(p[n:], r.buf[off-r.bufOff:])
+			n += c
+			off += int64(c)
+			continue
+		}
+		// Reads of a block or more bypass the buffer.
+		if want := len(p) - n; want >= objectReaderBlockSize {
+			data, err := r.fetch(off, int64(want))
+			c := copy(p[n:], data)
+			n += c
+			off += int64(c)
+			if err != nil {
+				return n, err
+			}
+			continue
+		}
+		data, err := r.fetch(off, objectReaderBlockSize)
+		if err != nil {
+			return n, err
+		}
+		r.buf, r.bufOff = data, off
+	}
+	return n, nil
+}
+
+// Read implements io.Reader.
+func (r *objectReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	n, err := r.ReadAt(p, r.pos)
+	r.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (r *objectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+// budgetSpent reports whether reads have stopped at the byte budget.
+func (r *objectReader) budgetSpent() bool {
+	return r.budget > 0 && r.bytesRead >= r.budget
+}
+
+// head returns up to n leading bytes of the object with a single ranged GET
+// and keeps them buffered for the reads that follow.
+func (r *objectReader) head(n int64) ([]byte, error) {
+	if r.size == 0 {
+		return nil, nil
+	}
+	data, err := r.fetch(0, n)
+	if err != nil {
+		return nil, err
+	}
+	r.buf, r.bufOff = data, 0
+	return data, nil
+}
+
+// fetch reads up to length bytes at off, stopping at the end of the object.
+func (r *objectReader) fetch(off, length int64) ([]byte, error) {
+	length = min(length, r.size-off)
+	if r.budget > 0 {
+		if r.bytesRead >= r.budget {
+			return nil, errReadBudget
+		}
+		length = min(length, r.budget-r.bytesRead)
+	}
+	content, err := r.s3Client.GetObjectRange(r.ctx, r.bucket, r.key, off, length)
+	if err != nil {
+		return nil, err
+	}
+	r.bytesRead += int64(len(content.Body))
+	recordBytesRead(r.ctx, int64(len(content.Body)))
+	if int64(len(content.Body)) < length {
+		return content.Body, fmt.Errorf("short read of %s at offset %d: got %d of %d bytes", r.key, off, len(content.Body), length)
+	}
+	return content.Body, nil
+}
@@ -95,6 +95,32 @@ func WithConnectionManager(m ConnectionManager) Option {
 	}
 }
 
+// WithQuotaReporter sets the source of quota usage reported by s3_get_quota.
+// Without a reporter the tool returns an error explaining that quotas are disabled.
+func WithQuotaReporter(r QuotaReporter) Option {
+	return func(t *Toolkit) {
+		t.quotaReporter = r
+	}
+}
+
+// WithHealthChecker sets the health checker whose results s3_list_connections
+// reports for each connection.
+func WithHealthChecker(h HealthChecker) Option {
+	return func(t *Toolkit) {
+		t.healthChecker = h
+	}
+}
+
+// WithConnectionAdmin enables the s3_add_connection, s3_remove_connection, and
+// s3_test_connection tools, which change connections through admin. Without
+// it those tools are not registered. Restrict who may call them with an
+// admin policy interceptor such as extensions.AdminPolicyInterceptor.
+func WithConnectionAdmin(admin ConnectionAdmin) Option {
+	return func(t *Toolkit) {
+		t.connectionAdmin = admin
+	}
+}
+
 // DisableTool disables specific tools from being registered.
 func DisableTool(names ...ToolName) Option {
 	return func(t *Toolkit) {
@@ -108,7 +134,7 @@ func DisableTool(names ...ToolName) Option {
 func EnableOnlyTools(names ...ToolName) Option {
 	return func(t *Toolkit) {
 		// Disable all tools first
-		for _, tool := range AllTools() {
+		for _, tool := range append(AllTools(), AdminTools()...) {
 			t.disabledTools[tool] = true
 		}
 		// Enable only the specified tools
@@ -31,19 +31,7 @@ var defaultOutputSchemas = map[ToolName]any{
 			"bucket":    map[string]any{"type": "string"},
 			"prefix":    map[string]any{"type": "string"},
 			"delimiter": map[string]any{"type": "string"},
-			"objects": map[string]any{
-				"type": "array",
-				"items": map[string]any{
-					"type": "object",
-					"properties": map[string]any{
-						"key":           map[string]any{"type": "string"},
-						"size":          map[string]any{"type": "integer"},
-						"last_modified": map[string]any{"type": "string"},
-						"etag":          map[string]any{"type": "string"},
-						"storage_class": map[string]any{"type": "string"},
-					},
-				},
-			},
+			"objects":   objectListSchema,
 			"common_prefixes": map[string]any{
 				"type":  "array",
 				"items": map[string]any{"type": "string"},
@@ -51,6 +39,11 @@ var defaultOutputSchemas = map[ToolName]any{
 			"count":                   map[string]any{"type": "integer"},
 			"is_truncated":            map[string]any{"type": "boolean"},
 			"next_continuation_token": map[string]any{"type": "string"},
+			"sort_by":                 map[string]any{"type": "string"},
+			"order":                   map[string]any{"type": "string"},
+			"scanned":                 map[string]any{"type": "integer"},
+			"exhaustive":              map[string]any{"type": "boolean"},
+			"served_by":               map[string]any{"type": "string"},
 		},
 	},
 
@@ -67,9 +60,12 @@ var defaultOutputSchemas = map[ToolName]any{
 				"type":                 "object",
 				"additionalProperties": map[string]any{"type": "string"},
 			},
-			"content":   map[string]any{"type": "string"},
-			"is_base64": map[string]any{"type": "boolean"},
-			"truncated": map[string]any{"type": "boolean"},
+			"content":           map[string]any{"type": "string"},
+			"is_base64":         map[string]any{"type": "boolean"},
+			"truncated":         map[string]any{"type": "boolean"},
+			"compression":       map[string]any{"type": "string"},
+			"decompressed_size": map[string]any{"type": "integer"},
+			"served_by":         map[string]any{"type": "string"},
 		},
 	},
 
@@ -87,6 +83,7 @@ var defaultOutputSchemas = map[ToolName]any{
 				"type":                 "object",
 				"additionalProperties": map[string]any{"type": "string"},
 			},
+			"served_by": map[string]any{"type": "string"},
 		},
 	},
 
@@ -98,15 +95,17 @@ var defaultOutputSchemas = map[ToolName]any{
 			"size":       map[string]any{"type": "integer"},
 			"etag":       map[string]any{"type": "string"},
 			"version_id": map[string]any{"type": "string"},
+			"served_by":  map[string]any{"type": "string"},
 		},
 	},
 
 	ToolDeleteObject: map[string]any{
 		"type": "object",
 		"properties": map[string]any{
-			"bucket":  map[string]any{"type": "string"},
-			"key":     map[string]any{"type": "string"},
-			"deleted": map[string]any{"type": "boolean"},
+			"bucket":    map[string]any{"type": "string"},
+			"key":       map[string]any{"type": "string"},
+			"deleted":   map[string]any{"type": "boolean"},
+			"served_by": map[string]any{"type": "string"},
 		},
 	},
 
@@ -120,6 +119,7 @@ var defaultOutputSchemas = map[ToolName]any{
 			"etag":          map[string]any{"type": "string"},
 			"last_modified": map[string]any{"type": "string"},
 			"version_id":    map[string]any{"type": "string"},
+			"served_by":     map[string]any{"type": "string"},
 		},
 	},
 
@@ -135,6 +135,255 @@ var defaultOutputSchemas = map[ToolName]any{
 		},
 	},
 
+	ToolFindObjects: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"bucket":       map[string]any{"type": "string"},
+			"prefix":       map[string]any{"type": "string"},
+			"objects":      objectListSchema,
+			"count":        map[string]any{"type": "integer"},
+			"scanned":      map[string]any{"type": "integer"},
+			"is_truncated": map[string]any{"type": "boolean"},
+			"cursor":       map[string]any{"type": "string"},
+			"served_by":    map[string]any{"type": "string"},
+		},
+	},
+
+	ToolSummarizePrefix: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"bucket":           map[string]any{"type": "string"},
+			"prefix":           map[string]any{"type": "string"},
+			"total_bytes":      map[string]any{"type": "integer"},
+			"object_count":     map[string]any{"type": "integer"},
+			"by_storage_class": usageGroupsSchema,
+			"by_extension":     usageGroupsSchema,
+			"by_sub_prefix":    usageGroupsSchema,
+			"oldest":           objectSchema,
+			"newest":           objectSchema,
+			"largest":          objectListSchema,
+			"is_partial":       map[string]any{"type": "boolean"},
+			"stop_reason":      map[string]any{"type": "string"},
+			"elapsed_ms":       map[string]any{"type": "integer"},
+			"served_by":        map[string]any{"type": "string"},
+		},
+	},
+
+	ToolTree: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"bucket":      map[string]any{"type": "string"},
+			"prefix":      map[string]any{"type": "string"},
+			"depth":       map[string]any{"type": "integer"},
+			"root":        map[string]any{"$ref": "#/$defs/node"},
+			"text":        map[string]any{"type": "string"},
+			"nodes":       map[string]any{"type": "integer"},
+			"scanned":     map[string]any{"type": "integer"},
+			"is_partial":  map[string]any{"type": "boolean"},
+			"stop_reason": map[string]any{"type": "string"},
+			"served_by":   map[string]any{"type": "string"},
+		},
+		"$defs": map[string]any{
+			"node": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":    map[string]any{"type": "string"},
+					"prefix":  map[string]any{"type": "string"},
+					"objects": map[string]any{"type": "integer"},
+					"bytes":   map[string]any{"type": "integer"},
+					"children": map[string]any{
+						"type":  "array",
+						"items": map[string]any{"$ref": "#/$defs/node"},
+					},
+					"truncated": map[string]any{"type": "boolean"},
+				},
+			},
+		},
+	},
+
+	ToolGrep: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"bucket":  map[string]any{"type": "string"},
+			"prefix":  map[string]any{"type": "string"},
+			"pattern": map[string]any{"type": "string"},
+			"matches": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key":  map[string]any{"type": "string"},
+						"line": map[string]any{"type": "integer"},
+						"text": map[string]any{"type": "string"},
+						"before": map[string]any{
+							"type":  "array",
+							"items": map[string]any{"type": "string"},
+						},
+						"after": map[string]any{
+							"type":  "array",
+							"items": map[string]any{"type": "string"},
+						},
+					},
+				},
+			},
+			"count":            map[string]any{"type": "integer"},
+			"objects_scanned":  map[string]any{"type": "integer"},
+			"objects_searched": map[string]any{"type": "integer"},
+			"objects_matched":  map[string]any{"type": "integer"},
+			"bytes_read":       map[string]any{"type": "integer"},
+			"skipped": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key":    map[string]any{"type": "string"},
+						"reason": map[string]any{"type": "string"},
+						"detail": map[string]any{"type": "string"},
+					},
+				},
+			},
+			"skipped_count": map[string]any{"type": "integer"},
+			"is_truncated":  map[string]any{"type": "boolean"},
+			"stop_reason":   map[string]any{"type": "string"},
+			"served_by":     map[string]any{"type": "string"},
+		},
+	},
+
+	ToolListArchive: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"bucket":      map[string]any{"type": "string"},
+			"key":         map[string]any{"type": "string"},
+			"format":      map[string]any{"type": "string"},
+			"compression": map[string]any{"type": "string"},
+			"size":        map[string]any{"type": "integer"},
+			"entries": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name":            map[string]any{"type": "string"},
+						"size":            map[string]any{"type": "integer"},
+						"compressed_size": map[string]any{"type": "integer"},
+						"modified":        map[string]any{"type": "string"},
+						"is_dir":          map[string]any{"type": "boolean"},
+					},
+				},
+			},
+			"count":        map[string]any{"type": "integer"},
+			"total_size":   map[string]any{"type": "integer"},
+			"bytes_read":   map[string]any{"type": "integer"},
+			"is_truncated": map[string]any{"type": "boolean"},
+			"stop_reason":  map[string]any{"type": "string"},
+			"served_by":    map[string]any{"type": "string"},
+		},
+	},
+
+	ToolGetArchiveEntry: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"bucket":        map[string]any{"type": "string"},
+			"key":           map[string]any{"type": "string"},
+			"entry":         map[string]any{"type": "string"},
+			"format":        map[string]any{"type": "string"},
+			"size":          map[string]any{"type": "integer"},
+			"content_type":  map[string]any{"type": "string"},
+			"last_modified": map[string]any{"type": "string"},
+			"content":       map[string]any{"type": "string"},
+			"is_base64":     map[string]any{"type": "boolean"},
+			"bytes_read":    map[string]any{"type": "integer"},
+			"served_by":     map[string]any{"type": "string"},
+		},
+	},
+
+	ToolInspectParquet: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"bucket":     map[string]any{"type": "string"},
+			"key":        map[string]any{"type": "string"},
+			"size":       map[string]any{"type": "integer"},
+			"num_rows":   map[string]any{"type": "integer"},
+			"created_by": map[string]any{"type": "string"},
+			"schema":     map[string]any{"type": "string"},
+			"columns": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"path":              map[string]any{"type": "string"},
+						"physical_type":     map[string]any{"type": "string"},
+						"logical_type":      map[string]any{"type": "string"},
+						"repetition":        map[string]any{"type": "string"},
+						"compression":       map[string]any{"type": "string"},
+						"null_count":        map[string]any{"type": "integer"},
+						"min":               map[string]any{},
+						"max":               map[string]any{},
+						"compressed_size":   map[string]any{"type": "integer"},
+						"uncompressed_size": map[string]any{"type": "integer"},
+					},
+				},
+			},
+			"row_group_count": map[string]any{"type": "integer"},
+			"row_groups": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"rows":              map[string]any{"type": "integer"},
+						"compressed_size":   map[string]any{"type": "integer"},
+						"uncompressed_size": map[string]any{"type": "integer"},
+					},
+				},
+			},
+			"metadata": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "string"},
+			},
+			"rows": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "object"},
+			},
+			"bytes_read":   map[string]any{"type": "integer"},
+			"is_truncated": map[string]any{"type": "boolean"},
+			"stop_reason":  map[string]any{"type": "string"},
+			"served_by":    map[string]any{"type": "string"},
+		},
+	},
+
+	ToolPreviewTable: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"bucket":      map[string]any{"type": "string"},
+			"key":         map[string]any{"type": "string"},
+			"size":        map[string]any{"type": "integer"},
+			"compression": map[string]any{"type": "string"},
+			"delimiter":   map[string]any{"type": "string"},
+			"quote":       map[string]any{"type": "string"},
+			"has_header":  map[string]any{"type": "boolean"},
+			"columns": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name":     map[string]any{"type": "string"},
+						"type":     map[string]any{"type": "string"},
+						"nullable": map[string]any{"type": "boolean"},
+					},
+				},
+			},
+			"schema": map[string]any{"type": "object"},
+			"rows": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "object"},
+			},
+			"rows_scanned": map[string]any{"type": "integer"},
+			"bytes_read":   map[string]any{"type": "integer"},
+			"is_truncated": map[string]any{"type": "boolean"},
+			"stop_reason":  map[string]any{"type": "string"},
+			"served_by":    map[string]any{"type": "string"},
+		},
+	},
+
 	ToolListConnections: map[string]any{
 		"type": "object",
 		"properties": map[string]any{
@@ -143,9 +392,42 @@ var defaultOutputSchemas = map[ToolName]any{
 				"items": map[string]any{
 					"type": "object",
 					"properties": map[string]any{
-						"name":     map[string]any{"type": "string"},
-						"region":   map[string]any{"type": "string"},
-						"endpoint": map[string]any{"type": "string"},
+						"name":      map[string]any{"type": "string"},
+						"region":    map[string]any{"type": "string"},
+						"endpoint":  map[string]any{"type": "string"},
+						"read_only": map[string]any{"type": "boolean"},
+						"circuit": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"state":                map[string]any{"type": "string"},
+								"consecutive_failures": map[string]any{"type": "integer"},
+								"retry_at":             map[string]any{"type": "string"},
+							},
+						},
+						"credentials": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"source":     map[string]any{"type": "string"},
+								"temporary":  map[string]any{"type": "boolean"},
+								"expires_at": map[string]any{"type": "string"},
+								"expired":    map[string]any{"type": "boolean"},
+							},
+						},
+						"health": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"status":        map[string]any{"type": "string"},
+								"initialized":   map[string]any{"type": "boolean"},
+								"latency_ms":    map[string]any{"type": "integer"},
+								"checked_at":    map[string]any{"type": "string"},
+								"last_error":    map[string]any{"type": "string"},
+								"last_error_at": map[string]any{"type": "string"},
+							},
+						},
+						"unsupported": map[string]any{
+							"type":  "array",
+							"items": map[string]any{"type": "string"},
+						},
 					},
 				},
 			},
@@ -153,6 +435,95 @@ var defaultOutputSchemas = map[ToolName]any{
 			"count":              map[string]any{"type": "integer"},
 		},
 	},
+
+	ToolAddConnection:    connectionAdminSchema,
+	ToolRemoveConnection: connectionAdminSchema,
+	ToolTestConnection:   connectionAdminSchema,
+
+	ToolGetQuota: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"session_id": map[string]any{"type": "string"},
+			"principal":  map[string]any{"type": "string"},
+			"connection": map[string]any{"type": "string"},
+			"quotas": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"scope":               map[string]any{"type": "string"},
+						"key":                 map[string]any{"type": "string"},
+						"window_seconds":      map[string]any{"type": "integer"},
+						"requests":            quotaCounterSchema,
+						"bytes_read":          quotaCounterSchema,
+						"bytes_written":       quotaCounterSchema,
+						"exhausted":           map[string]any{"type": "boolean"},
+						"retry_after_seconds": map[string]any{"type": "integer"},
+					},
+				},
+			},
+		},
+	},
+}
+
+// objectSchema is the schema of a listed object.
+var objectSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"key":           map[string]any{"type": "string"},
+		"size":          map[string]any{"type": "integer"},
+		"last_modified": map[string]any{"type": "string"},
+		"etag":          map[string]any{"type": "string"},
+		"storage_class": map[string]any{"type": "string"},
+	},
+}
+
+// objectListSchema is the schema shared by tools that return listed objects.
+var objectListSchema = map[string]any{
+	"type":  "array",
+	"items": objectSchema,
+}
+
+// usageGroupsSchema is the schema shared by the breakdowns in a prefix summary.
+var usageGroupsSchema = map[string]any{
+	"type": "array",
+	"items": map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":    map[string]any{"type": "string"},
+			"objects": map[string]any{"type": "integer"},
+			"bytes":   map[string]any{"type": "integer"},
+		},
+	},
+}
+
+// quotaCounterSchema is the schema shared by the counters in a quota usage entry.
+var quotaCounterSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"used":  map[string]any{"type": "integer"},
+		"limit": map[string]any{"type": "integer"},
+	},
+}
+
+// connectionAdminSchema is the output schema shared by the connection admin tools.
+var connectionAdminSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"connection": map[string]any{"type": "string"},
+		"action":     map[string]any{"type": "string"},
+		"persisted":  map[string]any{"type": "boolean"},
+		"health": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"status":      map[string]any{"type": "string"},
+				"initialized": map[string]any{"type": "boolean"},
+				"latency_ms":  map[string]any{"type": "integer"},
+				"checked_at":  map[string]any{"type": "string"},
+				"last_error":  map[string]any{"type": "string"},
+			},
+		},
+	},
 }
 
 // DefaultOutputSchema returns the default JSON Schema for a tool's structured output.
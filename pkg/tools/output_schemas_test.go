@@ -21,7 +21,7 @@ func TestDefaultOutputSchema(t *testing.T) {
 	})
 
 	t.Run("all tools have defaults", func(t *testing.T) {
-		for _, name := range AllTools() {
+		for _, name := range append(AllTools(), AdminTools()...) {
 			schema := DefaultOutputSchema(name)
 			if schema == nil {
 				t.Errorf("tool %s has no default output schema", name)
@@ -30,7 +30,7 @@ func TestDefaultOutputSchema(t *testing.T) {
 	})
 
 	t.Run("schema is map[string]any with type object", func(t *testing.T) {
-		for _, name := range AllTools() {
+		for _, name := range append(AllTools(), AdminTools()...) {
 			schema := DefaultOutputSchema(name)
 			m, ok := schema.(map[string]any)
 			if !ok {
@@ -0,0 +1,437 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// Limits for s3_inspect_parquet.
+const (
+	maxParquetRows = 100
+
+	// maxParquetRowGroups caps the row groups listed in a result.
+	maxParquetRowGroups = 100
+
+	// defaultParquetReadBytes is the read budget when the connection has no
+	// retrieval size limit.
+	defaultParquetReadBytes = 64 * 1024 * 1024
+
+	// maxParquetStatLen is the longest string statistic returned in full.
+	maxParquetStatLen = 100
+
+	// maxParquetMetadataLen is the longest key-value metadata entry returned
+	// in full. Writers such as Arrow store their whole schema there.
+	maxParquetMetadataLen = 1000
+)
+
+// ParquetColumn describes a leaf column of a Parquet file, with statistics
+// combined across all row groups.
+type ParquetColumn struct {
+	Path             string `json:"path"`
+	PhysicalType     string `json:"physical_type"`
+	LogicalType      string `json:"logical_type,omitempty"`
+	Repetition       string `json:"repetition"`
+	Compression      string `json:"compression,omitempty"`
+	NullCount        int64  `json:"null_count"`
+	Min              any    `json:"min,omitempty"`
+	Max              any    `json:"max,omitempty"`
+	CompressedSize   int64  `json:"compressed_size"`
+	UncompressedSize int64  `json:"uncompressed_size"`
+}
+
+// ParquetRowGroup describes a row group of a Parquet file.
+type ParquetRowGroup struct {
+	Rows             int64 `json:"rows"`
+	CompressedSize   int64 `json:"compressed_size"`
+	UncompressedSize int64 `json:"uncompressed_size"`
+}
+
+// InspectParquetResult represents the result of inspecting a Parquet file.
+type InspectParquetResult struct {
+	Bucket        string            `json:"bucket"`
+	Key           string            `json:"key"`
+	Size          int64             `json:"size"`
+	NumRows       int64             `json:"num_rows"`
+	CreatedBy     string            `json:"created_by,omitempty"`
+	Schema        string            `json:"schema"`
+	Columns       []ParquetColumn   `json:"columns"`
+	RowGroupCount int               `json:"row_group_count"`
+	RowGroups     []ParquetRowGroup `json:"row_groups"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Rows          []map[string]any  `json:"rows,omitempty"`
+	BytesRead     int64             `json:"bytes_read"`
+	IsTruncated   bool              `json:"is_truncated"`
+	StopReason    string            `json:"stop_reason,omitempty"`
+	ServedBy      string            `json:"served_by,omitempty"`
+}
+
+// registerInspectParquetTool registers the s3_inspect_parquet tool.
+func (t *Toolkit) registerInspectParquetTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		inspectInput, ok := input.(InspectParquetInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleInspectParquet(ctx, req, inspectInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolInspectParquet, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolInspectParquet),
+		Title:        t.getTitle(ToolInspectParquet, cfg),
+		Description:  t.getDescription(ToolInspectParquet, cfg),
+		Annotations:  t.getAnnotations(ToolInspectParquet, cfg),
+		Icons:        t.getIcons(ToolInspectParquet, cfg),
+		OutputSchema: t.getOutputSchema(ToolInspectParquet, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input InspectParquetInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[InspectParquetResult](out), err
+	})
+}
+
+// handleInspectParquet handles the s3_inspect_parquet tool request.
+func (t *Toolkit) handleInspectParquet(ctx context.Context, _ *mcp.CallToolRequest, input InspectParquetInput) (*mcp.CallToolResult, any, error) {
+	if input.Bucket == "" {
+		return ErrorResult("bucket parameter is required"), nil, nil
+	}
+	if input.Key == "" {
+		return ErrorResult("key parameter is required"), nil, nil
+	}
+	rows := min(max(input.Rows, 0), maxParquetRows)
+
+	s3Client, release, err := t.holdClient(input.Connection)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	defer release()
+	guard := t.guardFor(s3Client)
+	if err := guard.checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	ctx, servedBy := withServedBy(ctx)
+	meta, err := s3Client.GetObjectMetadata(ctx, input.Bucket, input.Key)
+	if err != nil {
+		return S3ErrorResult("get object metadata", err), nil, nil
+	}
+	obj := newObjectReader(ctx, s3Client, input.Bucket, input.Key, meta.Size)
+	obj.budget = defaultParquetReadBytes
+	if guard.maxGetSize > 0 {
+		obj.budget = guard.maxGetSize
+	}
+
+	result, err := inspectParquet(obj, rows)
+	if err != nil {
+		if errors.Is(err, errReadBudget) {
+			return ErrorResultf("parquet footer is larger than the read limit of %d bytes", obj.budget), nil, nil
+		}
+		return ErrorResultFor(fmt.Errorf("failed to read parquet file: %w", err)), nil, nil
+	}
+	result.Bucket = input.Bucket
+	result.Key = input.Key
+	result.BytesRead = obj.bytesRead
+	result.ServedBy = servedBy()
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, result, nil
+}
+
+// inspectParquet reads the footer of the Parquet file behind obj and, when
+// rows is positive, decodes up to that many rows. Rows that would read past
+// the budget of obj are left out and the result is marked truncated. The
+// decoder can panic on corrupt files, so panics are returned as errors.
+func inspectParquet(obj *objectReader, rows int) (result *InspectParquetResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("invalid parquet file: %v", r)
+		}
+	}()
+
+	f, err := parquet.OpenFile(obj, obj.Size(),
+		parquet.SkipMagicBytes(true),
+		parquet.SkipPageIndex(true),
+		parquet.SkipBloomFilters(true),
+		parquet.ReadBufferSize(objectReaderBlockSize),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	md := f.Metadata()
+	result = &InspectParquetResult{
+		Size:          obj.Size(),
+		NumRows:       f.NumRows(),
+		CreatedBy:     md.CreatedBy,
+		Schema:        f.Schema().String(),
+		Columns:       parquetColumns(f),
+		RowGroupCount: len(md.RowGroups),
+		RowGroups:     make([]ParquetRowGroup, 0, min(len(md.RowGroups), maxParquetRowGroups)),
+	}
+	for _, rg := range md.RowGroups[:min(len(md.RowGroups), maxParquetRowGroups)] {
+		group := ParquetRowGroup{Rows: rg.NumRows, UncompressedSize: rg.TotalByteSize}
+		for _, chunk := range rg.Columns {
+			group.CompressedSize += chunk.MetaData.TotalCompressedSize
+		}
+		result.RowGroups = append(result.RowGroups, group)
+	}
+	for _, kv := range md.KeyValueMetadata {
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]string)
+		}
+		result.Metadata[kv.Key] = truncateString(kv.Value, maxParquetMetadataLen)
+	}
+
+	if rows > 0 {
+		result.Rows, err = readParquetRows(f, rows)
+		switch {
+		case err != nil && (errors.Is(err, errReadBudget) || obj.budgetSpent()):
+			result.Rows = nil
+			result.IsTruncated = true
+			result.StopReason = StopReasonByteBudget
+		case err != nil:
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// parquetColumns describes the leaf columns of f, combining the statistics
+// of each column chunk.
+func parquetColumns(f *parquet.File) []ParquetColumn {
+	schema := f.Schema()
+	paths := schema.Columns()
+	columns := make([]ParquetColumn, len(paths))
+	mins := make([]parquet.Value, len(paths))
+	maxes := make([]parquet.Value, len(paths))
+	types := make([]parquet.Type, len(paths))
+
+	for i, path := range paths {
+		leaf, _ := schema.Lookup(path...)
+		typ := leaf.Node.Type()
+		types[i] = typ
+		columns[i] = ParquetColumn{
+			Path:         strings.Join(path, "."),
+			PhysicalType: typ.Kind().String(),
+			Repetition:   parquetRepetition(leaf.Node),
+		}
+		if logical := typ.String(); logical != columns[i].PhysicalType {
+			columns[i].LogicalType = logical
+		}
+	}
+
+	for _, rg := range f.Metadata().RowGroups {
+		for i, chunk := range rg.Columns {
+			if i >= len(columns) {
+				break
+			}
+			m := chunk.MetaData
+			col := &columns[i]
+			if col.Compression == "" {
+				col.Compression = m.Codec.String()
+			}
+			col.CompressedSize += m.TotalCompressedSize
+			col.UncompressedSize += m.TotalUncompressedSize
+			col.NullCount += m.Statistics.NullCount
+
+			minValue, maxValue := m.Statistics.MinValue, m.Statistics.MaxValue
+			if minValue == nil && maxValue == nil {
+				minValue, maxValue = m.Statistics.Min, m.Statistics.Max
+			}
+			kind := types[i].Kind()
+			if minValue != nil {
+				if v := kind.Value(minValue); mins[i].IsNull() || types[i].Compare(v, mins[i]) < 0 {
+					mins[i] = v
+				}
+			}
+			if maxValue != nil {
+				if v := kind.Value(maxValue); maxes[i].IsNull() || types[i].Compare(v, maxes[i]) > 0 {
+					maxes[i] = v
+				}
+			}
+		}
+	}
+
+	for i := range columns {
+		if !mins[i].IsNull() {
+			columns[i].Min = parquetStatValue(types[i], mins[i])
+		}
+		if !maxes[i].IsNull() {
+			columns[i].Max = parquetStatValue(types[i], maxes[i])
+		}
+	}
+	return columns
+}
+
+// parquetRepetition returns the repetition of a schema node.
+func parquetRepetition(node parquet.Node) string {
+	switch {
+	case node.Repeated():
+		return "repeated"
+	case node.Optional():
+		return "optional"
+	}
+	return "required"
+}
+
+// parquetStatValue returns a column statistic as a JSON value.
+func parquetStatValue(typ parquet.Type, v parquet.Value) any {
+	switch v.Kind() {
+	case parquet.Boolean:
+		return v.Boolean()
+	case parquet.Int32:
+		return parquetLeafValue(typ, v.Int32())
+	case parquet.Int64:
+		return parquetLeafValue(typ, v.Int64())
+	case parquet.Float:
+		return parquetLeafValue(typ, v.Float())
+	case parquet.Double:
+		return parquetLeafValue(typ, v.Double())
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		b := v.ByteArray()
+		if utf8.Valid(b) {
+			return truncateString(string(b), maxParquetStatLen)
+		}
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return v.String()
+}
+
+// readParquetRows decodes up to n rows of f into JSON values.
+func readParquetRows(f *parquet.File, n int) ([]map[string]any, error) {
+	schema := f.Schema()
+	out := make([]map[string]any, 0, n)
+	buf := make([]parquet.Row, n)
+	for _, rg := range f.RowGroups() {
+		if len(out) >= n {
+			break
+		}
+		rows := rg.Rows()
+		count, err := rows.ReadRows(buf[:n-len(out)])
+		closeErr := rows.Close()
+		for _, row := range buf[:count] {
+			m := make(map[string]any)
+			if err := schema.Reconstruct(&m, row); err != nil {
+				return nil, err
+			}
+			out = append(out, parquetGroupValue(schema, m))
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if closeErr != nil {
+			return nil, closeErr
+		}
+	}
+	return out, nil
+}
+
+// parquetGroupValue converts the fields of a decoded group to JSON values.
+func parquetGroupValue(node parquet.Node, m map[string]any) map[string]any {
+	for _, field := range node.Fields() {
+		if v, ok := m[field.Name()]; ok {
+			m[field.Name()] = parquetRowValue(field, v)
+		}
+	}
+	return m
+}
+
+// parquetRowValue converts a decoded value of node to a JSON value:
+// timestamps and dates become strings and floats that JSON cannot hold
+// become strings. Lists and maps are left as decoded.
+func parquetRowValue(node parquet.Node, v any) any {
+	if v == nil {
+		return nil
+	}
+	if !node.Leaf() {
+		if m, ok := v.(map[string]any); ok {
+			return parquetGroupValue(node, m)
+		}
+		return jsonSafe(v)
+	}
+	switch x := v.(type) {
+	case int32:
+		return parquetLeafValue(node.Type(), x)
+	case int64:
+		return parquetLeafValue(node.Type(), x)
+	case float32:
+		return parquetLeafValue(node.Type(), x)
+	case float64:
+		return parquetLeafValue(node.Type(), x)
+	}
+	return v
+}
+
+// parquetLeafValue formats a numeric value of typ, writing timestamps and
+// dates as strings.
+func parquetLeafValue[T int32 | int64 | float32 | float64](typ parquet.Type, v T) any {
+	if lt := typ.LogicalType(); lt != nil {
+		switch logical := lt.Value.(type) {
+		case *format.TimestampType:
+			if logical.Unit.Value != nil {
+				d := logical.Unit.Value.Duration()
+				return time.Unix(0, 0).Add(time.Duration(int64(v)) * d).UTC().Format(time.RFC3339Nano)
+			}
+		case *format.DateType:
+			return time.Unix(int64(v)*86400, 0).UTC().Format(time.DateOnly)
+		}
+	}
+	switch x := any(v).(type) {
+	case float32:
+		return jsonFloat(float64(x))
+	case float64:
+		return jsonFloat(x)
+	}
+	return v
+}
+
+// jsonFloat returns f, or its string form when JSON cannot hold it.
+func jsonFloat(f float64) any {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Sprint(f)
+	}
+	return f
+}
+
+// jsonSafe replaces floats that JSON cannot hold with strings in a decoded
+// list or map.
+func jsonSafe(v any) any {
+	switch x := v.(type) {
+	case float32:
+		return jsonFloat(float64(x))
+	case float64:
+		return jsonFloat(x)
+	case []any:
+		for i := range x {
+			x[i] = jsonSafe(x[i])
+		}
+	case map[string]any:
+		for k := range x {
+			x[k] = jsonSafe(x[k])
+		}
+	}
+	return v
+}
+
+// truncateString shortens s to at most n bytes on a rune boundary, marking
+// the cut with "...".
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "") + "..."
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type parquetTestRow struct {
+	ID      int64     `parquet:"id"`
+	Name    string    `parquet:"name"`
+	Score   float64   `parquet:"score"`
+	At      time.Time `parquet:"at,timestamp(millisecond)"`
+	Tags    []string  `parquet:"tags,list"`
+	Note    *string   `parquet:"note,optional"`
+	Payload string    `parquet:"payload"`
+}
+
+// parquetBytes writes n rows in row groups of groupSize rows. Each row has a
+// random-looking payload so that the file is much larger than its footer.
+func parquetBytes(t *testing.T, n, groupSize int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[parquetTestRow](&buf,
+		parquet.Compression(&parquet.Snappy),
+		parquet.MaxRowsPerRowGroup(int64(groupSize)),
+	)
+	note := "first"
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := range n {
+		row := parquetTestRow{
+			ID:      int64(i + 1),
+			Name:    fmt.Sprintf("user-%03d", i+1),
+			Score:   float64(i) / 2,
+			At:      start.Add(time.Duration(i) * time.Hour),
+			Tags:    []string{"a", "b"},
+			Payload: strings.Repeat(fmt.Sprintf("%08x", uint32(i)*2654435761), 512),
+		}
+		if i == 0 {
+			row.Note = &note
+		}
+		if _, err := w.Write([]parquetTestRow{row}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestInspectParquet(t *testing.T) {
+	data := parquetBytes(t, 300, 100)
+	mock := newContentMock(map[string][]byte{
+		"lake/events.parquet": data,
+		"notes.txt":           []byte("not parquet"),
+	})
+	toolkit := NewToolkit(mock)
+
+	inspect := func(input InspectParquetInput) *InspectParquetResult {
+		t.Helper()
+		input.Bucket = "bucket"
+		result, out, _ := toolkit.handleInspectParquet(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error: %v", result.Content)
+		}
+		return out.(*InspectParquetResult)
+	}
+
+	got := inspect(InspectParquetInput{Key: "lake/events.parquet"})
+	if got.NumRows != 300 || got.RowGroupCount != 3 || len(got.RowGroups) != 3 || got.RowGroups[0].Rows != 100 {
+		t.Errorf("rows = %d row groups = %d %+v", got.NumRows, got.RowGroupCount, got.RowGroups)
+	}
+	if !strings.Contains(got.Schema, "optional binary note (STRING)") {
+		t.Errorf("schema = %s", got.Schema)
+	}
+	if got.BytesRead >= got.Size/2 || len(got.Rows) != 0 {
+		t.Errorf("read %d of %d bytes without rows, rows = %d", got.BytesRead, got.Size, len(got.Rows))
+	}
+
+	columns := make(map[string]ParquetColumn)
+	for _, c := range got.Columns {
+		columns[c.Path] = c
+	}
+	if c := columns["id"]; c.PhysicalType != "INT64" || c.Repetition != "required" || c.Compression != "SNAPPY" || c.Min != int64(1) || c.Max != int64(300) {
+		t.Errorf("id column = %+v", c)
+	}
+	if c := columns["name"]; c.LogicalType != "STRING" || c.Min != "user-001" || c.Max != "user-300" {
+		t.Errorf("name column = %+v", c)
+	}
+	if c := columns["at"]; c.Min != "2024-01-02T03:04:05Z" || c.Max != "2024-01-14T14:04:05Z" {
+		t.Errorf("at column = %+v", c)
+	}
+	if c := columns["note"]; c.Repetition != "optional" || c.NullCount != 299 {
+		t.Errorf("note column = %+v", c)
+	}
+	if _, ok := columns["tags.list.element"]; !ok {
+		t.Errorf("missing nested column in %v", got.Columns)
+	}
+
+	got = inspect(InspectParquetInput{Key: "lake/events.parquet", Rows: 2})
+	if len(got.Rows) != 2 || got.IsTruncated {
+		t.Fatalf("rows = %v truncated = %v", got.Rows, got.IsTruncated)
+	}
+	first := got.Rows[0]
+	if first["id"] != int64(1) || first["name"] != "user-001" || first["at"] != "2024-01-02T03:04:05Z" || first["note"] != "first" {
+		t.Errorf("first row = %v", first)
+	}
+	if got.Rows[1]["note"] != nil {
+		t.Errorf("second row note = %v", got.Rows[1]["note"])
+	}
+
+	for _, input := range []InspectParquetInput{
+		{Bucket: "bucket"},
+		{Bucket: "bucket", Key: "notes.txt"},
+		{Bucket: "bucket", Key: "missing.parquet"},
+	} {
+		if result, _, _ := toolkit.handleInspectParquet(context.Background(), nil, input); !result.IsError {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
+
+func TestInspectParquet_ReadBudget(t *testing.T) {
+	data := parquetBytes(t, 100, 100)
+	mock := newContentMock(map[string][]byte{"events.parquet": data})
+	toolkit := NewToolkit(mock, WithMaxGetSize(16*1024))
+
+	result, out, _ := toolkit.handleInspectParquet(context.Background(), nil, InspectParquetInput{Bucket: "bucket", Key: "events.parquet", Rows: 5})
+	if result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	got := out.(*InspectParquetResult)
+	if got.NumRows != 100 || len(got.Rows) != 0 || !got.IsTruncated || got.StopReason != StopReasonByteBudget {
+		t.Errorf("rows = %d truncated = %v reason = %q", len(got.Rows), got.IsTruncated, got.StopReason)
+	}
+}
+
+func TestParquetLeafValue(t *testing.T) {
+	if got := parquetLeafValue(parquet.DoubleType, math.NaN()); got != "NaN" {
+		t.Errorf("NaN = %v", got)
+	}
+	if got := parquetLeafValue(parquet.Date().Type(), int32(19724)); got != "2024-01-02" {
+		t.Errorf("date = %v", got)
+	}
+	if got := jsonSafe([]any{math.Inf(1), 1.5}); fmt.Sprint(got) != "[+Inf 1.5]" {
+		t.Errorf("list = %v", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
 )
 
 const (
@@ -42,12 +44,9 @@ func (t *Toolkit) registerPresignURLTool(server *mcp.Server, cfg *toolConfig) {
 		Annotations:  t.getAnnotations(ToolPresignURL, cfg),
 		Icons:        t.getIcons(ToolPresignURL, cfg),
 		OutputSchema: t.getOutputSchema(ToolPresignURL, cfg),
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input PresignURLInput) (*mcp.CallToolResult, *PresignURLResult, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input PresignURLInput) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*PresignURLResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[PresignURLResult](out), err
 	})
 }
 
@@ -74,15 +73,31 @@ func (t *Toolkit) handlePresignURL(ctx context.Context, _ *mcp.CallToolRequest,
 	expiresIn := clampExpiration(input.ExpiresIn)
 
 	// Get client
-	s3Client, err := t.GetClient(input.Connection)
+	s3Client, release, err := t.holdClient(input.Connection)
 	if err != nil {
 		return ErrorResult(err.Error()), nil, nil
 	}
+	defer release()
+
+	// Check the connection's guardrails; an upload URL is a write
+	guard := t.guardFor(s3Client)
+	if method == methodPut {
+		if err := guard.checkWrite(); err != nil {
+			return ErrorResultFor(err), nil, nil
+		}
+	}
+	if err := guard.checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	if err := t.requireFeature(ctx, s3Client, client.FeaturePresign); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
 
 	// Generate presigned URL
 	presigned, err := generatePresignedURL(ctx, s3Client, input.Bucket, input.Key, method, expiresIn)
 	if err != nil {
-		return ErrorResultf("failed to generate presigned URL: %v", err), nil, nil
+		return S3ErrorResult("generate presigned URL", err), nil, nil
 	}
 
 	// Build result
@@ -17,6 +17,7 @@ type PutObjectResult struct {
 	Size      int64  `json:"size"`
 	ETag      string `json:"etag,omitempty"`
 	VersionID string `json:"version_id,omitempty"`
+	ServedBy  string `json:"served_by,omitempty"`
 }
 
 // registerPutObjectTool registers the s3_put_object tool.
@@ -38,12 +39,9 @@ func (t *Toolkit) registerPutObjectTool(server *mcp.Server, cfg *toolConfig) {
 		Annotations:  t.getAnnotations(ToolPutObject, cfg),
 		Icons:        t.getIcons(ToolPutObject, cfg),
 		OutputSchema: t.getOutputSchema(ToolPutObject, cfg),
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input PutObjectInput) (*mcp.CallToolResult, *PutObjectResult, error) {
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input PutObjectInput) (*mcp.CallToolResult, any, error) {
 		result, out, err := wrappedHandler(ctx, req, input)
-		if typed, ok := out.(*PutObjectResult); ok {
-			return result, typed, err
-		}
-		return result, nil, err
+		return result, typedOutput[PutObjectResult](out), err
 	})
 }
 
@@ -53,16 +51,26 @@ func (t *Toolkit) handlePutObject(ctx context.Context, _ *mcp.CallToolRequest, i
 		return errResult, nil, nil
 	}
 
-	body, errResult := t.preparePutBody(input)
-	if errResult != nil {
-		return errResult, nil, nil
-	}
-
-	s3Client, err := t.GetClient(input.Connection)
+	s3Client, release, err := t.holdClient(input.Connection)
 	if err != nil {
 		return ErrorResult(err.Error()), nil, nil
 	}
+	defer release()
+
+	guard := t.guardFor(s3Client)
+	if err := guard.checkWrite(); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+	if err := guard.checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	body, errResult := t.preparePutBody(s3Client, input)
+	if errResult != nil {
+		return errResult, nil, nil
+	}
 
+	ctx, servedBy := withServedBy(ctx)
 	output, err := s3Client.PutObject(ctx, &client.PutObjectInput{
 		Bucket:      input.Bucket,
 		Key:         input.Key,
@@ -71,15 +79,16 @@ func (t *Toolkit) handlePutObject(ctx context.Context, _ *mcp.CallToolRequest, i
 		Metadata:    input.Metadata,
 	})
 	if err != nil {
-		return ErrorResultf("failed to put object: %v", err), nil, nil
+		return S3ErrorResult("put object", err), nil, nil
 	}
+	recordBytesWritten(ctx, int64(len(body)))
 
-	return t.buildPutResult(input, body, output)
+	return t.buildPutResult(input, body, output, servedBy())
 }
 
 func (t *Toolkit) validatePutInput(input PutObjectInput) *mcp.CallToolResult {
 	if t.readOnly {
-		return ErrorResult(ErrReadOnly.Error())
+		return ErrorResultFor(ErrReadOnly)
 	}
 	if input.Bucket == "" {
 		return ErrorResult("bucket parameter is required")
@@ -93,13 +102,13 @@ func (t *Toolkit) validatePutInput(input PutObjectInput) *mcp.CallToolResult {
 	return nil
 }
 
-func (t *Toolkit) preparePutBody(input PutObjectInput) ([]byte, *mcp.CallToolResult) {
+func (t *Toolkit) preparePutBody(s3Client S3Client, input PutObjectInput) ([]byte, *mcp.CallToolResult) {
 	body, err := decodeContent(input.Content, input.IsBase64)
 	if err != nil {
 		return nil, ErrorResultf("failed to decode base64 content: %v", err)
 	}
-	if err := t.checkPutSizeLimit(body); err != nil {
-		return nil, ErrorResult(err.Error())
+	if err := t.checkPutSizeLimit(s3Client, body); err != nil {
+		return nil, ErrorResultFor(err)
 	}
 	return body, nil
 }
@@ -111,13 +120,16 @@ func defaultContentType(contentType string) string {
 	return contentType
 }
 
-func (t *Toolkit) buildPutResult(input PutObjectInput, body []byte, output *client.PutObjectOutput) (*mcp.CallToolResult, any, error) {
+func (t *Toolkit) buildPutResult(
+	input PutObjectInput, body []byte, output *client.PutObjectOutput, servedBy string,
+) (*mcp.CallToolResult, any, error) {
 	result := PutObjectResult{
 		Bucket:    input.Bucket,
 		Key:       input.Key,
 		Size:      int64(len(body)),
 		ETag:      output.ETag,
 		VersionID: output.VersionID,
+		ServedBy:  servedBy,
 	}
 	jsonResult, err := JSONResult(result)
 	if err != nil {
@@ -133,9 +145,12 @@ func decodeContent(content string, isBase64 bool) ([]byte, error) {
 	return []byte(content), nil
 }
 
-func (t *Toolkit) checkPutSizeLimit(body []byte) error {
-	if t.maxPutSize > 0 && int64(len(body)) > t.maxPutSize {
-		return fmt.Errorf("%w: content size %d bytes exceeds limit of %d bytes", ErrSizeLimitExceeded, len(body), t.maxPutSize)
+// checkPutSizeLimit returns an error if body is larger than the upload limit
+// of the connection behind s3Client.
+func (t *Toolkit) checkPutSizeLimit(s3Client S3Client, body []byte) error {
+	maxPutSize := t.guardFor(s3Client).maxPutSize
+	if maxPutSize > 0 && int64(len(body)) > maxPutSize {
+		return fmt.Errorf("%w: content size %d bytes exceeds limit of %d bytes", ErrSizeLimitExceeded, len(body), maxPutSize)
 	}
 	return nil
 }
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Quota scopes reported by QuotaUsage.Scope.
+const (
+	QuotaScopeSession    = "session"
+	QuotaScopePrincipal  = "principal"
+	QuotaScopeConnection = "connection"
+)
+
+// QuotaSubject identifies whose quota usage is being queried or charged.
+// Empty fields are not tracked.
+type QuotaSubject struct {
+	SessionID  string
+	Principal  string
+	Connection string
+}
+
+// QuotaCounter reports usage against a single limit. A Limit of zero means
+// the counter is tracked but not limited.
+type QuotaCounter struct {
+	Used  int64 `json:"used"`
+	Limit int64 `json:"limit"`
+}
+
+// QuotaUsage reports the current usage of one quota scope over its window.
+type QuotaUsage struct {
+	Scope             string       `json:"scope"`
+	Key               string       `json:"key"`
+	WindowSeconds     int64        `json:"window_seconds"`
+	Requests          QuotaCounter `json:"requests"`
+	BytesRead         QuotaCounter `json:"bytes_read"`
+	BytesWritten      QuotaCounter `json:"bytes_written"`
+	Exhausted         bool         `json:"exhausted"`
+	RetryAfterSeconds int64        `json:"retry_after_seconds,omitempty"`
+}
+
+// QuotaReporter reports quota usage for the s3_get_quota tool.
+// The extensions.QuotaTracker type satisfies this interface.
+type QuotaReporter interface {
+	QuotaUsage(subject QuotaSubject) []QuotaUsage
+}
+
+// GetQuotaResult represents the result of the s3_get_quota tool.
+type GetQuotaResult struct {
+	SessionID  string       `json:"session_id,omitempty"`
+	Principal  string       `json:"principal,omitempty"`
+	Connection string       `json:"connection,omitempty"`
+	Quotas     []QuotaUsage `json:"quotas,omitempty"`
+}
+
+// registerGetQuotaTool registers the s3_get_quota tool.
+func (t *Toolkit) registerGetQuotaTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		quotaInput, ok := input.(GetQuotaInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleGetQuota(ctx, req, quotaInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolGetQuota, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolGetQuota),
+		Title:        t.getTitle(ToolGetQuota, cfg),
+		Description:  t.getDescription(ToolGetQuota, cfg),
+		Annotations:  t.getAnnotations(ToolGetQuota, cfg),
+		Icons:        t.getIcons(ToolGetQuota, cfg),
+		OutputSchema: t.getOutputSchema(ToolGetQuota, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetQuotaInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[GetQuotaResult](out), err
+	})
+}
+
+// handleGetQuota handles the s3_get_quota tool request.
+func (t *Toolkit) handleGetQuota(_ context.Context, req *mcp.CallToolRequest, input GetQuotaInput) (*mcp.CallToolResult, any, error) {
+	if t.quotaReporter == nil {
+		return ErrorResult("quota tracking is not enabled"), nil, nil
+	}
+
+	subject := QuotaSubject{
+		SessionID:  requestSessionID(req),
+		Principal:  requestPrincipal(req),
+		Connection: input.Connection,
+	}
+	if subject.Connection == "" {
+		subject.Connection = t.defaultConnection
+	}
+
+	result := GetQuotaResult{
+		SessionID:  subject.SessionID,
+		Principal:  subject.Principal,
+		Connection: subject.Connection,
+		Quotas:     t.quotaReporter.QuotaUsage(subject),
+	}
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
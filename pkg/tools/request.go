@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// requestConnection returns the "connection" argument of a tool request,
+// or an empty string when the request does not name a connection.
+func requestConnection(req *mcp.CallToolRequest) string {
+	if req == nil || req.Params == nil || len(req.Params.Arguments) == 0 {
+		return ""
+	}
+
+	var args struct {
+		Connection string `json:"connection"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return ""
+	}
+	return args.Connection
+}
+
+// requestSessionID returns an identifier for the MCP session that issued the
+// request. Transports without session IDs (such as stdio) fall back to the
+// identity of the session object, which is stable for the session lifetime.
+func requestSessionID(req *mcp.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	if id := req.Session.ID(); id != "" {
+		return id
+	}
+	return fmt.Sprintf("session-%p", req.Session)
+}
+
+// requestPrincipal returns the authenticated user ID carried by the request,
+// or an empty string when the transport does not authenticate callers.
+func requestPrincipal(req *mcp.CallToolRequest) string {
+	if req == nil || req.Extra == nil || req.Extra.TokenInfo == nil {
+		return ""
+	}
+	return req.Extra.TokenInfo.UserID
+}
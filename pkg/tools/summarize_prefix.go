@@ -0,0 +1,284 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// Limits for s3_summarize_prefix.
+const (
+	defaultSummaryScanLimit = 100000
+	maxSummaryScanLimit     = 1000000
+	defaultSummaryTimeLimit = 30 * time.Second
+	maxSummaryTimeLimit     = 5 * time.Minute
+	defaultSummaryTop       = 10
+	maxSummaryTop           = 100
+
+	// maxSummaryGroups caps the entries in each breakdown. Smaller groups
+	// are folded into a single summaryOtherGroup entry.
+	maxSummaryGroups = 50
+)
+
+// Group names used in prefix summary breakdowns.
+const (
+	summaryOtherGroup = "(other)"
+	summaryNoneGroup  = "(none)"
+)
+
+// UsageGroup is the object count and size of one group in a prefix summary.
+type UsageGroup struct {
+	Name    string `json:"name"`
+	Objects int    `json:"objects"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// SummarizePrefixResult represents the result of summarizing a prefix.
+type SummarizePrefixResult struct {
+	Bucket         string         `json:"bucket"`
+	Prefix         string         `json:"prefix,omitempty"`
+	TotalBytes     int64          `json:"total_bytes"`
+	ObjectCount    int            `json:"object_count"`
+	ByStorageClass []UsageGroup   `json:"by_storage_class"`
+	ByExtension    []UsageGroup   `json:"by_extension"`
+	BySubPrefix    []UsageGroup   `json:"by_sub_prefix"`
+	Oldest         *ObjectResult  `json:"oldest,omitempty"`
+	Newest         *ObjectResult  `json:"newest,omitempty"`
+	Largest        []ObjectResult `json:"largest"`
+	IsPartial      bool           `json:"is_partial"`
+	StopReason     string         `json:"stop_reason,omitempty"`
+	ElapsedMS      int64          `json:"elapsed_ms"`
+	ServedBy       string         `json:"served_by,omitempty"`
+}
+
+// registerSummarizePrefixTool registers the s3_summarize_prefix tool.
+func (t *Toolkit) registerSummarizePrefixTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		summarizeInput, ok := input.(SummarizePrefixInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleSummarizePrefix(ctx, req, summarizeInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolSummarizePrefix, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolSummarizePrefix),
+		Title:        t.getTitle(ToolSummarizePrefix, cfg),
+		Description:  t.getDescription(ToolSummarizePrefix, cfg),
+		Annotations:  t.getAnnotations(ToolSummarizePrefix, cfg),
+		Icons:        t.getIcons(ToolSummarizePrefix, cfg),
+		OutputSchema: t.getOutputSchema(ToolSummarizePrefix, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input SummarizePrefixInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[SummarizePrefixResult](out), err
+	})
+}
+
+// handleSummarizePrefix handles the s3_summarize_prefix tool request.
+func (t *Toolkit) handleSummarizePrefix(ctx context.Context, _ *mcp.CallToolRequest, input SummarizePrefixInput) (*mcp.CallToolResult, any, error) {
+	if input.Bucket == "" {
+		return ErrorResult("bucket parameter is required"), nil, nil
+	}
+	scanLimit := clampInt(input.ScanLimit, defaultSummaryScanLimit, maxSummaryScanLimit)
+	top := clampInt(input.Top, defaultSummaryTop, maxSummaryTop)
+	timeLimit := defaultSummaryTimeLimit
+	if input.TimeLimitSeconds > 0 {
+		timeLimit = min(time.Duration(input.TimeLimitSeconds)*time.Second, maxSummaryTimeLimit)
+	}
+
+	s3Client, release, err := t.holdClient(input.Connection)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	defer release()
+	if err := t.guardFor(s3Client).checkListPrefix(input.Bucket, input.Prefix); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	start := time.Now()
+	deadline := start.Add(timeLimit)
+	summary := newPrefixSummary(input.Prefix, top)
+	timedOut := false
+
+	ctx, servedBy := withServedBy(ctx)
+	walk, err := walkObjects(ctx, s3Client, input.Bucket, input.Prefix, "", scanLimit, func(obj client.ObjectInfo) bool {
+		summary.add(obj)
+		if time.Now().After(deadline) {
+			timedOut = true
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return ErrorResultFor(fmt.Errorf("failed to summarize prefix: %w", err)), nil, nil
+	}
+
+	result := summary.result(input.Bucket)
+	if walk.Cursor != "" {
+		result.IsPartial = true
+		result.StopReason = StopReasonScanLimit
+		if timedOut {
+			result.StopReason = StopReasonTimeLimit
+		}
+	}
+	result.ElapsedMS = time.Since(start).Milliseconds()
+	result.ServedBy = servedBy()
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
+
+// prefixSummary accumulates the statistics for s3_summarize_prefix.
+type prefixSummary struct {
+	prefix         string
+	count          int
+	bytes          int64
+	byStorageClass map[string]*UsageGroup
+	byExtension    map[string]*UsageGroup
+	bySubPrefix    map[string]*UsageGroup
+	oldest         *client.ObjectInfo
+	newest         *client.ObjectInfo
+	largest        *topN[client.ObjectInfo]
+}
+
+// newPrefixSummary returns an empty summary of prefix that keeps the top
+// largest objects.
+func newPrefixSummary(prefix string, top int) *prefixSummary {
+	return &prefixSummary{
+		prefix:         prefix,
+		byStorageClass: make(map[string]*UsageGroup),
+		byExtension:    make(map[string]*UsageGroup),
+		bySubPrefix:    make(map[string]*UsageGroup),
+		largest: newTopN(top, func(a, b client.ObjectInfo) bool {
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+			return a.Key > b.Key
+		}),
+	}
+}
+
+// add counts obj in the summary.
+func (s *prefixSummary) add(obj client.ObjectInfo) {
+	s.count++
+	s.bytes += obj.Size
+	addUsage(s.byStorageClass, storageClassOf(obj), obj.Size)
+	addUsage(s.byExtension, extensionOf(obj.Key), obj.Size)
+	addUsage(s.bySubPrefix, subPrefixOf(s.prefix, obj.Key), obj.Size)
+
+	if !obj.LastModified.IsZero() {
+		if s.oldest == nil || obj.LastModified.Before(s.oldest.LastModified) {
+			o := obj
+			s.oldest = &o
+		}
+		if s.newest == nil || obj.LastModified.After(s.newest.LastModified) {
+			o := obj
+			s.newest = &o
+		}
+	}
+	s.largest.Push(obj)
+}
+
+// result returns the summary for tool output.
+func (s *prefixSummary) result(bucket string) SummarizePrefixResult {
+	result := SummarizePrefixResult{
+		Bucket:         bucket,
+		Prefix:         s.prefix,
+		TotalBytes:     s.bytes,
+		ObjectCount:    s.count,
+		ByStorageClass: usageGroups(s.byStorageClass),
+		ByExtension:    usageGroups(s.byExtension),
+		BySubPrefix:    usageGroups(s.bySubPrefix),
+		Largest:        make([]ObjectResult, 0, s.largest.Len()),
+	}
+	if s.oldest != nil {
+		oldest, newest := objectResult(*s.oldest), objectResult(*s.newest)
+		result.Oldest, result.Newest = &oldest, &newest
+	}
+	for _, obj := range s.largest.Sorted() {
+		result.Largest = append(result.Largest, objectResult(obj))
+	}
+	return result
+}
+
+// addUsage counts an object of size bytes in the named group.
+func addUsage(groups map[string]*UsageGroup, name string, size int64) {
+	g, ok := groups[name]
+	if !ok {
+		g = &UsageGroup{Name: name}
+		groups[name] = g
+	}
+	g.Objects++
+	g.Bytes += size
+}
+
+// usageGroups returns the groups largest first, folding all but the largest
+// maxSummaryGroups into one summaryOtherGroup entry.
+func usageGroups(groups map[string]*UsageGroup) []UsageGroup {
+	list := make([]UsageGroup, 0, len(groups))
+	for _, g := range groups {
+		list = append(list, *g)
+	}
+	slices.SortFunc(list, func(a, b UsageGroup) int {
+		switch {
+		case a.Bytes != b.Bytes:
+			return cmpDesc(a.Bytes, b.Bytes)
+		case a.Objects != b.Objects:
+			return cmpDesc(int64(a.Objects), int64(b.Objects))
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	if len(list) <= maxSummaryGroups {
+		return list
+	}
+	other := UsageGroup{Name: summaryOtherGroup}
+	for _, g := range list[maxSummaryGroups-1:] {
+		other.Objects += g.Objects
+		other.Bytes += g.Bytes
+	}
+	return append(list[:maxSummaryGroups-1], other)
+}
+
+// cmpDesc orders a before b when a is greater.
+func cmpDesc(a, b int64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return 0
+}
+
+// extensionOf returns the lowercase extension of the key's last segment,
+// without the dot, or summaryNoneGroup when it has none.
+func extensionOf(key string) string {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(path.Base(key)), "."))
+	if ext == "" {
+		return summaryNoneGroup
+	}
+	return ext
+}
+
+// subPrefixOf returns the immediate sub-prefix of prefix that contains key,
+// such as "data/2024/" for key "data/2024/01/a.json" under "data/", or
+// summaryNoneGroup for keys directly under the prefix.
+func subPrefixOf(prefix, key string) string {
+	rest := strings.TrimPrefix(key, prefix)
+	if i := strings.Index(rest, "/"); i >= 0 {
+		return prefix + rest[:i+1]
+	}
+	return summaryNoneGroup
+}
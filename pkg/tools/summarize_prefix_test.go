@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+func TestSummarizePrefix(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	objects := []client.ObjectInfo{
+		{Key: "data/readme", Size: 10, LastModified: base.Add(5 * time.Hour)},
+		{Key: "data/2024/a.json", Size: 100, LastModified: base.Add(2 * time.Hour)},
+		{Key: "data/2024/b.JSON", Size: 300, LastModified: base},
+		{Key: "data/2025/c.parquet", Size: 1000, LastModified: base.Add(9 * time.Hour), StorageClass: "GLACIER"},
+		{Key: "data/2025/deep/d.parquet", Size: 50, LastModified: base.Add(3 * time.Hour)},
+		{Key: "other/e.json", Size: 5000},
+	}
+	mock := NewMockS3Client("test")
+	mock.ListObjectsFunc = pagedListing(objects, 2, nil)
+	toolkit := NewToolkit(mock)
+
+	result, out, _ := toolkit.handleSummarizePrefix(context.Background(), nil, SummarizePrefixInput{
+		Bucket: "bucket",
+		Prefix: "data/",
+		Top:    2,
+	})
+	if result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	got := out.(*SummarizePrefixResult)
+
+	if got.ObjectCount != 5 || got.TotalBytes != 1460 || got.IsPartial {
+		t.Errorf("count = %d bytes = %d partial = %v", got.ObjectCount, got.TotalBytes, got.IsPartial)
+	}
+	wantGroups := map[string][]UsageGroup{
+		"storage class": {{Name: "GLACIER", Objects: 1, Bytes: 1000}, {Name: "STANDARD", Objects: 4, Bytes: 460}},
+		"extension":     {{Name: "parquet", Objects: 2, Bytes: 1050}, {Name: "json", Objects: 2, Bytes: 400}, {Name: summaryNoneGroup, Objects: 1, Bytes: 10}},
+		"sub-prefix":    {{Name: "data/2025/", Objects: 2, Bytes: 1050}, {Name: "data/2024/", Objects: 2, Bytes: 400}, {Name: summaryNoneGroup, Objects: 1, Bytes: 10}},
+	}
+	for name, groups := range map[string][]UsageGroup{
+		"storage class": got.ByStorageClass,
+		"extension":     got.ByExtension,
+		"sub-prefix":    got.BySubPrefix,
+	} {
+		if fmt.Sprint(groups) != fmt.Sprint(wantGroups[name]) {
+			t.Errorf("by %s = %v, want %v", name, groups, wantGroups[name])
+		}
+	}
+	if got.Oldest == nil || got.Oldest.Key != "data/2024/b.JSON" || got.Newest.Key != "data/2025/c.parquet" {
+		t.Errorf("oldest = %+v newest = %+v", got.Oldest, got.Newest)
+	}
+	if len(got.Largest) != 2 || got.Largest[0].Key != "data/2025/c.parquet" || got.Largest[1].Key != "data/2024/b.JSON" {
+		t.Errorf("largest = %+v", got.Largest)
+	}
+}
+
+func TestSummarizePrefix_Partial(t *testing.T) {
+	var objects []client.ObjectInfo
+	for i := range 10 {
+		objects = append(objects, client.ObjectInfo{Key: fmt.Sprintf("k%02d", i), Size: 1})
+	}
+	mock := NewMockS3Client("test")
+	mock.ListObjectsFunc = pagedListing(objects, 4, nil)
+	toolkit := NewToolkit(mock)
+
+	_, out, _ := toolkit.handleSummarizePrefix(context.Background(), nil, SummarizePrefixInput{Bucket: "bucket", ScanLimit: 6})
+	got := out.(*SummarizePrefixResult)
+	if !got.IsPartial || got.StopReason != StopReasonScanLimit || got.ObjectCount != 6 {
+		t.Errorf("partial = %v reason = %q count = %d", got.IsPartial, got.StopReason, got.ObjectCount)
+	}
+
+	_, out, _ = toolkit.handleSummarizePrefix(context.Background(), nil, SummarizePrefixInput{Bucket: "bucket", ScanLimit: 10})
+	if got := out.(*SummarizePrefixResult); got.IsPartial || got.ObjectCount != 10 {
+		t.Errorf("a budget covering every key reported partial = %v count = %d", got.IsPartial, got.ObjectCount)
+	}
+}
+
+func TestUsageGroups_FoldsSmallGroups(t *testing.T) {
+	groups := make(map[string]*UsageGroup)
+	for i := range maxSummaryGroups + 5 {
+		addUsage(groups, fmt.Sprintf("g%03d", i), int64(1000-i))
+	}
+	list := usageGroups(groups)
+	if len(list) != maxSummaryGroups {
+		t.Fatalf("len = %d, want %d", len(list), maxSummaryGroups)
+	}
+	if other := list[len(list)-1]; other.Name != summaryOtherGroup || other.Objects != 6 {
+		t.Errorf("last group = %+v, want %s with 6 objects", other, summaryOtherGroup)
+	}
+}
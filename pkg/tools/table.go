@@ -0,0 +1,630 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/integration"
+)
+
+// Column types inferred by s3_preview_table.
+const (
+	TableTypeInt       = "int"
+	TableTypeFloat     = "float"
+	TableTypeBool      = "bool"
+	TableTypeTimestamp = "timestamp"
+	TableTypeString    = "string"
+)
+
+// Values accepted by s3_preview_table header.
+const (
+	TableHeaderPresent = "present"
+	TableHeaderAbsent  = "absent"
+)
+
+// Limits for s3_preview_table.
+const (
+	defaultTableRows = 10
+	maxTableRows     = 100
+
+	// tablePreviewBytes is the size of the leading chunk read from the
+	// object, and of the text kept after decompressing it.
+	tablePreviewBytes = 1024 * 1024
+
+	// maxTableScanRecords caps the records used to infer column types.
+	maxTableScanRecords = 1000
+
+	// tableSniffRecords is the number of records split with each candidate
+	// delimiter when detecting the delimiter.
+	tableSniffRecords = 20
+
+	// maxTableFieldLen is the longest field value returned in full.
+	maxTableFieldLen = 1000
+)
+
+// tableDelimiters are the delimiters tried, in order of preference, when
+// none is given.
+var tableDelimiters = []rune{',', '\t', ';', '|'}
+
+// tableTimestampLayouts are the layouts a timestamp value may have. Parsing
+// accepts fractional seconds after the seconds field of each layout.
+var tableTimestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+}
+
+// TableColumn describes a column of a delimited file.
+type TableColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// PreviewTableResult represents the result of previewing a delimited file.
+type PreviewTableResult struct {
+	Bucket      string           `json:"bucket"`
+	Key         string           `json:"key"`
+	Size        int64            `json:"size"`
+	Compression string           `json:"compression,omitempty"`
+	Delimiter   string           `json:"delimiter"`
+	Quote       string           `json:"quote"`
+	HasHeader   bool             `json:"has_header"`
+	Columns     []TableColumn    `json:"columns"`
+	Schema      map[string]any   `json:"schema"`
+	Rows        []map[string]any `json:"rows"`
+	RowsScanned int              `json:"rows_scanned"`
+	BytesRead   int64            `json:"bytes_read"`
+	IsTruncated bool             `json:"is_truncated"`
+	StopReason  string           `json:"stop_reason,omitempty"`
+	ServedBy    string           `json:"served_by,omitempty"`
+}
+
+func (t *Toolkit) registerPreviewTableTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		previewInput, ok := input.(PreviewTableInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handlePreviewTable(ctx, req, previewInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolPreviewTable, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolPreviewTable),
+		Title:        t.getTitle(ToolPreviewTable, cfg),
+		Description:  t.getDescription(ToolPreviewTable, cfg),
+		Annotations:  t.getAnnotations(ToolPreviewTable, cfg),
+		Icons:        t.getIcons(ToolPreviewTable, cfg),
+		OutputSchema: t.getOutputSchema(ToolPreviewTable, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input PreviewTableInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[PreviewTableResult](out), err
+	})
+}
+
+// handlePreviewTable handles the s3_preview_table tool request.
+func (t *Toolkit) handlePreviewTable(ctx context.Context, _ *mcp.CallToolRequest, input PreviewTableInput) (*mcp.CallToolResult, any, error) {
+	if input.Bucket == "" {
+		return ErrorResult("bucket parameter is required"), nil, nil
+	}
+	if input.Key == "" {
+		return ErrorResult("key parameter is required"), nil, nil
+	}
+	opts := tableOptions{rows: clampInt(input.Rows, defaultTableRows, maxTableRows)}
+	var err error
+	if opts.delimiter, err = tableDelimiter(input.Delimiter); err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	switch input.Header {
+	case "", TableHeaderPresent, TableHeaderAbsent:
+		opts.header = input.Header
+	default:
+		return ErrorResultf("%v: header must be %s or %s", ErrInvalidParameter, TableHeaderPresent, TableHeaderAbsent), nil, nil
+	}
+
+	s3Client, release, err := t.holdClient(input.Connection)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	defer release()
+	guard := t.guardFor(s3Client)
+	if err := guard.checkKey(input.Bucket, input.Key); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	ctx, servedBy := withServedBy(ctx)
+	meta, err := s3Client.GetObjectMetadata(ctx, input.Bucket, input.Key)
+	if err != nil {
+		return S3ErrorResult("get object metadata", err), nil, nil
+	}
+
+	// Read only the leading chunk. An empty object cannot be range-read.
+	chunk := int64(tablePreviewBytes)
+	if guard.maxGetSize > 0 {
+		chunk = min(chunk, guard.maxGetSize)
+	}
+	var data []byte
+	var contentEncoding string
+	if meta.Size > 0 {
+		content, err := s3Client.GetObjectRange(ctx, input.Bucket, input.Key, 0, min(meta.Size, chunk))
+		if err != nil {
+			return S3ErrorResult("get object", err), nil, nil
+		}
+		recordBytesRead(ctx, int64(len(content.Body)))
+		data, contentEncoding = content.Body, content.ContentEncoding
+	}
+	bytesRead := int64(len(data))
+	opts.complete = bytesRead >= meta.Size
+
+	compression := integration.DetectCompression(contentEncoding, input.Key, data)
+	if compression != integration.CompressionNone {
+		data, opts.complete, err = decompressPrefix(data, compression, opts.complete)
+		if err != nil {
+			return ErrorResultf("failed to decompress object: %v", err), nil, nil
+		}
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return ErrorResultf("%s does not look like delimited text", input.Key), nil, nil
+	}
+	opts.ext = path.Ext(integration.StripCompressionExt(input.Key))
+
+	result := previewTable(data, opts)
+	result.Bucket = input.Bucket
+	result.Key = input.Key
+	result.Size = meta.Size
+	if compression != integration.CompressionNone {
+		result.Compression = compression
+	}
+	result.BytesRead = bytesRead
+	result.ServedBy = servedBy()
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, result, nil
+}
+
+// tableDelimiter parses the delimiter parameter: empty for detection, a
+// single character, or the escape `\t` for a tab.
+func tableDelimiter(s string) (rune, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if s == `\t` {
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || r == utf8.RuneError || r == '\r' || r == '\n' || r == '"' {
+		return 0, fmt.Errorf("%w: delimiter must be a single character other than a quote or newline", ErrInvalidParameter)
+	}
+	return r, nil
+}
+
+// decompressPrefix decompresses up to tablePreviewBytes of data. When data
+// is only the start of the object, the compressed stream ends early and the
+// resulting error is ignored if some text was recovered. It reports whether
+// the returned text is all of the object's content.
+func decompressPrefix(data []byte, compression string, whole bool) ([]byte, bool, error) {
+	zr, err := integration.NewDecompressReader(bytes.NewReader(data), compression)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = zr.Close() }()
+	out, err := io.ReadAll(io.LimitReader(zr, tablePreviewBytes+1))
+	if err != nil && (whole || len(out) == 0) {
+		return nil, false, err
+	}
+	complete := whole && err == nil && len(out) <= tablePreviewBytes
+	return out[:min(len(out), tablePreviewBytes)], complete, nil
+}
+
+// tableOptions controls how previewTable reads delimited text.
+type tableOptions struct {
+	delimiter rune   // zero to detect
+	header    string // empty to detect
+	rows      int    // sample rows to return
+	ext       string // extension of the key, without compression suffixes
+
+	// complete is false when the text is only the start of the object.
+	complete bool
+}
+
+// previewTable detects the dialect of delimited text, infers the type of
+// each column from up to maxTableScanRecords records, and returns the first
+// rows converted to those types.
+func previewTable(data []byte, opts tableOptions) *PreviewTableResult {
+	if !opts.complete {
+		// Drop a multi-byte character cut at the end of the chunk.
+		data = trimPartialRune(data)
+	}
+	text := strings.ToValidUTF8(string(data), "\uFFFD")
+	text = strings.TrimPrefix(text, "\uFEFF")
+
+	quote := sniffQuote(text)
+	delim := opts.delimiter
+	if delim == 0 {
+		delim = sniffDelimiter(text, quote, opts.ext, opts.complete)
+	}
+	if quote == delim {
+		quote = '"'
+	}
+	records, more := splitRecords(text, delim, quote, maxTableScanRecords+1, opts.complete)
+
+	result := &PreviewTableResult{
+		Delimiter:   string(delim),
+		Quote:       string(quote),
+		Columns:     []TableColumn{},
+		Rows:        []map[string]any{},
+		IsTruncated: !opts.complete,
+	}
+	switch opts.header {
+	case TableHeaderPresent:
+		result.HasHeader = len(records) > 0
+	case "":
+		result.HasHeader = sniffHeader(records)
+	}
+	var header []string
+	if result.HasHeader {
+		header, records = records[0], records[1:]
+	}
+	if len(records) > maxTableScanRecords {
+		records, more = records[:maxTableScanRecords], true
+	}
+	switch {
+	case more:
+		result.IsTruncated, result.StopReason = true, StopReasonScanLimit
+	case result.IsTruncated:
+		result.StopReason = StopReasonByteBudget
+	}
+	result.RowsScanned = len(records)
+
+	width := len(header)
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+	names := tableColumnNames(header, width)
+	types := make([]columnType, width)
+	for _, record := range records {
+		for i := range width {
+			if i >= len(record) {
+				types[i].observe("")
+				continue
+			}
+			types[i].observe(record[i])
+		}
+	}
+	for i, name := range names {
+		result.Columns = append(result.Columns, TableColumn{
+			Name:     name,
+			Type:     types[i].typ(),
+			Nullable: types[i].nullable,
+		})
+	}
+	result.Schema = tableJSONSchema(result.Columns, types)
+
+	for _, record := range records[:min(len(records), opts.rows)] {
+		row := make(map[string]any, width)
+		for i, c := range result.Columns {
+			var value string
+			if i < len(record) {
+				value = record[i]
+			}
+			row[c.Name] = tableValue(value, c.Type)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result
+}
+
+// splitRecords splits delimited text into records of fields. Quoted fields
+// may contain delimiters, newlines, and doubled quotes. Blank lines are
+// skipped. When complete is false, text is the start of longer content and
+// its last record, which may be cut short, is dropped. Splitting stops after
+// limit records and more reports whether text continues past them.
+func splitRecords(text string, delim, quote rune, limit int, complete bool) (records [][]string, more bool) {
+	var record []string
+	var field strings.Builder
+	inQuotes, quoted := false, false
+	endField := func() {
+		record = append(record, field.String())
+		field.Reset()
+		quoted = false
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		switch {
+		case inQuotes:
+			if r != quote {
+				field.WriteRune(r)
+				break
+			}
+			if strings.HasPrefix(text[i:], string(quote)) {
+				field.WriteRune(quote)
+				i += size
+				break
+			}
+			inQuotes = false
+		case r == quote && field.Len() == 0 && !quoted:
+			inQuotes, quoted = true, true
+		case r == delim:
+			endField()
+		case r == '\n' || r == '\r':
+			if r == '\r' && strings.HasPrefix(text[i:], "\n") {
+				i++
+			}
+			endField()
+			if len(record) > 1 || record[0] != "" {
+				records = append(records, record)
+			}
+			record = nil
+			if len(records) == limit {
+				return records, i < len(text)
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if complete && !inQuotes && (record != nil || field.Len() > 0 || quoted) {
+		endField()
+		records = append(records, record)
+	}
+	return records, false
+}
+
+// sniffQuote returns the quote character of text. Fields quoted with single
+// quotes are recognized when no field starts with a double quote.
+func sniffQuote(text string) rune {
+	text = text[:min(len(text), 64*1024)]
+	count := func(q byte) int {
+		n := 0
+		for i := 0; i < len(text); i++ {
+			if text[i] != q {
+				continue
+			}
+			if i == 0 || strings.ContainsRune("\r\n", rune(text[i-1])) || slices.Contains(tableDelimiters, rune(text[i-1])) {
+				n++
+			}
+		}
+		return n
+	}
+	if count('"') == 0 && count('\'') > 0 {
+		return '\''
+	}
+	return '"'
+}
+
+// sniffDelimiter returns the candidate delimiter that splits the leading
+// records of text into the same number of fields most often, preferring more
+// fields, then the delimiter implied by the key extension, then the order of
+// tableDelimiters. It returns a comma when no candidate splits text.
+func sniffDelimiter(text string, quote rune, ext string, complete bool) rune {
+	candidates := tableDelimiters
+	switch strings.ToLower(ext) {
+	case ".tsv", ".tab":
+		candidates = append([]rune{'\t'}, candidates...)
+	case ".psv":
+		candidates = append([]rune{'|'}, candidates...)
+	}
+
+	best, bestFields, bestCount := ',', 1, 0
+	for _, delim := range candidates {
+		records, _ := splitRecords(text, delim, quote, tableSniffRecords, complete)
+		counts := make(map[int]int)
+		for _, record := range records {
+			counts[len(record)]++
+		}
+		for fields, count := range counts {
+			if fields < 2 {
+				continue
+			}
+			if count > bestCount || count == bestCount && fields > bestFields {
+				best, bestFields, bestCount = delim, fields, count
+			}
+		}
+	}
+	return best
+}
+
+// sniffHeader reports whether the first record is a header: its values are
+// distinct, non-empty, and none of them is a number, boolean, or timestamp.
+func sniffHeader(records [][]string) bool {
+	if len(records) == 0 {
+		return false
+	}
+	seen := make(map[string]bool)
+	for _, value := range records[0] {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] || valueType(value) != TableTypeString {
+			return false
+		}
+		seen[value] = true
+	}
+	return true
+}
+
+// tableColumnNames returns width column names from header. Missing and empty
+// names become column_N, and repeated names get a numeric suffix.
+func tableColumnNames(header []string, width int) []string {
+	names := make([]string, width)
+	seen := make(map[string]bool, width)
+	for i := range names {
+		name := ""
+		if i < len(header) {
+			name = truncateString(strings.TrimSpace(header[i]), maxTableFieldLen)
+		}
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		for base, n := name, 2; seen[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// columnType accumulates the values of a column to infer its type.
+type columnType struct {
+	kind     string // empty until a non-null value is seen
+	dateOnly bool   // every timestamp is a date without a time
+	nullable bool
+}
+
+// observe widens the column type to cover value.
+func (c *columnType) observe(value string) {
+	value = strings.TrimSpace(value)
+	if isTableNull(value) {
+		c.nullable = true
+		return
+	}
+	kind := valueType(value)
+	switch {
+	case c.kind == "":
+		c.kind, c.dateOnly = kind, true
+	case c.kind == kind:
+	case c.kind == TableTypeInt && kind == TableTypeFloat, c.kind == TableTypeFloat && kind == TableTypeInt:
+		c.kind = TableTypeFloat
+	default:
+		c.kind = TableTypeString
+	}
+	if kind == TableTypeTimestamp && len(value) != len(time.DateOnly) {
+		c.dateOnly = false
+	}
+}
+
+// typ returns the inferred type. A column with only null values is a string.
+func (c *columnType) typ() string {
+	if c.kind == "" {
+		return TableTypeString
+	}
+	return c.kind
+}
+
+// isTableNull reports whether a trimmed field value stands for a null.
+func isTableNull(value string) bool {
+	return value == "" || strings.EqualFold(value, "null")
+}
+
+// valueType returns the narrowest type of a trimmed, non-null field value.
+// Numbers with leading zeros, such as postal codes, are strings.
+func valueType(value string) string {
+	digits := strings.TrimLeft(value, "+-")
+	switch {
+	case len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9':
+		return TableTypeString
+	case isTableInt(value):
+		return TableTypeInt
+	case isTableFloat(value):
+		return TableTypeFloat
+	case strings.EqualFold(value, "true"), strings.EqualFold(value, "false"):
+		return TableTypeBool
+	case isTableTimestamp(value):
+		return TableTypeTimestamp
+	}
+	return TableTypeString
+}
+
+func isTableInt(value string) bool {
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
+
+// isTableFloat reports whether value is a decimal number. Hexadecimal,
+// infinite, and NaN values, which ParseFloat accepts, are not.
+func isTableFloat(value string) bool {
+	if strings.ContainsAny(value, "xXiInN_") {
+		return false
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+func isTableTimestamp(value string) bool {
+	for _, layout := range tableTimestampLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// tableValue converts a field value to the JSON value of its column type.
+// Timestamps are kept as written.
+func tableValue(value, typ string) any {
+	trimmed := strings.TrimSpace(value)
+	if isTableNull(trimmed) {
+		return nil
+	}
+	switch typ {
+	case TableTypeInt:
+		if n, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return n
+		}
+	case TableTypeFloat:
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return f
+		}
+	case TableTypeBool:
+		return strings.EqualFold(trimmed, "true")
+	case TableTypeTimestamp:
+		return trimmed
+	}
+	return truncateString(value, maxTableFieldLen)
+}
+
+// tableJSONSchema returns a JSON Schema for the rows of a table with the
+// given columns.
+func tableJSONSchema(columns []TableColumn, types []columnType) map[string]any {
+	properties := make(map[string]any, len(columns))
+	required := []string{}
+	for i, c := range columns {
+		property := map[string]any{}
+		var jsonType string
+		switch c.Type {
+		case TableTypeInt:
+			jsonType = "integer"
+		case TableTypeFloat:
+			jsonType = "number"
+		case TableTypeBool:
+			jsonType = "boolean"
+		case TableTypeTimestamp:
+			jsonType = "string"
+			property["format"] = "date-time"
+			if types[i].dateOnly {
+				property["format"] = "date"
+			}
+		default:
+			jsonType = "string"
+		}
+		if c.Nullable {
+			property["type"] = []string{jsonType, "null"}
+		} else {
+			property["type"] = jsonType
+			required = append(required, c.Name)
+		}
+		properties[c.Name] = property
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
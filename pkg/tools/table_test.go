@@ -0,0 +1,193 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPreviewTable(t *testing.T) {
+	csv := "id,name,score,active,signup,zip\n" +
+		"1,Ada,9.5,true,2024-01-02,02134\n" +
+		"2,\"Lovelace, Ada\",10,FALSE,2024-01-03,\n" +
+		"3,\"say \"\"hi\"\"\nthere\",7.25,true,2024-01-04,10001\n"
+	mock := newContentMock(map[string][]byte{
+		"users.csv":     []byte(csv),
+		"users.csv.gz":  gzipBytes(t, csv),
+		"events.tsv":    []byte("at\tlevel\tcount\n2024-01-02T03:04:05Z\tinfo\t3\n2024-01-02 03:04:06\twarn\tnull\n"),
+		"semi.txt":      []byte("1;2,5;x\n2;3,5;y\n"),
+		"quoted.psv":    []byte("'a|b'|c\n'd'|e\n"),
+		"empty.csv":     nil,
+		"image.png":     {0x89, 'P', 'N', 'G', 0x00, 0x01},
+		"names.csv":     []byte("name,city\nAda,London\n"),
+		"widths.csv":    []byte("a,b\n1,2,3\n4\n"),
+		"duplicate.csv": []byte("x,x,,y\n1,2,3,4\n"),
+	})
+	toolkit := NewToolkit(mock)
+
+	preview := func(input PreviewTableInput) *PreviewTableResult {
+		t.Helper()
+		input.Bucket = "bucket"
+		result, out, _ := toolkit.handlePreviewTable(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error for %s: %v", input.Key, result.Content)
+		}
+		return out.(*PreviewTableResult)
+	}
+	columns := func(r *PreviewTableResult) string {
+		var s []string
+		for _, c := range r.Columns {
+			col := c.Name + ":" + c.Type
+			if c.Nullable {
+				col += "?"
+			}
+			s = append(s, col)
+		}
+		return strings.Join(s, " ")
+	}
+
+	for _, key := range []string{"users.csv", "users.csv.gz"} {
+		got := preview(PreviewTableInput{Key: key})
+		if got.Delimiter != "," || got.Quote != `"` || !got.HasHeader || got.RowsScanned != 3 || got.IsTruncated {
+			t.Errorf("%s: dialect = %+v", key, got)
+		}
+		if want := "id:int name:string score:float active:bool signup:timestamp zip:string?"; columns(got) != want {
+			t.Errorf("%s: columns = %s, want %s", key, columns(got), want)
+		}
+		if len(got.Rows) != 3 || got.Rows[0]["id"] != int64(1) || got.Rows[0]["score"] != 9.5 || got.Rows[1]["active"] != false ||
+			got.Rows[0]["zip"] != "02134" || got.Rows[1]["zip"] != nil || got.Rows[1]["name"] != "Lovelace, Ada" || got.Rows[2]["name"] != "say \"hi\"\nthere" {
+			t.Errorf("%s: rows = %v", key, got.Rows)
+		}
+	}
+	if got := preview(PreviewTableInput{Key: "users.csv.gz"}); got.Compression != "gzip" {
+		t.Errorf("compression = %q", got.Compression)
+	}
+
+	got := preview(PreviewTableInput{Key: "users.csv", Rows: 1})
+	props := got.Schema["properties"].(map[string]any)
+	if len(got.Rows) != 1 || fmt.Sprint(props["signup"]) != "map[format:date type:string]" || fmt.Sprint(props["zip"]) != "map[type:[string null]]" {
+		t.Errorf("rows = %d schema = %v", len(got.Rows), got.Schema)
+	}
+	if required := got.Schema["required"].([]string); !slices.Equal(required, []string{"id", "name", "score", "active", "signup"}) {
+		t.Errorf("required = %v", required)
+	}
+
+	got = preview(PreviewTableInput{Key: "events.tsv"})
+	if got.Delimiter != "\t" || columns(got) != "at:timestamp level:string count:int?" {
+		t.Errorf("tsv: delimiter = %q columns = %s", got.Delimiter, columns(got))
+	}
+	if props := got.Schema["properties"].(map[string]any); fmt.Sprint(props["at"]) != "map[format:date-time type:string]" {
+		t.Errorf("tsv schema = %v", got.Schema)
+	}
+
+	got = preview(PreviewTableInput{Key: "semi.txt"})
+	if got.Delimiter != ";" || got.HasHeader || columns(got) != "column_1:int column_2:string column_3:string" {
+		t.Errorf("semicolons: delimiter = %q header = %v columns = %s", got.Delimiter, got.HasHeader, columns(got))
+	}
+
+	got = preview(PreviewTableInput{Key: "quoted.psv", Header: TableHeaderAbsent})
+	if got.Delimiter != "|" || got.Quote != "'" || got.Rows[0]["column_1"] != "a|b" {
+		t.Errorf("single quotes: delimiter = %q quote = %q rows = %v", got.Delimiter, got.Quote, got.Rows)
+	}
+
+	got = preview(PreviewTableInput{Key: "empty.csv"})
+	if len(got.Columns) != 0 || len(got.Rows) != 0 || got.HasHeader {
+		t.Errorf("empty: %+v", got)
+	}
+
+	if got = preview(PreviewTableInput{Key: "names.csv"}); !got.HasHeader || columns(got) != "name:string city:string" {
+		t.Errorf("names: header = %v columns = %s", got.HasHeader, columns(got))
+	}
+	if got = preview(PreviewTableInput{Key: "names.csv", Header: TableHeaderAbsent}); got.HasHeader || got.RowsScanned != 2 {
+		t.Errorf("names without header: header = %v scanned = %d", got.HasHeader, got.RowsScanned)
+	}
+	if got = preview(PreviewTableInput{Key: "widths.csv"}); columns(got) != "a:int b:int? column_3:int?" {
+		t.Errorf("ragged: columns = %s", columns(got))
+	}
+	if got = preview(PreviewTableInput{Key: "duplicate.csv", Header: TableHeaderPresent}); columns(got) != "x:int x_2:int column_3:int y:int" {
+		t.Errorf("duplicate names: columns = %s", columns(got))
+	}
+	if got = preview(PreviewTableInput{Key: "semi.txt", Delimiter: ",", Header: TableHeaderAbsent}); got.Delimiter != "," || columns(got) != "column_1:string column_2:string" {
+		t.Errorf("delimiter override: columns = %s", columns(got))
+	}
+
+	for _, input := range []PreviewTableInput{
+		{Bucket: "bucket"},
+		{Bucket: "bucket", Key: "users.csv", Delimiter: "ab"},
+		{Bucket: "bucket", Key: "users.csv", Header: "maybe"},
+		{Bucket: "bucket", Key: "image.png"},
+		{Bucket: "bucket", Key: "missing.csv"},
+	} {
+		if result, _, _ := toolkit.handlePreviewTable(context.Background(), nil, input); !result.IsError {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
+
+func TestPreviewTable_FirstChunk(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,value\n")
+	for i := range 100000 {
+		fmt.Fprintf(&b, "%d,%d.5\n", i, i)
+	}
+	data := b.String()
+	mock := newContentMock(map[string][]byte{
+		"big.csv":    []byte(data),
+		"big.csv.gz": gzipBytes(t, data),
+	})
+
+	toolkit := NewToolkit(mock)
+	result, out, _ := toolkit.handlePreviewTable(context.Background(), nil, PreviewTableInput{Bucket: "bucket", Key: "big.csv"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	got := out.(*PreviewTableResult)
+	if got.BytesRead != tablePreviewBytes || got.RowsScanned != maxTableScanRecords || !got.IsTruncated || got.StopReason != StopReasonScanLimit {
+		t.Errorf("read = %d scanned = %d truncated = %v reason = %q", got.BytesRead, got.RowsScanned, got.IsTruncated, got.StopReason)
+	}
+
+	// A small retrieval limit cuts the chunk mid-record; the partial record
+	// is dropped.
+	toolkit = NewToolkit(mock, WithMaxGetSize(100))
+	for _, key := range []string{"big.csv", "big.csv.gz"} {
+		result, out, _ = toolkit.handlePreviewTable(context.Background(), nil, PreviewTableInput{Bucket: "bucket", Key: key, Rows: 100})
+		if result.IsError {
+			t.Fatalf("%s: unexpected error: %v", key, result.Content)
+		}
+		got = out.(*PreviewTableResult)
+		if got.BytesRead != 100 || !got.IsTruncated || got.StopReason != StopReasonByteBudget || len(got.Rows) == 0 {
+			t.Errorf("%s: read = %d truncated = %v reason = %q rows = %d", key, got.BytesRead, got.IsTruncated, got.StopReason, len(got.Rows))
+		}
+		last := got.Rows[len(got.Rows)-1]
+		if id, ok := last["id"].(int64); !ok || last["value"] != float64(id)+0.5 {
+			t.Errorf("%s: last row = %v", key, last)
+		}
+	}
+}
+
+func TestValueType(t *testing.T) {
+	for value, want := range map[string]string{
+		"42":                        TableTypeInt,
+		"-7":                        TableTypeInt,
+		"0":                         TableTypeInt,
+		"007":                       TableTypeString,
+		"0.5":                       TableTypeFloat,
+		"-01.5":                     TableTypeString,
+		"99999999999999999999":      TableTypeFloat,
+		"1.5e3":                     TableTypeFloat,
+		"NaN":                       TableTypeString,
+		"Inf":                       TableTypeString,
+		"0x1F":                      TableTypeString,
+		"True":                      TableTypeBool,
+		"yes":                       TableTypeString,
+		"2024-01-02T03:04:05.123Z":  TableTypeTimestamp,
+		"2024-01-02 03:04:05+02:00": TableTypeTimestamp,
+		"2024-13-02":                TableTypeString,
+	} {
+		if got := valueType(value); got != want {
+			t.Errorf("valueType(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
@@ -6,12 +6,24 @@ var defaultTitles = map[ToolName]string{
 	ToolListBuckets:       "List Buckets",
 	ToolListConnections:   "List Connections",
 	ToolListObjects:       "List Objects",
+	ToolFindObjects:       "Find Objects",
+	ToolSummarizePrefix:   "Summarize Prefix",
+	ToolTree:              "Prefix Tree",
+	ToolGrep:              "Search Object Contents",
+	ToolListArchive:       "List Archive Entries",
+	ToolGetArchiveEntry:   "Get Archive Entry",
+	ToolInspectParquet:    "Inspect Parquet File",
+	ToolPreviewTable:      "Preview Delimited Table",
 	ToolGetObject:         "Get Object",
 	ToolGetObjectMetadata: "Get Object Metadata",
 	ToolPresignURL:        "Generate Presigned URL",
 	ToolPutObject:         "Put Object",
 	ToolCopyObject:        "Copy Object",
 	ToolDeleteObject:      "Delete Object",
+	ToolGetQuota:          "Get Quota Usage",
+	ToolAddConnection:     "Add Connection",
+	ToolRemoveConnection:  "Remove Connection",
+	ToolTestConnection:    "Test Connection",
 }
 
 // DefaultTitle returns the default human-readable title for a tool.
@@ -18,7 +18,7 @@ func TestDefaultTitle(t *testing.T) {
 	})
 
 	t.Run("all tools have defaults", func(t *testing.T) {
-		for _, name := range AllTools() {
+		for _, name := range append(AllTools(), AdminTools()...) {
 			title := DefaultTitle(name)
 			if title == "" {
 				t.Errorf("tool %s has no default title", name)
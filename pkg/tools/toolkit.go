@@ -54,6 +54,10 @@ type Toolkit struct {
 	clients        map[string]S3Client
 	clientsMu      sync.RWMutex
 	manager        ConnectionManager
+	quotaReporter  QuotaReporter
+	healthChecker  HealthChecker
+
+	connectionAdmin ConnectionAdmin
 
 	// Configuration
 	defaultConnection string
@@ -75,6 +79,11 @@ type Toolkit struct {
 	transformers    *TransformerChain
 	registeredTools map[ToolName]bool
 
+	// Registrations of tools whose description names the connections
+	// lacking the feature they need
+	featureToolsMu sync.Mutex
+	featureTools   map[ToolName]*featureTool
+
 	// Logging
 	logger *slog.Logger
 }
@@ -92,6 +101,7 @@ func NewToolkit(client S3Client, opts ...Option) *Toolkit {
 		interceptors:    NewInterceptorChain(),
 		transformers:    NewTransformerChain(),
 		registeredTools: make(map[ToolName]bool),
+		featureTools:    make(map[ToolName]*featureTool),
 		maxGetSize:      DefaultMaxGetSize,
 		maxPutSize:      DefaultMaxPutSize,
 		readOnly:        false,
@@ -114,9 +124,11 @@ func NewToolkit(client S3Client, opts ...Option) *Toolkit {
 	return t
 }
 
-// RegisterAll adds all S3 tools to the given MCP server.
+// RegisterAll adds all S3 tools to the given MCP server, plus the admin tools
+// when a ConnectionAdmin is configured.
 func (t *Toolkit) RegisterAll(server *mcp.Server) {
 	t.Register(server, AllTools()...)
+	t.Register(server, AdminTools()...)
 }
 
 // Register adds specific tools by name to the MCP server.
@@ -140,8 +152,13 @@ func (t *Toolkit) registerTool(server *mcp.Server, name ToolName, cfg *toolConfi
 	if t.registeredTools[name] || t.isToolDisabled(name) {
 		return
 	}
+	// Admin tools are opt-in: they only exist when an admin is configured.
+	if IsAdminTool(name) && t.connectionAdmin == nil {
+		return
+	}
 	t.dispatchToolRegistration(server, name, cfg)
 	t.registeredTools[name] = true
+	t.trackFeatureTool(server, name, cfg)
 }
 
 func (t *Toolkit) dispatchToolRegistration(server *mcp.Server, name ToolName, cfg *toolConfig) {
@@ -150,6 +167,22 @@ func (t *Toolkit) dispatchToolRegistration(server *mcp.Server, name ToolName, cf
 		t.registerListBucketsTool(server, cfg)
 	case ToolListObjects:
 		t.registerListObjectsTool(server, cfg)
+	case ToolFindObjects:
+		t.registerFindObjectsTool(server, cfg)
+	case ToolSummarizePrefix:
+		t.registerSummarizePrefixTool(server, cfg)
+	case ToolTree:
+		t.registerTreeTool(server, cfg)
+	case ToolGrep:
+		t.registerGrepTool(server, cfg)
+	case ToolListArchive:
+		t.registerListArchiveTool(server, cfg)
+	case ToolGetArchiveEntry:
+		t.registerGetArchiveEntryTool(server, cfg)
+	case ToolInspectParquet:
+		t.registerInspectParquetTool(server, cfg)
+	case ToolPreviewTable:
+		t.registerPreviewTableTool(server, cfg)
 	case ToolGetObject:
 		t.registerGetObjectTool(server, cfg)
 	case ToolGetObjectMetadata:
@@ -164,6 +197,14 @@ func (t *Toolkit) dispatchToolRegistration(server *mcp.Server, name ToolName, cf
 		t.registerPresignURLTool(server, cfg)
 	case ToolListConnections:
 		t.registerListConnectionsTool(server, cfg)
+	case ToolGetQuota:
+		t.registerGetQuotaTool(server, cfg)
+	case ToolAddConnection:
+		t.registerAddConnectionTool(server, cfg)
+	case ToolRemoveConnection:
+		t.registerRemoveConnectionTool(server, cfg)
+	case ToolTestConnection:
+		t.registerTestConnectionTool(server, cfg)
 	}
 }
 
@@ -246,6 +287,21 @@ func (t *Toolkit) GetClient(name string) (S3Client, error) {
 	return nil, fmt.Errorf("%w: %s", ErrConnectionNotFound, name)
 }
 
+// holdClient returns the S3 client for the given connection name, as
+// GetClient does, held open until release is called. Handlers hold their
+// client for the whole call, so a client evicted between the S3 calls of a
+// walk is not closed under it.
+func (t *Toolkit) holdClient(name string) (client S3Client, release func(), err error) {
+	client, err = t.GetClient(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if holder, ok := clientAs[ClientHolder](client); ok {
+		return client, holder.Hold(), nil
+	}
+	return client, func() {}, nil
+}
+
 // ListConnections returns a list of available connection names.
 // When a ConnectionManager is configured, it returns all configured connections
 // (including those not yet lazily created). Otherwise, returns only cached clients.
@@ -321,7 +377,7 @@ func (t *Toolkit) wrapHandler(
 	}
 
 	return func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
-		tc := t.createToolContext(toolName)
+		tc := t.createToolContext(toolName, req)
 		ctx = WithToolContext(ctx, tc)
 
 		req, blocked := t.runInterceptors(ctx, tc, req, toolName)
@@ -345,6 +401,17 @@ func (t *Toolkit) wrapHandler(
 	}
 }
 
+// typedOutput returns out when it is a non-nil *T, or else nil. Tool handlers
+// registered with mcp.AddTool return their output as any: the SDK replaces a
+// nil *T with the zero T as structured content, which would overwrite the
+// classification ErrorResultFor sets on error results.
+func typedOutput[T any](out any) any {
+	if typed, ok := out.(*T); ok && typed != nil {
+		return typed
+	}
+	return nil
+}
+
 func (t *Toolkit) collectMiddlewares(toolName ToolName, cfg *toolConfig) []ToolMiddleware {
 	var all []ToolMiddleware
 	all = append(all, t.middleware.All()...)
@@ -357,8 +424,16 @@ func (t *Toolkit) collectMiddlewares(toolName ToolName, cfg *toolConfig) []ToolM
 	return all
 }
 
-func (t *Toolkit) createToolContext(toolName ToolName) *ToolContext {
-	tc := NewToolContext(toolName, t.defaultConnection)
+// createToolContext builds the ToolContext for a request. ConnectionName is
+// the connection named in the request arguments, falling back to the default.
+func (t *Toolkit) createToolContext(toolName ToolName, req *mcp.CallToolRequest) *ToolContext {
+	connection := requestConnection(req)
+	if connection == "" {
+		connection = t.defaultConnection
+	}
+	tc := NewToolContext(toolName, connection)
+	tc.SessionID = requestSessionID(req)
+	tc.Principal = requestPrincipal(req)
 	tc.StartTime = time.Now()
 	return tc
 }
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
 )
 
 func TestNewToolkit(t *testing.T) {
@@ -444,6 +446,35 @@ func TestToolkit_GetClient_ProviderReflectsRemoval(t *testing.T) {
 	}
 }
 
+// holdingClient counts holds that have not been released.
+type holdingClient struct {
+	*MockS3Client
+	held int
+}
+
+func (h *holdingClient) Hold() func() {
+	h.held++
+	return func() { h.held-- }
+}
+
+func TestToolkit_HandlersHoldClient(t *testing.T) {
+	held := &holdingClient{MockS3Client: NewMockS3Client("held")}
+	held.ListBucketsFunc = func(ctx context.Context) ([]client.BucketInfo, error) {
+		if held.held != 1 {
+			t.Errorf("held = %d during the call, want 1", held.held)
+		}
+		return nil, nil
+	}
+	toolkit := NewToolkit(held)
+
+	if result, _, _ := toolkit.handleListBuckets(context.Background(), nil, ListBucketsInput{}); result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	if held.held != 0 {
+		t.Errorf("held = %d after the call, want 0", held.held)
+	}
+}
+
 func makeTestRequest(args map[string]any) *mcp.CallToolRequest {
 	req := &mcp.CallToolRequest{
 		Params: &mcp.CallToolParamsRaw{},
@@ -808,3 +839,31 @@ func TestWithConnectionManager(t *testing.T) {
 		t.Error("expected manager to be set")
 	}
 }
+
+func TestToolkit_createToolContext(t *testing.T) {
+	toolkit := NewToolkit(NewMockS3Client("default"))
+
+	t.Run("uses connection from arguments", func(t *testing.T) {
+		tc := toolkit.createToolContext(ToolGetObject, makeTestRequest(map[string]any{"connection": "staging"}))
+		if tc.ConnectionName != "staging" {
+			t.Errorf("ConnectionName = %q, want %q", tc.ConnectionName, "staging")
+		}
+	})
+
+	t.Run("falls back to default connection", func(t *testing.T) {
+		tc := toolkit.createToolContext(ToolGetObject, makeTestRequest(nil))
+		if tc.ConnectionName != "default" {
+			t.Errorf("ConnectionName = %q, want %q", tc.ConnectionName, "default")
+		}
+		if tc.SessionID != "" || tc.Principal != "" {
+			t.Errorf("expected empty session and principal, got %q/%q", tc.SessionID, tc.Principal)
+		}
+	})
+
+	t.Run("handles nil request", func(t *testing.T) {
+		tc := toolkit.createToolContext(ToolGetObject, nil)
+		if tc.ConnectionName != "default" {
+			t.Errorf("ConnectionName = %q, want %q", tc.ConnectionName, "default")
+		}
+	})
+}
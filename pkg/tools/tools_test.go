@@ -2,10 +2,17 @@ package tools
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
 )
 
 func TestListBuckets(t *testing.T) {
@@ -79,6 +86,75 @@ func TestListObjects(t *testing.T) {
 	})
 }
 
+func TestListObjects_Sorted(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	objects := []client.ObjectInfo{
+		{Key: "logs/a.log", Size: 30, LastModified: base.Add(1 * time.Hour)},
+		{Key: "logs/b.log", Size: 10, LastModified: base.Add(5 * time.Hour)},
+		{Key: "logs/c.log", Size: 50, LastModified: base.Add(3 * time.Hour)},
+		{Key: "logs/d.log", Size: 10, LastModified: base.Add(4 * time.Hour)},
+		{Key: "logs/e.log", Size: 20, LastModified: base.Add(2 * time.Hour)},
+	}
+	mock := NewMockS3Client("test")
+	mock.ListObjectsFunc = pagedListing(objects, 2, nil)
+	toolkit := NewToolkit(mock)
+
+	list := func(input ListObjectsInput) *ListObjectsResult {
+		t.Helper()
+		input.Bucket = "bucket"
+		result, out, _ := toolkit.handleListObjects(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error: %v", result.Content)
+		}
+		return out.(*ListObjectsResult)
+	}
+	keys := func(r *ListObjectsResult) string {
+		var keys []string
+		for _, obj := range r.Objects {
+			keys = append(keys, strings.TrimPrefix(obj.Key, "logs/"))
+		}
+		return strings.Join(keys, ",")
+	}
+
+	tests := []struct {
+		input ListObjectsInput
+		want  string
+	}{
+		{ListObjectsInput{SortBy: SortByLastModified, MaxKeys: 2}, "b.log,d.log"},
+		{ListObjectsInput{SortBy: SortByLastModified, Order: "ASC", MaxKeys: 2}, "a.log,e.log"},
+		{ListObjectsInput{SortBy: SortBySize, MaxKeys: 3}, "c.log,a.log,e.log"},
+		{ListObjectsInput{SortBy: SortBySize, Order: OrderAsc, MaxKeys: 3}, "b.log,d.log,e.log"},
+		{ListObjectsInput{SortBy: SortByKey, Order: OrderDesc, MaxKeys: 2}, "e.log,d.log"},
+		{ListObjectsInput{SortBy: SortByKey}, "a.log,b.log,c.log,d.log,e.log"},
+	}
+	for _, tt := range tests {
+		got := list(tt.input)
+		if keys(got) != tt.want {
+			t.Errorf("%s %s = %s, want %s", tt.input.SortBy, tt.input.Order, keys(got), tt.want)
+		}
+		if got.Exhaustive == nil || !*got.Exhaustive || got.Scanned != len(objects) {
+			t.Errorf("%s: exhaustive = %v scanned = %d", tt.input.SortBy, got.Exhaustive, got.Scanned)
+		}
+	}
+
+	got := list(ListObjectsInput{SortBy: SortByLastModified, MaxKeys: 1, ScanLimit: 3})
+	if keys(got) != "b.log" || *got.Exhaustive || !got.IsTruncated || got.Scanned != 3 {
+		t.Errorf("budgeted sort = %s exhaustive = %v truncated = %v scanned = %d",
+			keys(got), *got.Exhaustive, got.IsTruncated, got.Scanned)
+	}
+
+	for _, input := range []ListObjectsInput{
+		{Bucket: "bucket", SortBy: "name"},
+		{Bucket: "bucket", SortBy: SortBySize, Order: "up"},
+		{Bucket: "bucket", SortBy: SortBySize, Delimiter: "/"},
+		{Bucket: "bucket", SortBy: SortBySize, ContinuationToken: "2"},
+	} {
+		if result, _, _ := toolkit.handleListObjects(context.Background(), nil, input); !result.IsError {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
+
 func TestGetObject(t *testing.T) {
 	mock := NewMockS3Client("test")
 	mock.AddObject("test-bucket", "text.txt", []byte("Hello, World!"), "text/plain")
@@ -285,7 +361,7 @@ func TestListConnections(t *testing.T) {
 	mock := NewMockS3Client("default")
 	toolkit := NewToolkit(mock, WithDefaultConnection("default"))
 
-	result, _, err := toolkit.handleListConnections(context.Background(), nil)
+	result, _, err := toolkit.handleListConnections(context.Background(), nil, ListConnectionsInput{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -295,6 +371,287 @@ func TestListConnections(t *testing.T) {
 	}
 }
 
+// circuitMockClient reports a fixed circuit breaker state.
+type circuitMockClient struct {
+	*MockS3Client
+	state client.CircuitState
+}
+
+func (c *circuitMockClient) CircuitState() (client.CircuitState, bool) { return c.state, true }
+
+// wrappingMockClient wraps another client the way multiserver's limiter does.
+type wrappingMockClient struct {
+	S3Client
+}
+
+func (w *wrappingMockClient) Unwrap() S3Client { return w.S3Client }
+
+func TestListConnections_CircuitState(t *testing.T) {
+	retryAt := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+	inner := &circuitMockClient{
+		MockS3Client: NewMockS3Client("minio"),
+		state:        client.CircuitState{State: client.CircuitOpen, ConsecutiveFailures: 5, RetryAt: retryAt},
+	}
+	toolkit := NewToolkit(&wrappingMockClient{S3Client: inner})
+
+	_, out, err := toolkit.handleListConnections(context.Background(), nil, ListConnectionsInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, ok := out.(*ListConnectionsResult)
+	if !ok || len(result.Connections) != 1 {
+		t.Fatalf("unexpected result: %+v", out)
+	}
+	circuit := result.Connections[0].Circuit
+	if circuit == nil {
+		t.Fatal("expected circuit state to be reported")
+	}
+	if circuit.State != client.CircuitOpen || circuit.ConsecutiveFailures != 5 || circuit.RetryAt != "2026-01-01T12:00:30Z" {
+		t.Errorf("circuit = %+v", circuit)
+	}
+
+	// Clients without a breaker report no circuit.
+	plain := NewToolkit(NewMockS3Client("plain"))
+	_, out, _ = plain.handleListConnections(context.Background(), nil, ListConnectionsInput{})
+	if c := out.(*ListConnectionsResult).Connections[0].Circuit; c != nil {
+		t.Errorf("expected no circuit for plain client, got %+v", c)
+	}
+}
+
+// credentialMockClient reports a fixed credential state.
+type credentialMockClient struct {
+	*MockS3Client
+	state client.CredentialState
+}
+
+func (c *credentialMockClient) CredentialState() (client.CredentialState, bool) { return c.state, true }
+
+func TestListConnections_CredentialState(t *testing.T) {
+	expires := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	toolkit := NewToolkit(&credentialMockClient{
+		MockS3Client: NewMockS3Client("sts"),
+		state:        client.CredentialState{Source: "AssumeRoleProvider", CanExpire: true, Expires: expires},
+	})
+
+	_, out, err := toolkit.handleListConnections(context.Background(), nil, ListConnectionsInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creds := out.(*ListConnectionsResult).Connections[0].Credentials
+	if creds == nil {
+		t.Fatal("expected credential state to be reported")
+	}
+	if creds.Source != "AssumeRoleProvider" || !creds.Temporary || !creds.Expired ||
+		creds.ExpiresAt != expires.Format("2006-01-02T15:04:05Z") {
+		t.Errorf("credentials = %+v", creds)
+	}
+}
+
+// fakeHealthChecker returns fixed health results by connection name and
+// records which connections were probed.
+type fakeHealthChecker struct {
+	results map[string]HealthStatus
+	cached  map[string]HealthStatus
+	probed  []string
+}
+
+func (f *fakeHealthChecker) HealthCheck(_ context.Context, names ...string) []HealthStatus {
+	f.probed = append(f.probed, names...)
+	statuses := make([]HealthStatus, len(names))
+	for i, name := range names {
+		statuses[i] = f.results[name]
+	}
+	return statuses
+}
+
+func (f *fakeHealthChecker) CachedHealth(name string) (HealthStatus, bool) {
+	h, ok := f.cached[name]
+	return h, ok
+}
+
+// initializedManager is a ConnectionManager that reports which clients exist.
+type initializedManager struct {
+	mockConnectionManager
+	initialized map[string]bool
+}
+
+func (m *initializedManager) IsClientInitialized(name string) bool { return m.initialized[name] }
+
+func TestListConnections_Health(t *testing.T) {
+	checkedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	checker := &fakeHealthChecker{
+		results: map[string]HealthStatus{
+			"default": {Connection: "default", Status: HealthHealthy, Initialized: true, Latency: 42 * time.Millisecond, CheckedAt: checkedAt},
+			"broken": {Connection: "broken", Status: HealthUnhealthy, CheckedAt: checkedAt,
+				LastError: "connection refused", LastErrorAt: checkedAt},
+			"idle": {Connection: "idle", Status: HealthHealthy, CheckedAt: checkedAt},
+		},
+		cached: map[string]HealthStatus{
+			"broken": {Connection: "broken", Status: HealthUnhealthy, CheckedAt: checkedAt, LastError: "connection refused"},
+		},
+	}
+	var created []string
+	toolkit := NewToolkit(nil,
+		WithDefaultConnection("default"),
+		WithClientProvider(func(name string) (S3Client, error) {
+			created = append(created, name)
+			if name == "broken" {
+				return nil, errors.New("connection refused")
+			}
+			return NewMockS3Client(name), nil
+		}),
+		WithConnectionManager(&initializedManager{
+			mockConnectionManager: mockConnectionManager{connections: []string{"default", "broken", "idle"}, defaultConnection: "default"},
+			initialized:           map[string]bool{"default": true},
+		}),
+		WithHealthChecker(checker),
+	)
+
+	// Connections not used yet report their cached health only.
+	_, out, err := toolkit.handleListConnections(context.Background(), nil, ListConnectionsInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := out.(*ListConnectionsResult)
+	if len(result.Connections) != 3 {
+		t.Fatalf("expected every connection to be reported, got %+v", result.Connections)
+	}
+	if !slices.Equal(checker.probed, []string{"default"}) || !slices.Equal(created, []string{"default"}) {
+		t.Errorf("probed = %v created = %v, want only default", checker.probed, created)
+	}
+
+	healthy := result.Connections[0].Health
+	if healthy == nil || healthy.Status != HealthHealthy || !healthy.Initialized || healthy.LatencyMs != 42 ||
+		healthy.CheckedAt != "2026-01-01T12:00:00Z" || result.Connections[0].Region == "" {
+		t.Errorf("default = %+v health = %+v", result.Connections[0], healthy)
+	}
+	broken := result.Connections[1]
+	if broken.Name != "broken" || broken.Health == nil || broken.Health.Status != HealthUnhealthy ||
+		broken.Health.LastError != "connection refused" {
+		t.Errorf("broken connection = %+v", broken)
+	}
+	if idle := result.Connections[2]; idle.Name != "idle" || idle.Health != nil {
+		t.Errorf("unchecked connection = %+v", idle)
+	}
+
+	// probe checks and creates every connection.
+	checker.probed, created = nil, nil
+	_, out, _ = toolkit.handleListConnections(context.Background(), nil, ListConnectionsInput{Probe: true})
+	result = out.(*ListConnectionsResult)
+	if !slices.Equal(checker.probed, []string{"default", "broken", "idle"}) || len(created) != 3 {
+		t.Errorf("probed = %v created = %v, want every connection", checker.probed, created)
+	}
+	if idle := result.Connections[2]; idle.Health == nil || idle.Health.Status != HealthHealthy || idle.Region == "" {
+		t.Errorf("probed idle connection = %+v", idle)
+	}
+}
+
+// resultText returns the text of the first content item of a result.
+func resultText(result *mcp.CallToolResult) string {
+	if len(result.Content) == 0 {
+		return ""
+	}
+	if text, ok := result.Content[0].(*mcp.TextContent); ok {
+		return text.Text
+	}
+	return ""
+}
+
+func TestConnectionGuardrails(t *testing.T) {
+	prod := NewMockS3Client("prod")
+	prod.config.ReadOnly = true
+	prod.config.MaxGetSize = 4
+	prod.config.AllowedBuckets = []string{"reports"}
+	prod.config.AllowedPrefixes = []string{"public/"}
+	prod.Buckets = []client.BucketInfo{{Name: "reports"}, {Name: "secrets"}}
+	prod.AddObject("reports", "public/a.txt", []byte("ok"), "text/plain")
+	prod.AddObject("reports", "public/big.txt", []byte("too large"), "text/plain")
+
+	scratch := NewMockS3Client("scratch")
+	scratch.config.MaxPutSize = 4
+
+	toolkit := NewToolkit(prod, WithMaxGetSize(1024))
+	toolkit.AddClient("scratch", scratch)
+	ctx := context.Background()
+
+	t.Run("read-only connection blocks writes", func(t *testing.T) {
+		result, _, _ := toolkit.handlePutObject(ctx, nil, PutObjectInput{
+			Bucket: "reports", Key: "public/new.txt", Content: "x", Connection: "prod",
+		})
+		if !result.IsError || !strings.Contains(resultText(result), "connection prod is read-only") {
+			t.Errorf("expected read-only error, got %s", resultText(result))
+		}
+		result, _, _ = toolkit.handlePresignURL(ctx, nil, PresignURLInput{
+			Bucket: "reports", Key: "public/new.txt", Method: methodPut,
+		})
+		if !result.IsError {
+			t.Error("expected presigned PUT to be blocked on a read-only connection")
+		}
+	})
+
+	t.Run("writable connection accepts writes under its own limit", func(t *testing.T) {
+		result, _, _ := toolkit.handlePutObject(ctx, nil, PutObjectInput{
+			Bucket: "tmp", Key: "a", Content: "abcd", Connection: "scratch",
+		})
+		if result.IsError {
+			t.Errorf("unexpected error: %s", resultText(result))
+		}
+		result, _, _ = toolkit.handlePutObject(ctx, nil, PutObjectInput{
+			Bucket: "tmp", Key: "b", Content: "abcde", Connection: "scratch",
+		})
+		if !result.IsError || !strings.Contains(resultText(result), "exceeds limit of 4 bytes") {
+			t.Errorf("expected size limit error, got %s", resultText(result))
+		}
+	})
+
+	t.Run("connection get limit overrides toolkit limit", func(t *testing.T) {
+		result, _, _ := toolkit.handleGetObject(ctx, nil, GetObjectInput{Bucket: "reports", Key: "public/a.txt"})
+		if result.IsError {
+			t.Errorf("unexpected error: %s", resultText(result))
+		}
+		result, _, _ = toolkit.handleGetObject(ctx, nil, GetObjectInput{Bucket: "reports", Key: "public/big.txt"})
+		if !result.IsError {
+			t.Error("expected size limit error")
+		}
+	})
+
+	t.Run("bucket and prefix allow lists", func(t *testing.T) {
+		result, _, _ := toolkit.handleGetObjectMetadata(ctx, nil, GetObjectMetadataInput{Bucket: "secrets", Key: "public/a.txt"})
+		if !result.IsError || !strings.Contains(resultText(result), "bucket secrets is not allowed") {
+			t.Errorf("expected bucket error, got %s", resultText(result))
+		}
+		result, _, _ = toolkit.handleGetObject(ctx, nil, GetObjectInput{Bucket: "reports", Key: "private/a.txt"})
+		if !result.IsError || !strings.Contains(resultText(result), "outside the allowed prefixes") {
+			t.Errorf("expected prefix error, got %s", resultText(result))
+		}
+		result, _, _ = toolkit.handleListObjects(ctx, nil, ListObjectsInput{Bucket: "reports"})
+		if !result.IsError {
+			t.Error("expected listing outside the allowed prefixes to be blocked")
+		}
+		result, _, _ = toolkit.handleListObjects(ctx, nil, ListObjectsInput{Bucket: "reports", Prefix: "public/"})
+		if result.IsError {
+			t.Errorf("unexpected error: %s", resultText(result))
+		}
+	})
+
+	t.Run("list buckets is filtered", func(t *testing.T) {
+		_, out, _ := toolkit.handleListBuckets(ctx, nil, ListBucketsInput{})
+		buckets := out.(*ListBucketsResult).Buckets
+		if len(buckets) != 1 || buckets[0].Name != "reports" {
+			t.Errorf("buckets = %+v, want only reports", buckets)
+		}
+	})
+
+	t.Run("list connections reports read-only", func(t *testing.T) {
+		_, out, _ := toolkit.handleListConnections(ctx, nil, ListConnectionsInput{})
+		for _, info := range out.(*ListConnectionsResult).Connections {
+			if info.ReadOnly != (info.Name == "prod") {
+				t.Errorf("connection %s read_only = %v", info.Name, info.ReadOnly)
+			}
+		}
+	})
+}
+
 func TestIsTextContent(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -344,6 +701,39 @@ func TestIsTextContent(t *testing.T) {
 	}
 }
 
+func TestGetObject_Decompression(t *testing.T) {
+	mock := NewMockS3Client("test")
+	mock.AddObject("test-bucket", "app.log.gz", gzipBytes(t, "line 1\nline 2\n"), "application/gzip")
+	mock.AddObject("test-bucket", "big.gz", gzipBytes(t, strings.Repeat("é", 1000)), "application/gzip")
+	toolkit := NewToolkit(mock, WithMaxGetSize(101))
+
+	get := func(input GetObjectInput) *GetObjectResult {
+		t.Helper()
+		input.Bucket = "test-bucket"
+		result, out, _ := toolkit.handleGetObject(context.Background(), nil, input)
+		if result.IsError {
+			t.Fatalf("unexpected error: %v", result.Content)
+		}
+		return out.(*GetObjectResult)
+	}
+
+	got := get(GetObjectInput{Key: "app.log.gz"})
+	if got.Content != "line 1\nline 2\n" || got.IsBase64 || got.Compression != "gzip" ||
+		got.DecompressedSize != 14 || got.Size != int64(len(gzipBytes(t, "line 1\nline 2\n"))) || got.Truncated {
+		t.Errorf("decompressed result = %+v", got)
+	}
+
+	if raw := get(GetObjectInput{Key: "app.log.gz", Raw: true}); !raw.IsBase64 || raw.Compression != "" {
+		t.Errorf("raw result = %+v", raw)
+	}
+
+	// Output past the size limit is cut, on a rune boundary, and flagged.
+	big := get(GetObjectInput{Key: "big.gz"})
+	if !big.Truncated || big.IsBase64 || big.DecompressedSize != 100 || big.Content != strings.Repeat("é", 50) {
+		t.Errorf("limited result: truncated = %v base64 = %v size = %d", big.Truncated, big.IsBase64, big.DecompressedSize)
+	}
+}
+
 func TestGetObjectMetadata(t *testing.T) {
 	mock := NewMockS3Client("test")
 	mock.AddObject("test-bucket", "file.txt", []byte("content"), "text/plain")
@@ -569,7 +959,7 @@ func TestCheckPutSizeLimit(t *testing.T) {
 		mock := NewMockS3Client("test")
 		toolkit := NewToolkit(mock, WithMaxPutSize(0))
 
-		err := toolkit.checkPutSizeLimit([]byte("any size content"))
+		err := toolkit.checkPutSizeLimit(mock, []byte("any size content"))
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -579,7 +969,7 @@ func TestCheckPutSizeLimit(t *testing.T) {
 		mock := NewMockS3Client("test")
 		toolkit := NewToolkit(mock, WithMaxPutSize(100))
 
-		err := toolkit.checkPutSizeLimit([]byte("small"))
+		err := toolkit.checkPutSizeLimit(mock, []byte("small"))
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -589,7 +979,7 @@ func TestCheckPutSizeLimit(t *testing.T) {
 		mock := NewMockS3Client("test")
 		toolkit := NewToolkit(mock, WithMaxPutSize(5))
 
-		err := toolkit.checkPutSizeLimit([]byte("this is too long"))
+		err := toolkit.checkPutSizeLimit(mock, []byte("this is too long"))
 		if err == nil {
 			t.Error("expected error for content over limit")
 		}
@@ -671,6 +1061,9 @@ func TestToolRegistration_ViaServer(t *testing.T) {
 	mock := NewMockS3Client("test")
 	mock.AddBucket("my-bucket", time.Now())
 	mock.AddObject("my-bucket", "hello.txt", []byte("world"), "text/plain")
+	mock.AddObject("my-bucket", "app.zip", zipBytes(t, archiveFile{"README.md", []byte("hi")}), "application/zip")
+	mock.AddObject("my-bucket", "events.parquet", parquetBytes(t, 3, 10), "application/octet-stream")
+	mock.AddObject("my-bucket", "users.csv", []byte("id,name\n1,Ada\n"), "text/csv")
 
 	tk := NewToolkit(mock, WithDefaultConnection("test"))
 	cs := connectToolkit(t, tk)
@@ -691,6 +1084,46 @@ func TestToolRegistration_ViaServer(t *testing.T) {
 			tool: "s3_list_objects",
 			args: map[string]any{"bucket": "my-bucket"},
 		},
+		{
+			name: "find_objects",
+			tool: "s3_find_objects",
+			args: map[string]any{"bucket": "my-bucket", "pattern": "*.txt"},
+		},
+		{
+			name: "summarize_prefix",
+			tool: "s3_summarize_prefix",
+			args: map[string]any{"bucket": "my-bucket", "prefix": "data/"},
+		},
+		{
+			name: "tree",
+			tool: "s3_tree",
+			args: map[string]any{"bucket": "my-bucket", "depth": 3},
+		},
+		{
+			name: "grep",
+			tool: "s3_grep",
+			args: map[string]any{"bucket": "my-bucket", "pattern": "ERROR"},
+		},
+		{
+			name: "list_archive",
+			tool: "s3_list_archive",
+			args: map[string]any{"bucket": "my-bucket", "key": "app.zip"},
+		},
+		{
+			name: "get_archive_entry",
+			tool: "s3_get_archive_entry",
+			args: map[string]any{"bucket": "my-bucket", "key": "app.zip", "entry": "README.md"},
+		},
+		{
+			name: "inspect_parquet",
+			tool: "s3_inspect_parquet",
+			args: map[string]any{"bucket": "my-bucket", "key": "events.parquet", "rows": 2},
+		},
+		{
+			name: "preview_table",
+			tool: "s3_preview_table",
+			args: map[string]any{"bucket": "my-bucket", "key": "users.csv", "rows": 1},
+		},
 		{
 			name: "get_object",
 			tool: "s3_get_object",
@@ -739,6 +1172,11 @@ func TestToolRegistration_ViaServer(t *testing.T) {
 			tool: "s3_list_connections",
 			args: nil,
 		},
+		{
+			name: "get_quota",
+			tool: "s3_get_quota",
+			args: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -780,3 +1218,285 @@ func TestMiddlewareFuncWrapper_NilFunctions(t *testing.T) {
 		t.Error("After() should return same result when function is nil")
 	}
 }
+
+// staticQuotaReporter returns fixed usage and records the subject it was asked about.
+type staticQuotaReporter struct {
+	subject QuotaSubject
+}
+
+func (r *staticQuotaReporter) QuotaUsage(subject QuotaSubject) []QuotaUsage {
+	r.subject = subject
+	return []QuotaUsage{{
+		Scope:    QuotaScopeConnection,
+		Key:      subject.Connection,
+		Requests: QuotaCounter{Used: 3, Limit: 10},
+	}}
+}
+
+func TestGetQuota(t *testing.T) {
+	t.Run("disabled without reporter", func(t *testing.T) {
+		toolkit := NewToolkit(NewMockS3Client("test"))
+		result, _, err := toolkit.handleGetQuota(context.Background(), nil, GetQuotaInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError {
+			t.Error("expected error result when quotas are disabled")
+		}
+	})
+
+	t.Run("reports usage for the default connection", func(t *testing.T) {
+		reporter := &staticQuotaReporter{}
+		toolkit := NewToolkit(NewMockS3Client("test"), WithQuotaReporter(reporter))
+		result, out, err := toolkit.handleGetQuota(context.Background(), nil, GetQuotaInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("unexpected error result: %v", result.Content)
+		}
+		if reporter.subject.Connection != "test" {
+			t.Errorf("subject connection = %q, want %q", reporter.subject.Connection, "test")
+		}
+		quota, ok := out.(*GetQuotaResult)
+		if !ok || len(quota.Quotas) != 1 || quota.Quotas[0].Requests.Used != 3 {
+			t.Errorf("unexpected result: %+v", out)
+		}
+	})
+}
+
+func TestHandlersRecordBytes(t *testing.T) {
+	mock := NewMockS3Client("test")
+	mock.AddObject("bucket", "file.txt", []byte("hello"), "text/plain")
+	toolkit := NewToolkit(mock)
+
+	tc := NewToolContext(ToolGetObject, "test")
+	ctx := WithToolContext(context.Background(), tc)
+	if _, _, err := toolkit.handleGetObject(ctx, nil, GetObjectInput{Bucket: "bucket", Key: "file.txt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tc.BytesRead() != 5 {
+		t.Errorf("BytesRead() = %d, want 5", tc.BytesRead())
+	}
+
+	tc = NewToolContext(ToolPutObject, "test")
+	ctx = WithToolContext(context.Background(), tc)
+	if _, _, err := toolkit.handlePutObject(ctx, nil, PutObjectInput{Bucket: "bucket", Key: "new.txt", Content: "abc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tc.BytesWritten() != 3 {
+		t.Errorf("BytesWritten() = %d, want 3", tc.BytesWritten())
+	}
+}
+
+// fakeConnectionAdmin records admin calls against a fixed set of connections.
+type fakeConnectionAdmin struct {
+	added   map[string]map[string]any
+	removed []string
+	err     error
+}
+
+func (f *fakeConnectionAdmin) AddConnection(_ context.Context, name string, config map[string]any, _, _ bool) (HealthStatus, error) {
+	if f.err != nil {
+		return HealthStatus{}, f.err
+	}
+	f.added[name] = config
+	return HealthStatus{Connection: name, Status: HealthHealthy, Latency: 5 * time.Millisecond}, nil
+}
+
+func (f *fakeConnectionAdmin) RemoveConnection(_ context.Context, name string, _ bool) error {
+	f.removed = append(f.removed, name)
+	return f.err
+}
+
+func (f *fakeConnectionAdmin) TestConnection(_ context.Context, name string, _ map[string]any) (HealthStatus, error) {
+	return HealthStatus{Connection: name, Status: HealthUnhealthy, LastError: "connection refused"}, nil
+}
+
+func TestConnectionAdminTools(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not registered without an admin", func(t *testing.T) {
+		cs := connectToolkit(t, NewToolkit(NewMockS3Client("test")))
+		tools, err := cs.ListTools(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tool := range tools.Tools {
+			if IsAdminTool(ToolName(tool.Name)) {
+				t.Errorf("admin tool %s registered without an admin", tool.Name)
+			}
+		}
+	})
+
+	admin := &fakeConnectionAdmin{added: map[string]map[string]any{}}
+	tk := NewToolkit(NewMockS3Client("test"), WithConnectionAdmin(admin))
+	cs := connectToolkit(t, tk)
+
+	call := func(tool string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: tool, Arguments: args})
+		if err != nil {
+			t.Fatalf("CallTool(%s) error: %v", tool, err)
+		}
+		return result
+	}
+
+	result := call("s3_add_connection", map[string]any{"name": "minio", "config": map[string]any{"endpoint": "http://minio:9000"}})
+	if result.IsError || admin.added["minio"]["endpoint"] != "http://minio:9000" {
+		t.Errorf("add: %s", resultText(result))
+	}
+	if !strings.Contains(resultText(result), `"action": "added"`) {
+		t.Errorf("add result = %s", resultText(result))
+	}
+
+	result = call("s3_add_connection", map[string]any{"name": "test", "config": map[string]any{}})
+	if !result.IsError || !strings.Contains(resultText(result), "replace") {
+		t.Errorf("adding an existing connection without replace should fail: %s", resultText(result))
+	}
+
+	result = call("s3_remove_connection", map[string]any{"name": "minio"})
+	if result.IsError || len(admin.removed) != 1 {
+		t.Errorf("remove: %s", resultText(result))
+	}
+
+	result = call("s3_test_connection", map[string]any{"config": map[string]any{"endpoint": "http://x"}})
+	if result.IsError || !strings.Contains(resultText(result), "connection refused") {
+		t.Errorf("test: %s", resultText(result))
+	}
+
+	admin.err = errors.New("probe failed: timeout")
+	result = call("s3_add_connection", map[string]any{"name": "other", "config": map[string]any{}})
+	if !result.IsError || !strings.Contains(resultText(result), "timeout") {
+		t.Errorf("admin error should be reported: %s", resultText(result))
+	}
+}
+
+func TestServedBy(t *testing.T) {
+	mock := NewMockS3Client("replicated")
+	mock.GetObjectFunc = func(ctx context.Context, bucket, key string) (*client.ObjectContent, error) {
+		RecordServedBy(ctx, "west")
+		return &client.ObjectContent{Body: []byte("hi"), ContentType: "text/plain"}, nil
+	}
+	mock.PutObjectFunc = func(ctx context.Context, input *client.PutObjectInput) (*client.PutObjectOutput, error) {
+		RecordServedBy(ctx, "east")
+		return &client.PutObjectOutput{}, nil
+	}
+	mock.GetObjectMetadataFunc = func(ctx context.Context, bucket, key string) (*client.ObjectMetadata, error) {
+		return &client.ObjectMetadata{Size: 2}, nil
+	}
+	toolkit := NewToolkit(mock)
+	ctx := context.Background()
+
+	_, out, _ := toolkit.handleGetObject(ctx, nil, GetObjectInput{Bucket: "b", Key: "k"})
+	if got := out.(*GetObjectResult).ServedBy; got != "west" {
+		t.Errorf("get served_by = %q, want west", got)
+	}
+	_, out, _ = toolkit.handlePutObject(ctx, nil, PutObjectInput{Bucket: "b", Key: "k", Content: "hi"})
+	if got := out.(*PutObjectResult).ServedBy; got != "east" {
+		t.Errorf("put served_by = %q, want east", got)
+	}
+
+	// Plain connections record nothing.
+	_, out, _ = toolkit.handleGetObjectMetadata(ctx, nil, GetObjectMetadataInput{Bucket: "b", Key: "k"})
+	if meta := out.(*GetObjectMetadataResult); meta.ServedBy != "" {
+		t.Errorf("metadata served_by = %q, want empty", meta.ServedBy)
+	}
+
+	// Recording without a recorder is a no-op.
+	RecordServedBy(ctx, "west")
+}
+
+// capabilityMockClient reports fixed capabilities, cached after the first
+// probe, and counts probes.
+type capabilityMockClient struct {
+	*MockS3Client
+	caps   client.Capabilities
+	probes atomic.Int32
+}
+
+func (c *capabilityMockClient) Capabilities(context.Context) client.Capabilities {
+	c.probes.Add(1)
+	return c.caps
+}
+
+func (c *capabilityMockClient) CachedCapabilities() (client.Capabilities, bool) {
+	return c.caps, c.probes.Load() > 0
+}
+
+func TestCapabilities(t *testing.T) {
+	limited := &capabilityMockClient{
+		MockS3Client: NewMockS3Client("public"),
+		caps:         client.Capabilities{Versioning: true, Tagging: true, ObjectLock: true, ConditionalWrites: true},
+	}
+	toolkit := NewToolkit(&wrappingMockClient{S3Client: limited})
+
+	result, _, _ := toolkit.handlePresignURL(context.Background(), nil, PresignURLInput{Bucket: "b", Key: "k"})
+	if !result.IsError {
+		t.Fatal("expected presigning to fail on a connection without presign support")
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "presign is not supported by connection public") {
+		t.Errorf("error = %q", text)
+	}
+	if n := limited.probes.Load(); n != 1 {
+		t.Errorf("presigning probed the connection %d times, want 1", n)
+	}
+
+	if desc := toolkit.getDescription(ToolPresignURL, nil); !strings.HasSuffix(desc, "Not supported by connections: public.") {
+		t.Errorf("description = %q, want a note naming the connection", desc)
+	}
+	if desc := toolkit.getDescription(ToolGetObject, nil); desc != DefaultDescription(ToolGetObject) {
+		t.Errorf("description of a tool without a required feature changed: %q", desc)
+	}
+
+	_, out, _ := toolkit.handleListConnections(context.Background(), nil, ListConnectionsInput{})
+	if got := out.(*ListConnectionsResult).Connections[0].Unsupported; !reflect.DeepEqual(got, []string{client.FeaturePresign}) {
+		t.Errorf("Unsupported = %v, want [presign]", got)
+	}
+	if n := limited.probes.Load(); n != 1 {
+		t.Errorf("listing connections probed %d more times, want 0", n-1)
+	}
+	_, _, _ = toolkit.handleListConnections(context.Background(), nil, ListConnectionsInput{Probe: true})
+	if n := limited.probes.Load(); n != 2 {
+		t.Errorf("listing connections with probe probed %d times, want 1", n-1)
+	}
+
+	// A probe on first use updates the description registered on a server.
+	lazy := &capabilityMockClient{MockS3Client: NewMockS3Client("public")}
+	cs := connectToolkit(t, NewToolkit(&wrappingMockClient{S3Client: lazy}))
+	presignDescription := func() string {
+		t.Helper()
+		tools, err := cs.ListTools(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tool := range tools.Tools {
+			if tool.Name == string(ToolPresignURL) {
+				return tool.Description
+			}
+		}
+		t.Fatal("presign tool not registered")
+		return ""
+	}
+	if desc := presignDescription(); strings.Contains(desc, "Not supported") {
+		t.Errorf("description before any probe = %q", desc)
+	}
+	_, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      string(ToolPresignURL),
+		Arguments: map[string]any{"bucket": "b", "key": "k"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc := presignDescription(); !strings.HasSuffix(desc, "Not supported by connections: public.") {
+		t.Errorf("description after the probe = %q", desc)
+	}
+
+	// Connections whose provider rules a feature out report it before any probe.
+	r2 := NewMockS3Client("r2")
+	r2.config.Provider = client.ProviderR2
+	_, out, _ = NewToolkit(r2).handleListConnections(context.Background(), nil, ListConnectionsInput{})
+	if got := out.(*ListConnectionsResult).Connections[0].Unsupported; !slices.Contains(got, client.FeatureVersioning) {
+		t.Errorf("Unsupported = %v, want versioning", got)
+	}
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// topN keeps the n greatest items pushed to it, ordered by less, in a min-heap
+// so memory stays bounded however many items are seen.
+type topN[T any] struct {
+	h topHeap[T]
+}
+
+// newTopN returns a topN that keeps at most n items.
+func newTopN[T any](n int, less func(a, b T) bool) *topN[T] {
+	return &topN[T]{h: topHeap[T]{n: n, less: less}}
+}
+
+// Push offers x, replacing the smallest kept item when the heap is full and x
+// is greater.
+func (t *topN[T]) Push(x T) {
+	switch {
+	case t.h.n <= 0:
+	case len(t.h.items) < t.h.n:
+		heap.Push(&t.h, x)
+	case t.h.less(t.h.items[0], x):
+		t.h.items[0] = x
+		heap.Fix(&t.h, 0)
+	}
+}
+
+// Len returns the number of items kept.
+func (t *topN[T]) Len() int {
+	return len(t.h.items)
+}
+
+// Sorted returns the kept items, greatest first.
+func (t *topN[T]) Sorted() []T {
+	items := slices.Clone(t.h.items)
+	slices.SortStableFunc(items, func(a, b T) int {
+		switch {
+		case t.h.less(b, a):
+			return -1
+		case t.h.less(a, b):
+			return 1
+		}
+		return 0
+	})
+	return items
+}
+
+// topHeap implements heap.Interface for topN.
+type topHeap[T any] struct {
+	n     int
+	less  func(a, b T) bool
+	items []T
+}
+
+func (h *topHeap[T]) Len() int           { return len(h.items) }
+func (h *topHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *topHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *topHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+
+func (h *topHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
@@ -0,0 +1,25 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTopN(t *testing.T) {
+	top := newTopN(3, func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 1, 9, 3, 7, 9, 2} {
+		top.Push(v)
+	}
+	if got, want := top.Sorted(), []int{9, 9, 7}; !slices.Equal(got, want) {
+		t.Errorf("Sorted() = %v, want %v", got, want)
+	}
+	if top.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", top.Len())
+	}
+
+	empty := newTopN(0, func(a, b int) bool { return a < b })
+	empty.Push(1)
+	if empty.Len() != 0 {
+		t.Errorf("a zero-size topN kept %d items", empty.Len())
+	}
+}
@@ -0,0 +1,246 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// Limits for s3_tree.
+const (
+	defaultTreeDepth     = 2
+	maxTreeDepth         = 10
+	defaultTreeMaxNodes  = 200
+	maxTreeMaxNodes      = 2000
+	defaultTreeScanLimit = 100000
+	maxTreeScanLimit     = 1000000
+)
+
+// TreeNode is a prefix in a tree view. Objects and Bytes count every object
+// below the prefix, not only those directly under it.
+type TreeNode struct {
+	Name      string      `json:"name"`
+	Prefix    string      `json:"prefix"`
+	Objects   int         `json:"objects"`
+	Bytes     int64       `json:"bytes"`
+	Children  []*TreeNode `json:"children,omitempty"`
+	Truncated bool        `json:"truncated,omitempty"`
+
+	children map[string]*TreeNode
+}
+
+// TreeResult represents the result of building a prefix tree.
+type TreeResult struct {
+	Bucket     string    `json:"bucket"`
+	Prefix     string    `json:"prefix,omitempty"`
+	Depth      int       `json:"depth"`
+	Root       *TreeNode `json:"root"`
+	Text       string    `json:"text"`
+	Nodes      int       `json:"nodes"`
+	Scanned    int       `json:"scanned"`
+	IsPartial  bool      `json:"is_partial"`
+	StopReason string    `json:"stop_reason,omitempty"`
+	ServedBy   string    `json:"served_by,omitempty"`
+}
+
+// registerTreeTool registers the s3_tree tool.
+func (t *Toolkit) registerTreeTool(server *mcp.Server, cfg *toolConfig) {
+	baseHandler := func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		treeInput, ok := input.(TreeInput)
+		if !ok {
+			return ErrorResult("internal error: invalid input type"), nil, nil
+		}
+		return t.handleTree(ctx, req, treeInput)
+	}
+
+	wrappedHandler := t.wrapHandler(ToolTree, baseHandler, cfg)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.toolName(ToolTree),
+		Title:        t.getTitle(ToolTree, cfg),
+		Description:  t.getDescription(ToolTree, cfg),
+		Annotations:  t.getAnnotations(ToolTree, cfg),
+		Icons:        t.getIcons(ToolTree, cfg),
+		OutputSchema: t.getOutputSchema(ToolTree, cfg),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input TreeInput) (*mcp.CallToolResult, any, error) {
+		result, out, err := wrappedHandler(ctx, req, input)
+		return result, typedOutput[TreeResult](out), err
+	})
+}
+
+// handleTree handles the s3_tree tool request.
+func (t *Toolkit) handleTree(ctx context.Context, _ *mcp.CallToolRequest, input TreeInput) (*mcp.CallToolResult, any, error) {
+	if input.Bucket == "" {
+		return ErrorResult("bucket parameter is required"), nil, nil
+	}
+	depth := clampInt(input.Depth, defaultTreeDepth, maxTreeDepth)
+	maxNodes := clampInt(input.MaxNodes, defaultTreeMaxNodes, maxTreeMaxNodes)
+	scanLimit := clampInt(input.ScanLimit, defaultTreeScanLimit, maxTreeScanLimit)
+
+	s3Client, release, err := t.holdClient(input.Connection)
+	if err != nil {
+		return ErrorResult(err.Error()), nil, nil
+	}
+	defer release()
+	if err := t.guardFor(s3Client).checkListPrefix(input.Bucket, input.Prefix); err != nil {
+		return ErrorResultFor(err), nil, nil
+	}
+
+	tree := newPrefixTree(input.Prefix, depth, maxNodes)
+	ctx, servedBy := withServedBy(ctx)
+	walk, err := walkObjects(ctx, s3Client, input.Bucket, input.Prefix, "", scanLimit, func(obj client.ObjectInfo) bool {
+		tree.add(obj)
+		return true
+	})
+	if err != nil {
+		return ErrorResultFor(fmt.Errorf("failed to build tree: %w", err)), nil, nil
+	}
+
+	tree.finish()
+	result := TreeResult{
+		Bucket:   input.Bucket,
+		Prefix:   input.Prefix,
+		Depth:    depth,
+		Root:     tree.root,
+		Text:     renderTree(tree.root),
+		Nodes:    tree.nodes,
+		Scanned:  walk.Scanned,
+		ServedBy: servedBy(),
+	}
+	if walk.Cursor != "" {
+		result.IsPartial = true
+		result.StopReason = StopReasonScanLimit
+	}
+
+	jsonResult, err := JSONResult(result)
+	if err != nil {
+		return ErrorResultf("failed to format result: %v", err), nil, nil
+	}
+	return jsonResult, &result, nil
+}
+
+// prefixTree builds the tree for s3_tree from a flat listing.
+type prefixTree struct {
+	root     *TreeNode
+	prefix   string
+	depth    int
+	maxNodes int
+	nodes    int
+}
+
+// newPrefixTree returns an empty tree rooted at prefix that keeps depth
+// levels below the root and at most maxNodes nodes, counting the root.
+func newPrefixTree(prefix string, depth, maxNodes int) *prefixTree {
+	name := prefix
+	if name == "" {
+		name = "/"
+	}
+	return &prefixTree{
+		root:     &TreeNode{Name: name, Prefix: prefix},
+		prefix:   prefix,
+		depth:    depth,
+		maxNodes: maxNodes,
+		nodes:    1,
+	}
+}
+
+// add counts obj in the root and in each prefix node above it, down to the
+// depth limit. When the node budget is spent, obj is counted in the deepest
+// existing node, which is marked truncated.
+func (p *prefixTree) add(obj client.ObjectInfo) {
+	node := p.root
+	node.Objects++
+	node.Bytes += obj.Size
+
+	segments := strings.Split(strings.TrimPrefix(obj.Key, p.prefix), "/")
+	prefix := p.prefix
+	for level, segment := range segments[:len(segments)-1] {
+		if level >= p.depth {
+			return
+		}
+		name := segment + "/"
+		prefix += name
+		child, ok := node.children[name]
+		if !ok {
+			if p.nodes >= p.maxNodes {
+				node.Truncated = true
+				return
+			}
+			child = &TreeNode{Name: name, Prefix: prefix}
+			if node.children == nil {
+				node.children = make(map[string]*TreeNode)
+			}
+			node.children[name] = child
+			p.nodes++
+		}
+		child.Objects++
+		child.Bytes += obj.Size
+		node = child
+	}
+}
+
+// finish fills each node's sorted Children from the lookup map.
+func (p *prefixTree) finish() {
+	var fill func(n *TreeNode)
+	fill = func(n *TreeNode) {
+		for _, child := range n.children {
+			n.Children = append(n.Children, child)
+			fill(child)
+		}
+		sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+		n.children = nil
+	}
+	fill(p.root)
+}
+
+// renderTree renders root as an indented text tree, one prefix per line with
+// its object count and size.
+func renderTree(root *TreeNode) string {
+	var b strings.Builder
+	b.WriteString(treeLine(root))
+	var render func(n *TreeNode, indent string)
+	render = func(n *TreeNode, indent string) {
+		for i, child := range n.Children {
+			branch, next := "├── ", "│   "
+			if i == len(n.Children)-1 {
+				branch, next = "└── ", "    "
+			}
+			b.WriteString(indent + branch + treeLine(child))
+			render(child, indent+next)
+		}
+	}
+	render(root, "")
+	return b.String()
+}
+
+// treeLine formats one node of a text tree.
+func treeLine(n *TreeNode) string {
+	objects := "objects"
+	if n.Objects == 1 {
+		objects = "object"
+	}
+	more := ""
+	if n.Truncated {
+		more = ", more not shown"
+	}
+	return fmt.Sprintf("%s (%d %s, %s%s)\n", n.Name, n.Objects, objects, formatSize(n.Bytes), more)
+}
+
+// formatSize formats a byte count with binary units, e.g. "1.5 MB".
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit && exp < 4; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTP"[exp])
+}
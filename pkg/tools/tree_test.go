@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+func TestTree(t *testing.T) {
+	objects := []client.ObjectInfo{
+		{Key: "data/readme.md", Size: 100},
+		{Key: "data/2024/01/a.json", Size: 1024},
+		{Key: "data/2024/02/b.json", Size: 2048},
+		{Key: "data/2024/02/deep/c.json", Size: 10},
+		{Key: "data/2025/d.json", Size: 5},
+		{Key: "other/e.json", Size: 1},
+	}
+	mock := NewMockS3Client("test")
+	mock.ListObjectsFunc = pagedListing(objects, 2, nil)
+	toolkit := NewToolkit(mock)
+
+	result, out, _ := toolkit.handleTree(context.Background(), nil, TreeInput{Bucket: "bucket", Prefix: "data/"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	got := out.(*TreeResult)
+
+	want := "data/ (5 objects, 3.1 KB)\n" +
+		"├── 2024/ (3 objects, 3.0 KB)\n" +
+		"│   ├── 01/ (1 object, 1.0 KB)\n" +
+		"│   └── 02/ (2 objects, 2.0 KB)\n" +
+		"└── 2025/ (1 object, 5 B)\n"
+	if got.Text != want {
+		t.Errorf("text =\n%s\nwant\n%s", got.Text, want)
+	}
+	if got.Nodes != 5 || got.Scanned != 5 || got.IsPartial {
+		t.Errorf("nodes = %d scanned = %d partial = %v", got.Nodes, got.Scanned, got.IsPartial)
+	}
+	if node := got.Root.Children[0].Children[1]; node.Prefix != "data/2024/02/" || node.Bytes != 2058 {
+		t.Errorf("node = %+v", node)
+	}
+
+	// The node budget stops new prefixes and marks where children were cut.
+	_, out, _ = toolkit.handleTree(context.Background(), nil, TreeInput{Bucket: "bucket", Prefix: "data/", MaxNodes: 3, ScanLimit: 4})
+	got = out.(*TreeResult)
+	if got.Nodes != 3 || !got.Root.Children[0].Truncated || !got.IsPartial || got.StopReason != StopReasonScanLimit {
+		t.Errorf("budgeted tree = %+v", got)
+	}
+	if !strings.Contains(got.Text, "more not shown") {
+		t.Errorf("text does not mention truncation:\n%s", got.Text)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		0:           "0 B",
+		1023:        "1023 B",
+		1536:        "1.5 KB",
+		5 << 30:     "5.0 GB",
+		3 << 50:     "3.0 PB",
+		1024 << 50:  "1024.0 PB",
+		10<<20 + 99: "10.0 MB",
+	}
+	for n, want := range tests {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
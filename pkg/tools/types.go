@@ -12,13 +12,108 @@ type ListObjectsInput struct {
 	Delimiter         string `json:"delimiter,omitempty" jsonschema_description:"Character used to group keys. Commonly '/' to simulate folders. Common prefixes are returned separately."`
 	MaxKeys           int32  `json:"max_keys,omitempty" jsonschema_description:"Maximum number of objects to return (1-1000). Default: 1000."`
 	ContinuationToken string `json:"continuation_token,omitempty" jsonschema_description:"Token from a previous response to continue listing from where you left off."`
+	SortBy            string `json:"sort_by,omitempty" jsonschema_description:"Sort by 'last_modified', 'size', or 'key'. Scans up to scan_limit keys under the prefix and returns the first max_keys in this order, e.g. the latest files. Cannot be combined with delimiter or continuation_token."`
+	Order             string `json:"order,omitempty" jsonschema_description:"Sort order: 'asc' or 'desc'. Default: 'desc' for last_modified and size, 'asc' for key."`
+	ScanLimit         int    `json:"scan_limit,omitempty" jsonschema_description:"Maximum number of keys to scan when sort_by is set (1-100000). Default: 10000."`
 	Connection        string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
 }
 
+// FindObjectsInput defines the input parameters for the find_objects tool.
+type FindObjectsInput struct {
+	Bucket         string   `json:"bucket" jsonschema_description:"Name of the S3 bucket to search."`
+	Prefix         string   `json:"prefix,omitempty" jsonschema_description:"Only search keys starting with this prefix, e.g. 'data/'."`
+	Pattern        string   `json:"pattern,omitempty" jsonschema_description:"Glob the key must match. '*' and '?' stay within one path segment and '**' spans segments. A pattern without '/' is matched against the last segment, e.g. '*.parquet'."`
+	Regex          string   `json:"regex,omitempty" jsonschema_description:"Regular expression (RE2 syntax) the full key must match."`
+	MinSize        int64    `json:"min_size,omitempty" jsonschema_description:"Minimum object size in bytes."`
+	MaxSize        int64    `json:"max_size,omitempty" jsonschema_description:"Maximum object size in bytes."`
+	ModifiedAfter  string   `json:"modified_after,omitempty" jsonschema_description:"Only objects modified at or after this time (RFC 3339 or YYYY-MM-DD)."`
+	ModifiedBefore string   `json:"modified_before,omitempty" jsonschema_description:"Only objects modified before this time (RFC 3339 or YYYY-MM-DD)."`
+	StorageClass   string   `json:"storage_class,omitempty" jsonschema_description:"Only objects in this storage class, e.g. STANDARD or GLACIER."`
+	Extensions     []string `json:"extensions,omitempty" jsonschema_description:"Only keys ending in one of these extensions, e.g. ['parquet', 'csv']. Case insensitive."`
+	MaxResults     int      `json:"max_results,omitempty" jsonschema_description:"Maximum number of matches to return (1-1000). Default: 100."`
+	ScanLimit      int      `json:"scan_limit,omitempty" jsonschema_description:"Maximum number of keys to examine in this call (1-100000). Default: 10000."`
+	Cursor         string   `json:"cursor,omitempty" jsonschema_description:"Cursor from a previous response to resume the search where it stopped. Use the same bucket and prefix."`
+	Connection     string   `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
+}
+
+// SummarizePrefixInput defines the input parameters for the summarize_prefix tool.
+type SummarizePrefixInput struct {
+	Bucket           string `json:"bucket" jsonschema_description:"Name of the S3 bucket."`
+	Prefix           string `json:"prefix,omitempty" jsonschema_description:"Prefix to summarize, e.g. 'data/'. Empty summarizes the whole bucket."`
+	ScanLimit        int    `json:"scan_limit,omitempty" jsonschema_description:"Maximum number of keys to examine (1-1000000). Default: 100000."`
+	TimeLimitSeconds int    `json:"time_limit_seconds,omitempty" jsonschema_description:"Maximum time to spend walking the prefix, in seconds (max 300). Default: 30."`
+	Top              int    `json:"top,omitempty" jsonschema_description:"Number of largest objects to return (1-100). Default: 10."`
+	Connection       string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
+}
+
+// TreeInput defines the input parameters for the tree tool.
+type TreeInput struct {
+	Bucket     string `json:"bucket" jsonschema_description:"Name of the S3 bucket."`
+	Prefix     string `json:"prefix,omitempty" jsonschema_description:"Prefix at the root of the tree, e.g. 'data/'. Empty starts at the bucket root."`
+	Depth      int    `json:"depth,omitempty" jsonschema_description:"Number of levels below the root to show (1-10). Default: 2."`
+	MaxNodes   int    `json:"max_nodes,omitempty" jsonschema_description:"Maximum number of prefixes in the tree, including the root (1-2000). Default: 200."`
+	ScanLimit  int    `json:"scan_limit,omitempty" jsonschema_description:"Maximum number of keys to examine (1-1000000). Default: 100000."`
+	Connection string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
+}
+
+// GrepInput defines the input parameters for the grep tool.
+type GrepInput struct {
+	Bucket         string `json:"bucket" jsonschema_description:"Name of the S3 bucket to search."`
+	Prefix         string `json:"prefix,omitempty" jsonschema_description:"Only search objects with keys starting with this prefix, e.g. 'logs/2024/'."`
+	Pattern        string `json:"pattern" jsonschema_description:"Text to search for. Treated as a literal string unless regex is true."`
+	Regex          bool   `json:"regex,omitempty" jsonschema_description:"Treat pattern as a regular expression (RE2 syntax)."`
+	IgnoreCase     bool   `json:"ignore_case,omitempty" jsonschema_description:"Match without regard to case."`
+	Include        string `json:"include,omitempty" jsonschema_description:"Only search keys matching this glob, e.g. '*.log'. A glob without '/' is matched against the last segment of the key."`
+	ContextLines   int    `json:"context_lines,omitempty" jsonschema_description:"Number of lines to return before and after each match (0-10). Default: 0."`
+	MaxMatches     int    `json:"max_matches,omitempty" jsonschema_description:"Maximum number of matches to return (1-1000). Default: 100."`
+	MaxObjectBytes int64  `json:"max_object_bytes,omitempty" jsonschema_description:"Skip objects larger than this many bytes. Cannot exceed the connection's retrieval size limit, which is the default."`
+	MaxTotalBytes  int64  `json:"max_total_bytes,omitempty" jsonschema_description:"Stop after downloading this many bytes in total (max 1 GB). Default: 100 MB."`
+	ScanLimit      int    `json:"scan_limit,omitempty" jsonschema_description:"Maximum number of keys to examine (1-100000). Default: 10000."`
+	Concurrency    int    `json:"concurrency,omitempty" jsonschema_description:"Number of objects to search at once (1-16). Default: 4."`
+	Connection     string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
+}
+
+// ListArchiveInput defines the input parameters for the list_archive tool.
+type ListArchiveInput struct {
+	Bucket     string `json:"bucket" jsonschema_description:"Name of the S3 bucket containing the archive."`
+	Key        string `json:"key" jsonschema_description:"Key (path) of the zip or tar archive, e.g. 'releases/app.zip' or 'backups/site.tar.gz'."`
+	Format     string `json:"format,omitempty" jsonschema_description:"Archive format: 'zip' or 'tar'. Default: detected from the key's extension or the archive's leading bytes."`
+	MaxEntries int    `json:"max_entries,omitempty" jsonschema_description:"Maximum number of entries to return (1-10000). Default: 1000."`
+	Connection string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
+}
+
+// GetArchiveEntryInput defines the input parameters for the get_archive_entry tool.
+type GetArchiveEntryInput struct {
+	Bucket     string `json:"bucket" jsonschema_description:"Name of the S3 bucket containing the archive."`
+	Key        string `json:"key" jsonschema_description:"Key (path) of the zip or tar archive."`
+	Entry      string `json:"entry" jsonschema_description:"Name of the entry to retrieve, as returned by s3_list_archive, e.g. 'docs/README.md'."`
+	Format     string `json:"format,omitempty" jsonschema_description:"Archive format: 'zip' or 'tar'. Default: detected from the key's extension or the archive's leading bytes."`
+	Connection string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
+}
+
+// InspectParquetInput defines the input parameters for the inspect_parquet tool.
+type InspectParquetInput struct {
+	Bucket     string `json:"bucket" jsonschema_description:"Name of the S3 bucket containing the Parquet file."`
+	Key        string `json:"key" jsonschema_description:"Key (path) of the Parquet file, e.g. 'lake/events/part-00000.parquet'."`
+	Rows       int    `json:"rows,omitempty" jsonschema_description:"Number of rows to decode from the start of the file and return as JSON (0-100). Default: 0."`
+	Connection string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
+}
+
+// PreviewTableInput defines the input parameters for the preview_table tool.
+type PreviewTableInput struct {
+	Bucket     string `json:"bucket" jsonschema_description:"Name of the S3 bucket containing the file."`
+	Key        string `json:"key" jsonschema_description:"Key (path) of the delimited file, e.g. 'exports/users.csv' or 'logs/events.tsv.gz'."`
+	Rows       int    `json:"rows,omitempty" jsonschema_description:"Number of sample rows to return (1-100). Default: 10."`
+	Delimiter  string `json:"delimiter,omitempty" jsonschema_description:"Field delimiter, a single character such as ',' or ';', or '\\t' for a tab. Default: detected from the content."`
+	Header     string `json:"header,omitempty" jsonschema_description:"Whether the first row is a header: 'present' or 'absent'. Default: detected from the content."`
+	Connection string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
+}
+
 // GetObjectInput defines the input parameters for the get_object tool.
 type GetObjectInput struct {
 	Bucket     string `json:"bucket" jsonschema_description:"Name of the S3 bucket containing the object."`
 	Key        string `json:"key" jsonschema_description:"Key (path) of the object to retrieve."`
+	Raw        bool   `json:"raw,omitempty" jsonschema_description:"Return gzip, zstd, and bzip2 objects as stored instead of decompressing them."`
 	Connection string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection to use. If not specified, uses the default connection."`
 }
 
@@ -68,5 +163,30 @@ type PresignURLInput struct {
 
 // ListConnectionsInput defines the input parameters for the list_connections tool.
 type ListConnectionsInput struct {
-	// No parameters required
+	Probe bool `json:"probe,omitempty" jsonschema_description:"Also probe connections that have not been used yet, creating their clients, and probe every connection for optional features. By default unused connections report only their last cached health check."`
+}
+
+// AddConnectionInput defines the input parameters for the add_connection tool.
+type AddConnectionInput struct {
+	Name    string         `json:"name" jsonschema_description:"Name of the connection to add or replace."`
+	Config  map[string]any `json:"config" jsonschema_description:"Connection settings: region, endpoint, presign_endpoint, access_key_id, secret_access_key, session_token, use_path_style, provider, account_id, timeout, read_only, allowed_buckets, allowed_prefixes, and health_check_bucket. Keys must be literal values and are required with a custom endpoint, e.g. {\"endpoint\": \"http://minio:9000\", \"use_path_style\": true, \"access_key_id\": \"reader\", \"secret_access_key\": \"...\"}."`
+	Replace bool           `json:"replace,omitempty" jsonschema_description:"Set to true to replace an existing connection with the same name."`
+	Persist bool           `json:"persist,omitempty" jsonschema_description:"Set to true to also save the change to the server's configuration file."`
+}
+
+// RemoveConnectionInput defines the input parameters for the remove_connection tool.
+type RemoveConnectionInput struct {
+	Name    string `json:"name" jsonschema_description:"Name of the connection to remove."`
+	Persist bool   `json:"persist,omitempty" jsonschema_description:"Set to true to also save the change to the server's configuration file."`
+}
+
+// TestConnectionInput defines the input parameters for the test_connection tool.
+type TestConnectionInput struct {
+	Name   string         `json:"name,omitempty" jsonschema_description:"Name of the connection to test. Used as the name of the candidate when config is set."`
+	Config map[string]any `json:"config,omitempty" jsonschema_description:"Candidate connection settings to test without adding them, with the same fields as add_connection. If not specified, tests the named existing connection."`
+}
+
+// GetQuotaInput defines the input parameters for the get_quota tool.
+type GetQuotaInput struct {
+	Connection string `json:"connection,omitempty" jsonschema_description:"Name of the S3 connection whose quota to report. If not specified, uses the default connection."`
 }
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/txn2/mcp-s3/pkg/client"
+)
+
+// walkPageSize is the number of keys requested per ListObjectsV2 call.
+const walkPageSize = 1000
+
+// Reasons a tool stopped walking a prefix before the end of the listing.
+const (
+	StopReasonScanLimit  = "scan_limit"
+	StopReasonTimeLimit  = "time_limit"
+	StopReasonByteBudget = "byte_budget"
+	StopReasonMaxMatches = "max_matches"
+	StopReasonMaxEntries = "max_entries"
+)
+
+// listCursor records where a walk of a bucket prefix stopped, so a later call
+// can resume it. Token is the continuation token of the page to list again and
+// After is the last key already handled on that page.
+type listCursor struct {
+	Bucket string `json:"b"`
+	Prefix string `json:"p,omitempty"`
+	Token  string `json:"t,omitempty"`
+	After  string `json:"a,omitempty"`
+}
+
+// encode returns the cursor as an opaque string.
+func (c listCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor returned by an earlier walk of the same bucket
+// and prefix. An empty string starts a new walk.
+func decodeCursor(s, bucket, prefix string) (listCursor, error) {
+	cursor := listCursor{Bucket: bucket, Prefix: prefix}
+	if s == "" {
+		return cursor, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil {
+		return listCursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidParameter)
+	}
+	if cursor.Bucket != bucket || cursor.Prefix != prefix {
+		return listCursor{}, fmt.Errorf("%w: cursor belongs to a different bucket or prefix", ErrInvalidParameter)
+	}
+	return cursor, nil
+}
+
+// walkResult reports how far a walk got.
+type walkResult struct {
+	// Scanned is the number of keys passed to the visit function.
+	Scanned int
+
+	// Cursor resumes the walk. It is empty when every key was visited.
+	Cursor string
+}
+
+// walkObjects lists the keys under prefix in order, starting from cursor, and
+// calls visit for each one. It stops when the listing ends, after budget keys,
+// or when visit returns false, and returns a cursor for the keys not yet
+// visited.
+func walkObjects(
+	ctx context.Context, s3Client S3Client, bucket, prefix, cursor string, budget int,
+	visit func(obj client.ObjectInfo) bool,
+) (walkResult, error) {
+	cur, err := decodeCursor(cursor, bucket, prefix)
+	if err != nil {
+		return walkResult{}, err
+	}
+
+	var result walkResult
+	for {
+		page, err := s3Client.ListObjects(ctx, bucket, prefix, "", walkPageSize, cur.Token)
+		if err != nil {
+			return result, err
+		}
+		more := page.IsTruncated && page.NextContinueToken != ""
+
+		for i, obj := range page.Objects {
+			if cur.After != "" && obj.Key <= cur.After {
+				continue
+			}
+			result.Scanned++
+			if visit(obj) && result.Scanned < budget {
+				continue
+			}
+			switch {
+			case i < len(page.Objects)-1:
+				result.Cursor = listCursor{Bucket: bucket, Prefix: prefix, Token: cur.Token, After: obj.Key}.encode()
+			case more:
+				result.Cursor = listCursor{Bucket: bucket, Prefix: prefix, Token: page.NextContinueToken}.encode()
+			}
+			return result, nil
+		}
+
+		if !more {
+			return result, nil
+		}
+		cur = listCursor{Bucket: bucket, Prefix: prefix, Token: page.NextContinueToken}
+	}
+}
+
+// objectResult converts a listed object for tool output.
+func objectResult(obj client.ObjectInfo) ObjectResult {
+	result := ObjectResult{
+		Key:          obj.Key,
+		Size:         obj.Size,
+		ETag:         obj.ETag,
+		StorageClass: obj.StorageClass,
+	}
+	if !obj.LastModified.IsZero() {
+		result.LastModified = obj.LastModified.Format("2006-01-02T15:04:05Z")
+	}
+	return result
+}